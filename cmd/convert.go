@@ -0,0 +1,204 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mrutkows/go-skeleton/convert"
+	"github.com/mrutkows/go-skeleton/cyclonedx"
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/sbom"
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/mrutkows/go-skeleton/spdx"
+	"github.com/mrutkows/go-skeleton/utils"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FLAG_CONVERT_TO   = "to"
+	FLAG_SPEC_VERSION = "spec-version"
+)
+
+// Conversion target formats (i.e., `--to` values)
+const (
+	CONVERT_TO_CYCLONEDX      = "cyclonedx" // i.e., CycloneDX JSON
+	CONVERT_TO_CYCLONEDX_JSON = sbom.FORMAT_CYCLONEDX_JSON
+	CONVERT_TO_CYCLONEDX_XML  = sbom.FORMAT_CYCLONEDX_XML
+	CONVERT_TO_SPDX_JSON      = sbom.FORMAT_SPDX_JSON
+	CONVERT_TO_SPDX_TV        = sbom.FORMAT_SPDX_TV
+)
+
+var ConvertToFormats = []string{CONVERT_TO_CYCLONEDX, CONVERT_TO_CYCLONEDX_JSON, CONVERT_TO_CYCLONEDX_XML, CONVERT_TO_SPDX_JSON, CONVERT_TO_SPDX_TV}
+
+func init() {
+	convertCmd.Flags().StringVar(&utils.Flags.OutputFormat, FLAG_CONVERT_TO, CONVERT_TO_CYCLONEDX, "output format: cyclonedx (i.e., cyclonedx-json), cyclonedx-json or cyclonedx-xml (from SPDX or CycloneDX), spdx-json or spdx-tv (from CycloneDX)")
+	convertCmd.Flags().StringVar(&utils.Flags.SpecVersion, FLAG_SPEC_VERSION, cyclonedx.LATEST_SPEC_VERSION, "output CycloneDX spec. version: 1.2, 1.3, 1.4, 1.5, 1.6 (SPDX output is always 2.3)")
+	rootCmd.AddCommand(convertCmd)
+}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert -i <input-sbom> -o <output-sbom> --to <format>",
+	Short: "convert input file to another SBOM format.",
+	Long:  "convert input file to another SBOM format; fields without an equivalent are kept (as properties) and listed in a conversion report.",
+	RunE:  convertCmdImpl,
+}
+
+func convertCmdImpl(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("args", args))
+	report, err := Convert(ctx)
+	if err == nil {
+		// the report goes to stderr as the converted document may go to stdout
+		if reportErr := report.Write(os.Stderr); reportErr != nil {
+			err = NewIOError("unable to write conversion report: %w", reportErr)
+		}
+	}
+	logger.Exit(err)
+	return err
+}
+
+// Convert loads the document named by the input file flag and writes it,
+// converted to the output format, to the output file (or stdout).
+func Convert(ctx context.Context) (report *convert.Report, err error) {
+	logger := commandLogger(ctx)
+	logger.Enter()
+	defer func() { logger.Exit(err) }()
+
+	buffer, detection, err := readInput(ctx)
+	if err != nil {
+		return
+	}
+	logger.Trace(fmt.Sprintf("Document format: %s", detection))
+
+	switch utils.Flags.OutputFormat {
+	case CONVERT_TO_CYCLONEDX, CONVERT_TO_CYCLONEDX_JSON, CONVERT_TO_CYCLONEDX_XML:
+		var bom *cyclonedx.Bom
+		if bom, report, err = toCycloneDX(ctx, buffer, detection); err != nil {
+			return
+		}
+		if utils.Flags.OutputFormat == CONVERT_TO_CYCLONEDX_XML {
+			err = writeOutput(ctx, bom.WriteXML)
+		} else {
+			err = writeOutput(ctx, bom.WriteJSON)
+		}
+	case CONVERT_TO_SPDX_JSON, CONVERT_TO_SPDX_TV:
+		var bom *cyclonedx.Bom
+		if bom, err = parseCycloneDX(ctx, buffer, detection); err != nil {
+			return
+		}
+		var document *spdx.Document
+		tool := fmt.Sprintf("%s-%s", utils.Flags.Project, utils.Flags.Version)
+		if document, report, err = convert.CycloneDXToSpdx(bom, tool); err != nil {
+			return
+		}
+		if utils.Flags.OutputFormat == CONVERT_TO_SPDX_JSON {
+			err = writeOutput(ctx, document.WriteJSON)
+		} else {
+			err = writeOutput(ctx, document.WriteTagValue)
+		}
+	default:
+		err = NewUsageError("unsupported output format: `%s` (expected one of: %s)",
+			utils.Flags.OutputFormat, strings.Join(ConvertToFormats, ", "))
+	}
+	return
+}
+
+// Convert an SPDX document or (another serialization or version of) a CycloneDX BOM
+func toCycloneDX(ctx context.Context, buffer []byte, detection sbom.Detection) (bom *cyclonedx.Bom, report *convert.Report, err error) {
+	if detection.Family() == schema.FORMAT_CYCLONEDX {
+		if bom, err = parseCycloneDX(ctx, buffer, detection); err != nil {
+			return
+		}
+		if report, err = convert.CycloneDXToVersion(bom, utils.Flags.SpecVersion); err != nil {
+			err = &UsageError{err}
+		}
+		return
+	}
+
+	var document *spdx.Document
+	if document, err = parseSpdx(ctx, buffer, detection); err != nil {
+		return
+	}
+	if bom, report, err = convert.SpdxToCycloneDX(document, utils.Flags.SpecVersion); err != nil {
+		err = &UsageError{err}
+	}
+	return
+}
+
+func parseSpdx(ctx context.Context, buffer []byte, detection sbom.Detection) (document *spdx.Document, err error) {
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("format", detection.Format))
+	defer func() { logger.Exit(err) }()
+
+	switch detection.Format {
+	case sbom.FORMAT_SPDX_TV:
+		document, err = spdx.ParseTagValue(bytes.NewReader(buffer))
+	case sbom.FORMAT_SPDX_JSON:
+		document, err = spdx.ParseJSON(bytes.NewReader(buffer))
+	case sbom.FORMAT_SPDX_YAML:
+		document, err = spdx.ParseYAML(bytes.NewReader(buffer))
+	default:
+		return nil, NewUsageError("conversion to CycloneDX is not supported for format: `%s`", detection.Format)
+	}
+	if err != nil {
+		return nil, NewParseError("unable to parse document: %w", err)
+	}
+	return document, nil
+}
+
+func parseCycloneDX(ctx context.Context, buffer []byte, detection sbom.Detection) (bom *cyclonedx.Bom, err error) {
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("format", detection.Format))
+	defer func() { logger.Exit(err) }()
+
+	switch detection.Format {
+	case sbom.FORMAT_CYCLONEDX_JSON:
+		bom, err = cyclonedx.ParseJSON(bytes.NewReader(buffer))
+	case sbom.FORMAT_CYCLONEDX_XML:
+		bom, err = cyclonedx.ParseXML(bytes.NewReader(buffer))
+	default:
+		return nil, NewUsageError("conversion to SPDX is not supported for format: `%s`", detection.Format)
+	}
+	if err != nil {
+		return nil, NewParseError("unable to parse document: %w", err)
+	}
+	return bom, nil
+}
+
+// Write the (converted) document to the output file (or stdout)
+func writeOutput(ctx context.Context, write func(writer io.Writer) error) error {
+	output, err := createOutput(ctx)
+	if err != nil {
+		return err
+	}
+	err = write(output)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return NewIOError("unable to write output: %w", err)
+	}
+	return nil
+}
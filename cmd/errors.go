@@ -0,0 +1,98 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Process exit codes; these values are part of the command line contract
+// (i.e., scripts depend upon them) and MUST NOT change once released.
+const (
+	EXIT_SUCCESS            = 0 // command completed successfully (e.g., SBOM is valid)
+	EXIT_ERROR              = 1 // unexpected (internal) error
+	EXIT_USAGE_ERROR        = 2 // invalid command, flag or flag value
+	EXIT_IO_ERROR           = 3 // unable to read input or write output (e.g., file not found)
+	EXIT_PARSE_ERROR        = 4 // input could not be parsed (e.g., invalid JSON, unknown format)
+	EXIT_VALIDATION_FAILURE = 5 // input was parsed, but is not valid against its schema
+	EXIT_POLICY_FAILURE     = 6 // input is valid, but violates a configured policy
+)
+
+// Errors returned from commands that map to a specific exit code
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+type UsageError struct{ Err error }
+type IOError struct{ Err error }
+type ParseError struct{ Err error }
+type ValidationFailure struct{ Err error }
+type PolicyFailure struct{ Err error }
+
+func (err *UsageError) Error() string        { return err.Err.Error() }
+func (err *IOError) Error() string           { return err.Err.Error() }
+func (err *ParseError) Error() string        { return err.Err.Error() }
+func (err *ValidationFailure) Error() string { return err.Err.Error() }
+func (err *PolicyFailure) Error() string     { return err.Err.Error() }
+
+func (err *UsageError) Unwrap() error        { return err.Err }
+func (err *IOError) Unwrap() error           { return err.Err }
+func (err *ParseError) Unwrap() error        { return err.Err }
+func (err *ValidationFailure) Unwrap() error { return err.Err }
+func (err *PolicyFailure) Unwrap() error     { return err.Err }
+
+func (err *UsageError) ExitCode() int        { return EXIT_USAGE_ERROR }
+func (err *IOError) ExitCode() int           { return EXIT_IO_ERROR }
+func (err *ParseError) ExitCode() int        { return EXIT_PARSE_ERROR }
+func (err *ValidationFailure) ExitCode() int { return EXIT_VALIDATION_FAILURE }
+func (err *PolicyFailure) ExitCode() int     { return EXIT_POLICY_FAILURE }
+
+func NewUsageError(format string, a ...interface{}) error {
+	return &UsageError{fmt.Errorf(format, a...)}
+}
+
+func NewIOError(format string, a ...interface{}) error {
+	return &IOError{fmt.Errorf(format, a...)}
+}
+
+func NewParseError(format string, a ...interface{}) error {
+	return &ParseError{fmt.Errorf(format, a...)}
+}
+
+func NewValidationFailure(format string, a ...interface{}) error {
+	return &ValidationFailure{fmt.Errorf(format, a...)}
+}
+
+func NewPolicyFailure(format string, a ...interface{}) error {
+	return &PolicyFailure{fmt.Errorf(format, a...)}
+}
+
+// ExitCode returns the process exit code for an error returned by Execute();
+// errors outside the taxonomy above are treated as internal errors.
+func ExitCode(err error) int {
+	if err == nil {
+		return EXIT_SUCCESS
+	}
+	var exitCoder ExitCoder
+	if errors.As(err, &exitCoder) {
+		return exitCoder.ExitCode()
+	}
+	return EXIT_ERROR
+}
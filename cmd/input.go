@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/mrutkows/go-skeleton/sbom"
+	"github.com/mrutkows/go-skeleton/utils"
+)
+
+// readInput reads the file named by the `-i` flag and detects its format
+// and version; an explicit `--input-format` takes precedence over detection.
+// All commands that read `-i` should use this function.
+func readInput(ctx context.Context) (buffer []byte, detection sbom.Detection, err error) {
+	logger := commandLogger(ctx)
+	logger.Enter()
+	defer func() { logger.Exit(detection, err) }()
+
+	if utils.Flags.InputFile == "" {
+		return nil, detection, NewUsageError("no input file specified; use `--%s`", FLAG_FILENAME_INPUT)
+	}
+
+	buffer, err = os.ReadFile(utils.Flags.InputFile)
+	if err != nil {
+		return nil, detection, NewIOError("unable to read input file: %w", err)
+	}
+
+	if utils.Flags.InputFormat != "" {
+		if detection, err = sbom.DetectAs(buffer, utils.Flags.InputFormat); err != nil {
+			return nil, detection, &UsageError{err}
+		}
+	} else if detection, err = sbom.Detect(buffer); err != nil {
+		return nil, detection, NewParseError("%w; use `--%s` to specify one of: %v", err, FLAG_FORMAT_INPUT, sbom.Formats)
+	}
+	return buffer, detection, nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/utils"
+	"github.com/spf13/cobra"
+)
+
+// Flags shared by all list commands
+const (
+	FLAG_LIST_FORMAT  = "format"
+	FLAG_LIST_COLUMNS = "columns"
+	FLAG_LIST_SORT    = "sort"
+)
+
+// addListFlags declares the flags all list commands share (i.e., call it
+// from the command's init())
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&utils.Flags.ListFormat, FLAG_LIST_FORMAT, log.FORMAT_TEXT, "list format: text, markdown, csv, tsv, json")
+	cmd.Flags().StringSliceVar(&utils.Flags.ListColumns, FLAG_LIST_COLUMNS, nil, "columns to list, in order (e.g., `format,version`); default: all")
+	cmd.Flags().StringSliceVar(&utils.Flags.ListSort, FLAG_LIST_SORT, nil, "columns to sort by (e.g., `format,-version`; `-` sorts descending)")
+}
+
+// writeList writes the rows (a slice of structs) as a table, per the list
+// flags, to the output file (`-o`) or, if none was given, to stdout; text
+// tables written to a terminal are fit to its width.
+func writeList(ctx context.Context, rows interface{}) (err error) {
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("format", utils.Flags.ListFormat))
+	defer func() { logger.Exit(err) }()
+
+	options := log.TableOptions{
+		Columns: utils.Flags.ListColumns,
+		SortBy:  utils.Flags.ListSort,
+	}
+	if utils.Flags.OutputFile == "" {
+		options.MaxWidth = log.TerminalWidth(os.Stdout)
+	}
+	// Note: all (table) format errors are due to flags (e.g., an unknown column)
+	table, err := log.FormatTable(rows, utils.Flags.ListFormat, options)
+	if err != nil {
+		return &UsageError{err}
+	}
+
+	output, err := createOutput(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(output, strings.NewReader(table))
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return NewIOError("unable to write list: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mrutkows/go-skeleton/utils"
+)
+
+// Wraps stdout so that closing the output never closes the process' stdout
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// createOutput returns a writer for the file named by the `-o` flag;
+// if no output file was given, command output is written to stdout.
+// Callers must always Close() the returned writer.
+func createOutput(ctx context.Context) (io.WriteCloser, error) {
+	if utils.Flags.OutputFile == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	file, err := os.Create(utils.Flags.OutputFile)
+	if err != nil {
+		return nil, NewIOError("unable to create output file: %w", err)
+	}
+	commandLogger(ctx).Trace(fmt.Sprintf("Writing output to: `%s`", utils.Flags.OutputFile))
+	return file, nil
+}
@@ -0,0 +1,280 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/utils"
+)
+
+// Supported validation report formats
+const (
+	REPORT_FORMAT_TEXT  = "text"
+	REPORT_FORMAT_JSON  = "json"
+	REPORT_FORMAT_SARIF = "sarif"
+	REPORT_FORMAT_JUNIT = "junit"
+)
+
+const (
+	SARIF_VERSION = "2.1.0"
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type reportWriter func(io.Writer, []ValidationResult) error
+
+var reportWriters = map[string]reportWriter{
+	REPORT_FORMAT_TEXT:  writeTextReport,
+	REPORT_FORMAT_JSON:  writeJsonReport,
+	REPORT_FORMAT_SARIF: writeSarifReport,
+	REPORT_FORMAT_JUNIT: writeJunitReport,
+}
+
+// writeValidationReport writes the results in the requested format
+// to the output file (`-o`) or, if none was given, to stdout.
+func writeValidationReport(ctx context.Context, format string, results ...ValidationResult) (err error) {
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("format", format), log.Arg("results", len(results)))
+	defer func() { logger.Exit(err) }()
+
+	writeReport, ok := reportWriters[format]
+	if !ok {
+		return NewUsageError("unsupported report format: `%s` (expected one of: text, json, sarif, junit)", format)
+	}
+
+	output, err := createOutput(ctx)
+	if err != nil {
+		return err
+	}
+	// Note: (only) once closed is the report (file) known to be complete
+	err = writeReport(output, results)
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return NewIOError("unable to write report: %w", err)
+	}
+	return nil
+}
+
+// Human-readable summary (one line per document followed by its errors)
+func writeTextReport(output io.Writer, results []ValidationResult) (err error) {
+	for _, result := range results {
+		if _, err = fmt.Fprintf(output, "Document %s: valid=[%t]\n", result.Document, result.Valid); err != nil {
+			return
+		}
+		for _, schemaError := range result.Errors {
+			if _, err = fmt.Fprintf(output, "  %s\n", schemaError); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func writeJsonReport(output io.Writer, results []ValidationResult) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// ------------------------------------------------------------------------
+// SARIF (Static Analysis Results Interchange Format) v2.1.0
+// Note: only the subset of properties needed to report schema errors
+// ------------------------------------------------------------------------
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleId     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// One SARIF rule per validator and keyword (e.g., "json-schema/required"); one result per schema error
+func writeSarifReport(output io.Writer, results []ValidationResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:    utils.Flags.Project,
+			Version: utils.Flags.Version,
+			Rules:   []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	rules := make(map[string]bool)
+	for _, result := range results {
+		for _, schemaError := range result.Errors {
+			ruleId := schemaError.Source + "/" + schemaError.Keyword
+			if !rules[ruleId] {
+				rules[ruleId] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+					Id:               ruleId,
+					ShortDescription: sarifMessage{Text: ruleDescription(schemaError)},
+				})
+			}
+
+			sarifResult := sarifResult{
+				RuleId:  ruleId,
+				Level:   "error",
+				Message: sarifMessage{Text: schemaError.Message},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: result.Document}},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: schemaError.Pointer}},
+				}},
+				Properties: map[string]interface{}{"pointer": schemaError.Pointer},
+			}
+			if schemaError.Value != nil {
+				sarifResult.Properties["value"] = schemaError.Value
+			}
+			run.Results = append(run.Results, sarifResult)
+		}
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: SARIF_SCHEMA, Version: SARIF_VERSION, Runs: []sarifRun{run}})
+}
+
+// e.g., "JSON schema `required` constraint" or "SPDX `element-reference` rule"
+func ruleDescription(schemaError SchemaError) string {
+	switch schemaError.Source {
+	case VALIDATOR_XSD:
+		return fmt.Sprintf("XML schema (XSD) `%s` constraint", schemaError.Keyword)
+	case VALIDATOR_SPDX:
+		return fmt.Sprintf("SPDX `%s` rule", schemaError.Keyword)
+	}
+	return fmt.Sprintf("JSON schema `%s` constraint", schemaError.Keyword)
+}
+
+// ------------------------------------------------------------------------
+// JUnit XML: one test case per document; schema errors are listed
+// as the body of a single failure element
+// ------------------------------------------------------------------------
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJunitReport(output io.Writer, results []ValidationResult) error {
+	suite := junitTestSuite{Name: "validate", Tests: len(results)}
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.Document,
+			ClassName: strings.TrimSpace(fmt.Sprintf("%s %s", result.Format, result.Version)),
+		}
+		if !result.Valid {
+			suite.Failures++
+			var sb strings.Builder
+			for _, schemaError := range result.Errors {
+				sb.WriteString(fmt.Sprintf("%s\n", schemaError))
+			}
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d schema error(s)", len(result.Errors)),
+				Type:    "schema",
+				Text:    sb.String(),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if _, err := io.WriteString(output, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(output)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(output, "\n")
+	return err
+}
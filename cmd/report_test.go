@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Rules are identified (and described) by the validator (i.e., the source) and keyword of their errors
+func TestSarifRules(t *testing.T) {
+	results := []ValidationResult{{
+		Document: "bom.json",
+		Errors: []SchemaError{
+			{Pointer: "/components/0", Keyword: "required", Source: VALIDATOR_JSON_SCHEMA, Message: "name is required"},
+			{Pointer: "/components/1/type", Keyword: "enum", Source: VALIDATOR_JSON_SCHEMA, Message: "type must be one of", Value: "unknown"},
+			{Pointer: "/bom/components", Keyword: "required", Source: VALIDATOR_XSD, Message: "name is required"},
+			{Pointer: "/relationships/0", Keyword: "element-reference", Source: VALIDATOR_SPDX, Message: "unknown element"},
+			{Pointer: "/metadata", Keyword: "required", Source: VALIDATOR_JSON_SCHEMA, Message: "timestamp is required", Value: 1},
+		},
+	}}
+
+	var output bytes.Buffer
+	assert.NoError(t, writeSarifReport(&output, results))
+	var report sarifLog
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &report))
+	assert.Equal(t, SARIF_VERSION, report.Version)
+	assert.Equal(t, []sarifRule{
+		{"json-schema/required", sarifMessage{"JSON schema `required` constraint"}},
+		{"json-schema/enum", sarifMessage{"JSON schema `enum` constraint"}},
+		{"xsd/required", sarifMessage{"XML schema (XSD) `required` constraint"}},
+		{"spdx/element-reference", sarifMessage{"SPDX `element-reference` rule"}},
+	}, report.Runs[0].Tool.Driver.Rules)
+
+	var ruleIds []string
+	for _, result := range report.Runs[0].Results {
+		ruleIds = append(ruleIds, result.RuleId)
+	}
+	assert.Equal(t, []string{"json-schema/required", "json-schema/enum", "xsd/required", "spdx/element-reference", "json-schema/required"}, ruleIds)
+	last := report.Runs[0].Results[4]
+	assert.Equal(t, "bom.json", last.Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+	assert.Equal(t, "/metadata", last.Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(t, map[string]interface{}{"pointer": "/metadata", "value": float64(1)}, last.Properties)
+}
+
+func TestJunitReport(t *testing.T) {
+	results := []ValidationResult{
+		{Document: "valid.json", Format: "cyclonedx-json", Version: "1.5", Valid: true},
+		{Document: "invalid.json", Format: "spdx-json", Version: "2.3", Errors: []SchemaError{
+			{Pointer: "/packages/0", Keyword: "required", Source: VALIDATOR_JSON_SCHEMA, Message: "name is required"},
+		}},
+	}
+
+	var output bytes.Buffer
+	assert.NoError(t, writeJunitReport(&output, results))
+	var report junitTestSuites
+	assert.NoError(t, xml.Unmarshal(output.Bytes(), &report))
+	suite := report.Suites[0]
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, "cyclonedx-json 1.5", suite.TestCases[0].ClassName)
+	assert.Nil(t, suite.TestCases[0].Failure)
+	if assert.NotNil(t, suite.TestCases[1].Failure) {
+		assert.Equal(t, "1 schema error(s)", suite.TestCases[1].Failure.Message)
+		assert.Equal(t, "/packages/0: name is required (required)\n", suite.TestCases[1].Failure.Text)
+	}
+}
+
+func TestWriteValidationReportFormat(t *testing.T) {
+	err := writeValidationReport(context.Background(), "html")
+	var usageError *UsageError
+	assert.True(t, errors.As(err, &usageError), err)
+}
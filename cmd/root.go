@@ -18,7 +18,10 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/mrutkows/go-skeleton/log"
@@ -37,17 +40,35 @@ const (
 	FLAG_FILENAME_INPUT_SHORT  = "i"
 	FLAG_FILENAME_OUTPUT       = "output-file"
 	FLAG_FILENAME_OUTPUT_SHORT = "o"
+	FLAG_FORMAT_INPUT          = "input-format"
+	FLAG_LOG_FORMAT            = "log-format"
+	FLAG_COLOR                 = "color"
+	FLAG_LOG_FILE              = "log-file"
+	FLAG_LOG_MAX_SIZE          = "log-max-size"
+	FLAG_LOG_MAX_BACKUPS       = "log-max-backups"
+	FLAG_LOG_COMPRESS          = "log-compress"
 )
 
 var rootCmd = &cobra.Command{
 	Use:           utils.Flags.Project,
-	SilenceErrors: false, // TODO: investigate if we should use
-	SilenceUsage:  false, // TODO: investigate if we should use
+	SilenceErrors: true, // errors are logged (once) by Execute()
+	SilenceUsage:  true, // usage is only displayed for usage errors
 	Short:         "Software Bill-of-Materials (SBOM) base utility.",
 	Long:          "This utility serves as centralized command line interface into various Software Bill-of-Materials (SBOM) helper utilities.",
+	Args:          rootCmdArgs,
 	RunE:          RootCmdImpl,
+	// Note: flags are only validated once parsed (i.e., after initConfig())
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return configError
+	},
 }
 
+// Any error found (in the flags) by initConfig(); it cannot return errors itself
+var configError error
+
+// The log file (`--log-file`), if any; closed by Execute()
+var logFile *log.RotatingFile
+
 // initialize the module; primarily, initialize cobra
 func init() {
 	ProjectLogger = log.NewLogger(log.TRACE)
@@ -56,13 +77,25 @@ func init() {
 	// Tell Cobra what our Cobra "init" call back method is
 	cobra.OnInitialize(initConfig)
 
+	// Flag parsing errors are usage errors (for all commands)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{err}
+	})
+
 	// Declare top-level, persistent flags and where to place the post-parse values
 	// TODO: move command help strings to (centralized) constants for better editing/translation across all files
 	//rootCmd.PersistentFlags().BoolVarP(nil, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().BoolVarP(&utils.Flags.Trace, FLAG_TRACE, FLAG_TRACE_SHORT, false, "enable trace logging")
 	rootCmd.PersistentFlags().BoolVarP(&utils.Flags.Debug, FLAG_DEBUG, FLAG_DEBUG_SHORT, false, "enable debug logging")
 	rootCmd.PersistentFlags().StringVarP(&utils.Flags.InputFile, FLAG_FILENAME_INPUT, FLAG_FILENAME_INPUT_SHORT, "", "input filename")
+	rootCmd.PersistentFlags().StringVarP(&utils.Flags.InputFormat, FLAG_FORMAT_INPUT, "", "", "input format (overrides detection): cyclonedx-json, cyclonedx-xml, spdx-json, spdx-tv, spdx-yaml, spdx-rdf")
 	rootCmd.PersistentFlags().StringVarP(&utils.Flags.OutputFile, FLAG_FILENAME_OUTPUT, FLAG_FILENAME_OUTPUT_SHORT, "", "output filename")
+	rootCmd.PersistentFlags().StringVar(&utils.Flags.LogFormat, FLAG_LOG_FORMAT, log.FORMAT_TEXT, "log output format: text, json")
+	rootCmd.PersistentFlags().StringVar(&utils.Flags.LogFile, FLAG_LOG_FILE, "", "write log output to a file (rotated by size); errors and warnings are still written to stderr")
+	rootCmd.PersistentFlags().IntVar(&utils.Flags.LogMaxSize, FLAG_LOG_MAX_SIZE, 10, "rotate the log file when it reaches this size (in megabytes); 0 never rotates")
+	rootCmd.PersistentFlags().IntVar(&utils.Flags.LogMaxBackups, FLAG_LOG_MAX_BACKUPS, 3, "number of rotated log files to keep (e.g., <log-file>.1)")
+	rootCmd.PersistentFlags().BoolVar(&utils.Flags.LogCompress, FLAG_LOG_COMPRESS, false, "gzip rotated log files (e.g., <log-file>.1.gz)")
+	rootCmd.PersistentFlags().StringVar(&utils.Flags.Color, FLAG_COLOR, log.COLOR_AUTO, "colorize (log and formatted) output: auto (terminals only; honors NO_COLOR and FORCE_COLOR), on, off")
 	ProjectLogger.Exit()
 }
 
@@ -76,41 +109,123 @@ func initConfig() {
 		// debug level implies trace
 		ProjectLogger.SetLevel(log.TRACE)
 	}
+	// show the call tree (i.e., nested Enter() and Exit() calls) when tracing
+	ProjectLogger.EnableIndent(utils.Flags.Trace || utils.Flags.Debug)
 
-	// Print global flags in debug mode
-	flagInfo, err := log.FormatStruct("Flags", utils.Flags)
-	if err != nil {
-		ProjectLogger.Error(err.Error())
-	} else {
-		ProjectLogger.Debug(flagInfo)
+	// Update log format
+	if err := ProjectLogger.SetFormat(utils.Flags.LogFormat); err != nil {
+		configError = &UsageError{err}
 	}
 
-	// Print logger settings in debug mode
-	logInfo, err2 := log.FormatStruct("Flags", utils.Flags)
-	if err2 != nil {
-		ProjectLogger.Error(err2.Error())
+	// Update colorization (of log and formatted output)
+	if err := ProjectLogger.SetColor(utils.Flags.Color); err != nil {
+		configError = &UsageError{err}
 	} else {
-		ProjectLogger.Debug(logInfo)
+		log.SetFormatColor(utils.Flags.Color)
+	}
+
+	// Update log output (all levels to the file; errors and warnings also to stderr)
+	if utils.Flags.LogFile != "" {
+		if err := setLogFile(); err != nil {
+			configError = err
+		}
+	}
+
+	// Output of (dependencies using) "log/slog" (and the standard "log") goes
+	// through the project's logger, i.e., its level, format and outputs
+	// Note: installed here (not in init()), as "main" replaces the logger
+	slog.SetDefault(slog.New(log.NewSlogHandler(ProjectLogger)))
+
+	// Print global flags in debug mode
+	// Note: dumped (rather than formatted and logged), so that each output
+	// (e.g., the terminal, but not the log file) is colorized as configured
+	if ProjectLogger.GetLevel() >= log.DEBUG {
+		if err := ProjectLogger.DumpStruct("Flags", utils.Flags); err != nil {
+			ProjectLogger.Error(err)
+		}
 	}
 	ProjectLogger.Exit()
 }
 
+// Note: the log file is not buffered; it is (only) closed once the command
+// ran, i.e., to complete the (background) compression of a rotated file
+func setLogFile() error {
+	if utils.Flags.LogMaxSize < 0 {
+		return NewUsageError("invalid `--%s`: %d (expected 0 or more megabytes)", FLAG_LOG_MAX_SIZE, utils.Flags.LogMaxSize)
+	} else if utils.Flags.LogMaxBackups < 0 {
+		return NewUsageError("invalid `--%s`: %d (expected 0 or more)", FLAG_LOG_MAX_BACKUPS, utils.Flags.LogMaxBackups)
+	}
+	var err error
+	logFile, err = log.NewRotatingFile(utils.Flags.LogFile, int64(utils.Flags.LogMaxSize)*log.MEGABYTE,
+		utils.Flags.LogMaxBackups, utils.Flags.LogCompress)
+	if err != nil {
+		return NewIOError("unable to open log file: %w", err)
+	}
+	ProjectLogger.SetOutput(logFile)
+	ProjectLogger.AddOutput(os.Stderr, log.ERROR, log.WARNING)
+	ProjectLogger.Trace(fmt.Sprintf("Writing log output to: `%s`", utils.Flags.LogFile))
+	return nil
+}
+
+// Any (positional) argument to the root command is an unknown sub-command
+func rootCmdArgs(cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return NewUsageError("unknown command %q for %q", args[0], cmd.CommandPath())
+	}
+	return nil
+}
+
 func RootCmdImpl(cmd *cobra.Command, args []string) error {
-	ProjectLogger.Enter()
+	ProjectLogger.Enter(log.Arg("args", args))
 	//fmt.Printf("cmd: %+v\nargs: %v\n", cmd, args)
 	ProjectLogger.Exit()
 	return nil
 }
 
-func Execute() {
+// The fields every (log) line of a command run carries, i.e., the command
+// and, if any, the document (input file) it runs on
+func commandContext(cmd *cobra.Command) context.Context {
+	fields := map[string]interface{}{"command": cmd.Name()}
+	if utils.Flags.InputFile != "" {
+		fields["document"] = utils.Flags.InputFile
+	}
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return log.NewContext(ctx, ProjectLogger.WithFields(fields))
+}
+
+// The logger carried by the context (see commandContext()); otherwise, the project's
+func commandLogger(ctx context.Context) *log.MiniLogger {
+	if logger, ok := log.FromContext(ctx); ok {
+		return logger
+	}
+	return ProjectLogger
+}
+
+// Execute runs the (sub-)command named on the command line and returns its
+// error (if any); use ExitCode(err) to map the error to a process exit code.
+// Note: only the "main" package decides when (and with what code) to exit.
+func Execute() error {
 	// instead of creating a dependency on the "main" module
 	ProjectLogger.Enter()
-	if err := rootCmd.Execute(); err != nil {
-		// TODO: use log errors
-		// TODO: invalid command (empty); display help
-		rootCmd.Help()
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Note: the project name is copied into the flags (by "main") after this package's init()
+	rootCmd.Use = utils.Flags.Project
+	cmd, err := rootCmd.ExecuteContextC(context.Background())
+	if err != nil {
+		ProjectLogger.Error(err)
+		var usageError *UsageError
+		if errors.As(err, &usageError) {
+			cmd.Usage()
+		}
 	}
-	ProjectLogger.Exit()
+	ProjectLogger.Exit(ExitCode(err))
+	if logFile != nil {
+		if closeErr := logFile.Close(); closeErr != nil {
+			// i.e., (only) to stderr
+			ProjectLogger.Warning(fmt.Sprintf("unable to close log file: %v", closeErr))
+		}
+	}
+	return err
 }
@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	stdlog "log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// i.e., as "main" does, replace the logger (created by init()) before the
+// flags are parsed; slog (and standard "log") output must still follow it
+func TestSlogDefault(t *testing.T) {
+	defer func(logger *log.MiniLogger, flags utils.MyFlags, handler *slog.Logger) {
+		ProjectLogger, utils.Flags, configError = logger, flags, nil
+		slog.SetDefault(handler)
+	}(ProjectLogger, utils.Flags, slog.Default())
+
+	var buffer bytes.Buffer
+	ProjectLogger = log.NewLogger(log.INFO)
+	ProjectLogger.SetOutput(&buffer)
+	utils.Flags.LogFormat, utils.Flags.Color = log.FORMAT_JSON, log.COLOR_OFF
+	initConfig()
+	assert.NoError(t, configError)
+
+	slog.Debug("hidden")
+	slog.Info("shown", "count", 2)
+	stdlog.Print("standard")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2, buffer.String())
+	var records []map[string]interface{}
+	for _, line := range lines {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, "shown", records[0]["value"])
+	assert.Equal(t, map[string]interface{}{"count": 2.0}, records[0]["fields"])
+	assert.Equal(t, "standard", records[1]["value"])
+}
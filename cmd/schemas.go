@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	ProjectLogger.Enter()
+	addListFlags(schemasCmd)
+	rootCmd.AddCommand(schemasCmd)
+	ProjectLogger.Exit()
+}
+
+var schemasCmd = &cobra.Command{
+	Use:   "schemas",
+	Short: "list the embedded SBOM schemas.",
+	Long:  "list the embedded SBOM schemas (by format, version and encoding) that documents are validated against.",
+	Args:  cobra.NoArgs,
+	RunE:  schemasCmdImpl,
+}
+
+// A row of the `schemas` list
+type schemaRow struct {
+	Format   string
+	Version  string
+	Encoding string // i.e., "json" (JSON schema) or "xml" (XSD)
+	File     string
+	Url      string `log:"URL"`
+}
+
+func schemasCmdImpl(cmd *cobra.Command, args []string) error {
+	ctx := commandContext(cmd)
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("args", args))
+	err := writeList(ctx, schemaRows())
+	logger.Exit(err)
+	return err
+}
+
+// Sorted by format, version and encoding (unless sorted otherwise by `--sort`)
+func schemaRows() (rows []schemaRow) {
+	for _, encoding := range []struct {
+		name    string
+		schemas map[string]map[string]schema.Schema
+	}{{"json", schema.Schemas}, {"xml", schema.XMLSchemas}} {
+		for _, versions := range encoding.schemas {
+			for _, embedded := range versions {
+				rows = append(rows, schemaRow{embedded.Format, embedded.Version, encoding.name, embedded.File, embedded.Url})
+			}
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Format != rows[j].Format {
+			return rows[i].Format < rows[j].Format
+		} else if rows[i].Version != rows[j].Version {
+			return rows[i].Version < rows[j].Version
+		}
+		return rows[i].Encoding < rows[j].Encoding
+	})
+	return
+}
@@ -18,15 +18,60 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"strings"
 
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/sbom"
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/mrutkows/go-skeleton/spdx"
 	"github.com/mrutkows/go-skeleton/utils"
 	"github.com/spf13/cobra"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// The validators (i.e., sources) of schema errors
+const (
+	VALIDATOR_JSON_SCHEMA = "json-schema"
+	VALIDATOR_XSD         = "xsd"
+	VALIDATOR_SPDX        = "spdx" // semantic checks (of SPDX documents)
+)
+
+// A single schema (or semantic) violation found in the input document
+type SchemaError struct {
+	Pointer string      `json:"pointer"`         // JSON pointer (RFC 6901) to the offending value
+	Keyword string      `json:"keyword"`         // JSON schema (or XSD) keyword (e.g., "required") or semantic rule that failed
+	Source  string      `json:"source"`          // the validator that found it (e.g., VALIDATOR_XSD)
+	Message string      `json:"message"`         // human-readable description
+	Value   interface{} `json:"value,omitempty"` // the offending value (if any)
+}
+
+func (err SchemaError) String() string {
+	return fmt.Sprintf("%s: %s (%s)", err.Pointer, err.Message, err.Keyword)
+}
+
+// The outcome of validating a single document
+type ValidationResult struct {
+	Document string        `json:"document"`
+	Format   string        `json:"format"`
+	Version  string        `json:"version"`
+	Valid    bool          `json:"valid"`
+	Errors   []SchemaError `json:"errors"`
+}
+
+const (
+	FLAG_SCHEMA_FILE   = "schema"
+	FLAG_REPORT_FORMAT = "format"
 )
 
 func init() {
 	ProjectLogger.Enter()
+	validateCmd.Flags().StringVar(&utils.Flags.SchemaFile, FLAG_SCHEMA_FILE, "", "validate against a custom (local) JSON schema (or XSD, for XML) file instead of the embedded schema")
+	validateCmd.Flags().StringVar(&utils.Flags.ReportFormat, FLAG_REPORT_FORMAT, REPORT_FORMAT_TEXT, "validation report format: text, json, sarif, junit")
 	rootCmd.AddCommand(validateCmd)
 	ProjectLogger.Exit()
 }
@@ -35,31 +80,260 @@ var validateCmd = &cobra.Command{
 	Use:   "validate -i <input-sbom.json>",
 	Short: "validate input file against its declared SBOM schema.",
 	Long:  "validate input file against its declared SBOM schema, if detectable and supported.",
-	Run: func(cmd *cobra.Command, args []string) {
-		ProjectLogger.Enter()
-		// TODO: remove when execution call order satisfactory
-		fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!")
-		ProjectLogger.Exit()
-	},
-	RunE: validateCmdImpl,
+	RunE:  validateCmdImpl,
 }
 
 func validateCmdImpl(cmd *cobra.Command, args []string) error {
-	ProjectLogger.Enter()
-	isValid, err := Validate()
+	ctx := commandContext(cmd)
+	logger := commandLogger(ctx)
+	logger.Enter(log.Arg("args", args))
+	result, err := Validate(ctx)
+	if err == nil {
+		err = writeValidationReport(ctx, utils.Flags.ReportFormat, result)
+	}
+	if err == nil && !result.Valid {
+		err = NewValidationFailure("document `%s` is not valid (%d errors)", result.Document, len(result.Errors))
+	}
+	logger.Exit(err)
+	return err
+}
+
+// Validate loads the document named by the input file flag, detects its
+// SBOM format and version and validates it against the matching schema.
+func Validate(ctx context.Context) (result ValidationResult, err error) {
+	logger := commandLogger(ctx)
+	logger.Enter()
+	result, err = validateFile(ctx)
+	logger.Exit(result.Valid, err)
+	return
+}
+
+func validateFile(ctx context.Context) (result ValidationResult, err error) {
+	logger := commandLogger(ctx)
+	result.Document = utils.Flags.InputFile
+	buffer, detection, err := readInput(ctx)
 	if err != nil {
-		ProjectLogger.Error(err)
-		os.Exit(-3)
+		return
 	}
-	ProjectLogger.Info(fmt.Sprintf("Document %s: valid=[%t]", utils.Flags.InputFile, isValid))
-	ProjectLogger.Exit()
-	return nil
+	result.Format, result.Version = detection.Format, detection.Version
+
+	switch detection.Format {
+	case sbom.FORMAT_CYCLONEDX_JSON, sbom.FORMAT_SPDX_JSON:
+	case sbom.FORMAT_CYCLONEDX_XML:
+		logger.Trace(fmt.Sprintf("Document format: %s", detection))
+		result.Errors, err = validateXML(ctx, buffer, detection.Family(), detection.Version)
+		result.Valid = err == nil && len(result.Errors) == 0
+		return
+	case sbom.FORMAT_SPDX_YAML:
+		// SPDX YAML encodes the same data model (and schema) as SPDX JSON
+		if buffer, err = spdx.YAMLToJSON(buffer); err != nil {
+			err = NewParseError("unable to parse document: %w", err)
+			return
+		}
+	default:
+		err = NewParseError("schema validation is not supported for format: `%s`", detection.Format)
+		return
+	}
+	logger.Trace(fmt.Sprintf("Document format: %s", detection))
+
+	jsonSchema, err := loadSchema(ctx, detection.Family(), detection.Version)
+	if err != nil {
+		return
+	}
+
+	schemaResult, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(buffer))
+	if err != nil {
+		err = NewParseError("unable to parse document: %w", err)
+		return
+	}
+
+	result.Valid = schemaResult.Valid()
+	result.Errors = []SchemaError{}
+	for _, resultError := range schemaResult.Errors() {
+		result.Errors = append(result.Errors, SchemaError{
+			Pointer: contextToPointer(resultError.Context()),
+			Keyword: schemaKeyword(resultError.Type()),
+			Source:  VALIDATOR_JSON_SCHEMA,
+			Message: resultError.Description(),
+			Value:   resultError.Value(),
+		})
+	}
+
+	// Semantic checks require a document that (structurally) matches the schema
+	if result.Valid && detection.Family() == schema.FORMAT_SPDX {
+		result.Errors, err = checkSpdx(buffer)
+		result.Valid = err == nil && len(result.Errors) == 0
+	}
+	return
 }
 
-func Validate() (bool, error) {
-	ProjectLogger.Enter()
+// Validate an XML document against its (XSD) schema; unlike JSON pointers,
+// each error is located by the (XPath-like) path of the offending node.
+func validateXML(ctx context.Context, buffer []byte, format string, version string) ([]SchemaError, error) {
+	xmlSchema, err := loadXMLSchema(ctx, format, version)
+	if err != nil {
+		return nil, err
+	}
+
+	xmlErrors, err := xmlSchema.Validate(bytes.NewReader(buffer))
+	if err != nil {
+		return nil, NewParseError("unable to parse document: %w", err)
+	}
+
+	schemaErrors := []SchemaError{}
+	for _, xmlError := range xmlErrors {
+		schemaError := SchemaError{
+			Pointer: xmlError.Path,
+			Keyword: xmlError.Keyword,
+			Source:  VALIDATOR_XSD,
+			Message: xmlError.Message,
+		}
+		if xmlError.Value != "" {
+			schemaError.Value = xmlError.Value
+		}
+		schemaErrors = append(schemaErrors, schemaError)
+	}
+	return schemaErrors, nil
+}
+
+// Check the internal consistency of an (already schema-valid) SPDX JSON
+// document; each issue is reported with the semantic rule it violates.
+func checkSpdx(buffer []byte) ([]SchemaError, error) {
+	document, err := spdx.ParseJSON(bytes.NewReader(buffer))
+	if err != nil {
+		return nil, NewParseError("unable to parse document: %w", err)
+	}
 
+	issues := []SchemaError{}
+	for _, issue := range document.Check() {
+		issues = append(issues, SchemaError{
+			Pointer: issue.Pointer,
+			Keyword: issue.Rule,
+			Source:  VALIDATOR_SPDX,
+			Message: issue.Message,
+		})
+	}
+	return issues, nil
+}
 
-	ProjectLogger.Exit(true)
-	return true, nil
+// Use the custom schema file (if provided); otherwise, the embedded schema
+func loadSchema(ctx context.Context, format string, version string) (*gojsonschema.Schema, error) {
+	logger := commandLogger(ctx)
+	if utils.Flags.SchemaFile != "" {
+		logger.Trace(fmt.Sprintf("Using custom schema: `%s`", utils.Flags.SchemaFile))
+		jsonSchema, err := schema.CompileFile(utils.Flags.SchemaFile)
+		var pathError *fs.PathError
+		if errors.As(err, &pathError) {
+			return nil, NewIOError("unable to load custom schema: %w", err)
+		} else if err != nil {
+			return nil, NewParseError("unable to load custom schema: %w", err)
+		}
+		return jsonSchema, nil
+	}
+
+	if version == "" {
+		return nil, NewUsageError("unable to determine %s version; use `--%s` to provide a schema", format, FLAG_SCHEMA_FILE)
+	}
+
+	embeddedSchema, err := schema.Lookup(format, version)
+	if err != nil {
+		return nil, &ParseError{err}
+	}
+	logger.Trace(fmt.Sprintf("Using embedded schema: `%s` (%s)", embeddedSchema.File, embeddedSchema.Url))
+
+	jsonSchema, err := embeddedSchema.Compile()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile embedded schema `%s`: %w", embeddedSchema.File, err)
+	}
+	return jsonSchema, nil
+}
+
+// Use the custom XSD file (if provided); otherwise, the embedded XSD
+func loadXMLSchema(ctx context.Context, format string, version string) (*schema.XMLSchema, error) {
+	logger := commandLogger(ctx)
+	if utils.Flags.SchemaFile != "" {
+		logger.Trace(fmt.Sprintf("Using custom schema: `%s`", utils.Flags.SchemaFile))
+		xmlSchema, err := schema.CompileXMLFile(utils.Flags.SchemaFile)
+		var pathError *fs.PathError
+		if errors.As(err, &pathError) {
+			return nil, NewIOError("unable to load custom schema: %w", err)
+		} else if err != nil {
+			return nil, NewParseError("unable to load custom schema: %w", err)
+		}
+		return xmlSchema, nil
+	}
+
+	if version == "" {
+		return nil, NewUsageError("unable to determine %s version; use `--%s` to provide a schema", format, FLAG_SCHEMA_FILE)
+	}
+
+	embeddedSchema, err := schema.LookupXML(format, version)
+	if err != nil {
+		return nil, &ParseError{err}
+	}
+	logger.Trace(fmt.Sprintf("Using embedded schema: `%s` (%s)", embeddedSchema.File, embeddedSchema.Url))
+
+	xmlSchema, err := embeddedSchema.CompileXML()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile embedded schema `%s`: %w", embeddedSchema.File, err)
+	}
+	return xmlSchema, nil
+}
+
+// Escapes "~" and "/" within a JSON pointer (RFC 6901) segment
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Convert a gojsonschema context (e.g., "(root).components.0") to a JSON pointer
+// Note: segments are joined by NUL (as property names may contain "." or "/")
+func contextToPointer(context *gojsonschema.JsonContext) string {
+	if context == nil {
+		return ""
+	}
+	var pointer strings.Builder
+	for _, segment := range strings.Split(context.String("\x00"), "\x00")[1:] {
+		pointer.WriteString("/" + pointerEscaper.Replace(segment))
+	}
+	return pointer.String()
+}
+
+// gojsonschema reports error "types" (e.g., "number_gte"); map these back
+// to the JSON schema keywords (e.g., "minimum") they were raised for.
+var schemaKeywords = map[string]string{
+	"additional_property_not_allowed": "additionalProperties",
+	"array_max_items":                 "maxItems",
+	"array_max_properties":            "maxProperties",
+	"array_min_items":                 "minItems",
+	"array_min_properties":            "minProperties",
+	"array_no_additional_items":       "additionalItems",
+	"condition_else":                  "else",
+	"condition_then":                  "then",
+	"const":                           "const",
+	"contains":                        "contains",
+	"enum":                            "enum",
+	"format":                          "format",
+	"invalid_property_name":           "propertyNames",
+	"invalid_property_pattern":        "patternProperties",
+	"invalid_type":                    "type",
+	"missing_dependency":              "dependencies",
+	"multiple_of":                     "multipleOf",
+	"number_all_of":                   "allOf",
+	"number_any_of":                   "anyOf",
+	"number_gt":                       "exclusiveMinimum",
+	"number_gte":                      "minimum",
+	"number_lt":                       "exclusiveMaximum",
+	"number_lte":                      "maximum",
+	"number_not":                      "not",
+	"number_one_of":                   "oneOf",
+	"pattern":                         "pattern",
+	"required":                        "required",
+	"string_gte":                      "minLength",
+	"string_lte":                      "maxLength",
+	"unique":                          "uniqueItems",
+}
+
+func schemaKeyword(errorType string) string {
+	if keyword, ok := schemaKeywords[errorType]; ok {
+		return keyword
+	}
+	return errorType
 }
@@ -0,0 +1,284 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/log"
+	"github.com/mrutkows/go-skeleton/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Write a test document (to a temporary directory) and return its path
+func writeTestDocument(t *testing.T, name string, content string) string {
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// A (much) reduced CycloneDX schema, e.g., to validate against a custom schema
+const testCycloneDXSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"required": ["bomFormat", "specVersion"],
+	"properties": {
+		"bomFormat": {"enum": ["CycloneDX"]},
+		"specVersion": {"type": "string"},
+		"metadata": {"type": "object", "additionalProperties": {"type": "string"}}
+	}
+}`
+
+func TestValidateFile(t *testing.T) {
+	flags := utils.Flags
+	defer func() { utils.Flags = flags }()
+	customSchema := writeTestDocument(t, "bom.schema.json", testCycloneDXSchema)
+
+	tests := []struct {
+		name        string
+		inputFile   string
+		inputFormat string
+		schemaFile  string
+		errors      []SchemaError // expected (subset of) schema errors; Message and Value are not compared
+		err         error         // expected (wrapped) error, if known
+		message     string        // expected (part of the) error message, if any
+		exitCode    int
+	}{
+		{
+			name:      "valid CycloneDX JSON",
+			inputFile: writeTestDocument(t, "valid.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,"components":[{"type":"library","name":"acme"}]}`),
+		},
+		{
+			name:      "valid CycloneDX XML",
+			inputFile: "../cyclonedx/testdata/valid-bom.xml",
+		},
+		{
+			name:      "valid SPDX JSON",
+			inputFile: "../spdx/testdata/SPDXJSONExample-v2.3.spdx.json",
+		},
+		{
+			name:      "valid SPDX YAML",
+			inputFile: "../spdx/testdata/SPDXYAMLExample-2.3.spdx.yaml",
+		},
+		{
+			name: "schema-invalid CycloneDX JSON",
+			inputFile: writeTestDocument(t, "invalid.json",
+				`{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,"components":[{"type":"library"},{"type":"unknown","name":"acme"}]}`),
+			errors: []SchemaError{
+				{Pointer: "/components/0", Keyword: "required", Source: VALIDATOR_JSON_SCHEMA},
+				{Pointer: "/components/1/type", Keyword: "enum", Source: VALIDATOR_JSON_SCHEMA},
+			},
+		},
+		{
+			name: "schema-invalid CycloneDX XML",
+			inputFile: writeTestDocument(t, "invalid.xml",
+				`<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1"><components><component type="unknown"><name>acme</name></component></components></bom>`),
+			errors: []SchemaError{
+				{Pointer: "/bom/components/component/@type", Keyword: "enumeration", Source: VALIDATOR_XSD},
+			},
+		},
+		{
+			name:      "schema-invalid SPDX JSON",
+			inputFile: writeTestDocument(t, "invalid.spdx.json", `{"spdxVersion":"SPDX-2.3","SPDXID":"SPDXRef-DOCUMENT"}`),
+			errors:    []SchemaError{{Pointer: "", Keyword: "required", Source: VALIDATOR_JSON_SCHEMA}},
+		},
+		{
+			name:       "custom schema",
+			inputFile:  writeTestDocument(t, "custom.json", `{"bomFormat":"CycloneDX","specVersion":"1.5","metadata":{"a/b~c":1}}`),
+			schemaFile: customSchema,
+			errors:     []SchemaError{{Pointer: "/metadata/a~1b~0c", Keyword: "type", Source: VALIDATOR_JSON_SCHEMA}},
+		},
+		{
+			name:       "missing custom schema",
+			inputFile:  writeTestDocument(t, "custom.json", `{"bomFormat":"CycloneDX","specVersion":"1.5"}`),
+			schemaFile: filepath.Join(t.TempDir(), "missing.schema.json"),
+			err:        fs.ErrNotExist,
+			exitCode:   EXIT_IO_ERROR,
+		},
+		{
+			name:      "unknown format",
+			inputFile: writeTestDocument(t, "unknown.json", `{"name":"acme"}`),
+			message:   "unable to detect SBOM format",
+			exitCode:  EXIT_PARSE_ERROR,
+		},
+		{
+			name:      "unsupported version",
+			inputFile: writeTestDocument(t, "version.json", `{"bomFormat":"CycloneDX","specVersion":"0.9"}`),
+			message:   "unsupported CycloneDX version: `0.9`",
+			exitCode:  EXIT_PARSE_ERROR,
+		},
+		{
+			name:      "unsupported format",
+			inputFile: writeTestDocument(t, "hello.spdx", "SPDXVersion: SPDX-2.3\nDataLicense: CC0-1.0\n"),
+			message:   "schema validation is not supported for format: `spdx-tv`",
+			exitCode:  EXIT_PARSE_ERROR,
+		},
+		{
+			name:        "invalid input format",
+			inputFile:   writeTestDocument(t, "valid.json", `{"bomFormat":"CycloneDX","specVersion":"1.5"}`),
+			inputFormat: "html",
+			message:     "unsupported input format: `html`",
+			exitCode:    EXIT_USAGE_ERROR,
+		},
+		{
+			name:     "no input file",
+			message:  "no input file specified",
+			exitCode: EXIT_USAGE_ERROR,
+		},
+		{
+			name:      "missing file",
+			inputFile: filepath.Join(t.TempDir(), "missing.json"),
+			err:       fs.ErrNotExist,
+			exitCode:  EXIT_IO_ERROR,
+		},
+		{
+			name:      "unreadable file (directory)",
+			inputFile: t.TempDir(),
+			message:   "unable to read input file",
+			exitCode:  EXIT_IO_ERROR,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			utils.Flags.InputFile = test.inputFile
+			utils.Flags.InputFormat = test.inputFormat
+			utils.Flags.SchemaFile = test.schemaFile
+			result, err := validateFile(context.Background())
+			assert.Equal(t, test.exitCode, ExitCode(err), err)
+			if test.err == nil && test.message == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				if test.err != nil {
+					assert.True(t, errors.Is(err, test.err), err)
+				}
+				assert.Contains(t, err.Error(), test.message)
+			}
+			assert.Equal(t, err == nil && test.errors == nil, result.Valid)
+			if test.errors == nil {
+				assert.Empty(t, result.Errors)
+			}
+			for _, expected := range test.errors {
+				assert.Contains(t, locateErrors(result.Errors), expected)
+			}
+		})
+	}
+}
+
+// Functions exit (i.e., trace their EXIT) on failure, too; otherwise, the
+// (per-goroutine) call tree, and so the indentation of later output, is off
+func TestEnterExitOnFailure(t *testing.T) {
+	flags := utils.Flags
+	defer func() { utils.Flags = flags }()
+	utils.Flags.InputFile = filepath.Join(t.TempDir(), "missing.json")
+
+	// Note: logged at the same stack depth as the function that failed
+	traceDone := func(ctx context.Context) {
+		commandLogger(ctx).Info("done")
+	}
+	tests := map[string]func(ctx context.Context) error{
+		"readInput": func(ctx context.Context) error {
+			_, _, err := readInput(ctx)
+			traceDone(ctx)
+			return err
+		},
+		"Convert": func(ctx context.Context) error {
+			_, err := Convert(ctx)
+			traceDone(ctx)
+			return err
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+			logger := log.NewLogger(log.TRACE)
+			logger.SetOutput(&output)
+			logger.EnableIndent(true)
+			assert.NoError(t, logger.SetFormat(log.FORMAT_JSON))
+
+			assert.Error(t, test(log.NewContext(context.Background(), logger)))
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			var record struct{ Depth int }
+			assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &record))
+			assert.Zero(t, record.Depth, output.String())
+		})
+	}
+}
+
+// Strip (for comparison) the (validator-specific) messages and values
+func locateErrors(schemaErrors []SchemaError) []SchemaError {
+	located := []SchemaError{}
+	for _, schemaError := range schemaErrors {
+		located = append(located, SchemaError{Pointer: schemaError.Pointer, Keyword: schemaError.Keyword, Source: schemaError.Source})
+	}
+	return located
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		exitCode int
+	}{
+		{nil, EXIT_SUCCESS},
+		{errors.New("internal"), EXIT_ERROR},
+		{NewUsageError("usage"), EXIT_USAGE_ERROR},
+		{NewIOError("io"), EXIT_IO_ERROR},
+		{NewParseError("parse"), EXIT_PARSE_ERROR},
+		{NewValidationFailure("invalid"), EXIT_VALIDATION_FAILURE},
+		{NewPolicyFailure("policy"), EXIT_POLICY_FAILURE},
+		// wrapped errors map to the (innermost) exit code
+		{NewIOError("unable to write report: %w", NewUsageError("usage")), EXIT_IO_ERROR},
+		{fmt.Errorf("internal: %w", NewParseError("parse")), EXIT_PARSE_ERROR},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.exitCode, ExitCode(test.err), test.err)
+	}
+}
+
+func TestContextToPointer(t *testing.T) {
+	root := gojsonschema.NewJsonContext("(root)", nil)
+	components := gojsonschema.NewJsonContext("components", root)
+	tests := []struct {
+		context *gojsonschema.JsonContext
+		pointer string
+	}{
+		{nil, ""},
+		{root, ""},
+		{components, "/components"},
+		{gojsonschema.NewJsonContext("0", components), "/components/0"},
+		{gojsonschema.NewJsonContext("a/b~c", components), "/components/a~1b~0c"},
+		{gojsonschema.NewJsonContext("a.b", root), "/a.b"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.pointer, contextToPointer(test.context))
+	}
+
+	assert.Equal(t, "minimum", schemaKeyword("number_gte"))
+	assert.Equal(t, "unknown_type", schemaKeyword("unknown_type"))
+}
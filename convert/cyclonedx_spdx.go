@@ -0,0 +1,623 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mrutkows/go-skeleton/cyclonedx"
+	"github.com/mrutkows/go-skeleton/spdx"
+)
+
+// The SPDX version documents are converted to
+const SPDX_VERSION = "SPDX-2.3"
+
+// Prefix of the (OTHER) annotations that keep CycloneDX fields without an
+// SPDX equivalent (e.g., "cdx:scope: optional")
+const ANNOTATION_PREFIX = "cdx:"
+
+const (
+	SPDX_NAMESPACE_PREFIX = "https://spdx.org/spdxdocs/"
+	SPDX_TIME_LAYOUT      = "2006-01-02T15:04:05Z"
+	ANNOTATION_TYPE_OTHER = "OTHER"
+)
+
+// CycloneDXToSpdx converts a CycloneDX BOM to an SPDX (2.3) document; tool
+// (i.e., "<name>-<version>") is recorded as the document's creator. Every
+// component becomes a package (with a generated SPDXID); nesting becomes
+// CONTAINS and the dependency graph, DEPENDS_ON relationships. CycloneDX
+// fields without an equivalent are kept as "cdx:" annotations; the report
+// lists every such approximation.
+func CycloneDXToSpdx(bom *cyclonedx.Bom, tool string) (*spdx.Document, *Report, error) {
+	converter := &cyclonedxConverter{
+		bom:      bom,
+		tool:     tool,
+		report:   &Report{},
+		spdxIds:  map[string]bool{spdx.SPDXID_DOCUMENT: true},
+		refs:     map[string]string{},
+		packages: map[string]*spdx.Package{},
+		licenses: map[string]bool{},
+	}
+	return converter.convert(), converter.report, nil
+}
+
+type cyclonedxConverter struct {
+	bom      *cyclonedx.Bom
+	tool     string
+	report   *Report
+	document *spdx.Document
+
+	spdxIds  map[string]bool          // SPDXIDs in use
+	refs     map[string]string        // bom-ref to SPDXID
+	packages map[string]*spdx.Package // by SPDXID
+	licenses map[string]bool          // declared "LicenseRef-"s
+}
+
+func (converter *cyclonedxConverter) annotator() string {
+	return spdx.Entity{Type: spdx.ENTITY_TOOL, Name: converter.tool}.String()
+}
+
+// Keep a field without SPDX equivalent as an (OTHER) "cdx:" annotation
+func (converter *cyclonedxConverter) keep(annotations *[]*spdx.Annotation, source string, name string, value string) {
+	if value == "" {
+		return
+	}
+	*annotations = append(*annotations, &spdx.Annotation{
+		Annotator:      converter.annotator(),
+		AnnotationDate: converter.document.CreationInfo.Created,
+		AnnotationType: ANNOTATION_TYPE_OTHER,
+		Comment:        ANNOTATION_PREFIX + name + ": " + value,
+	})
+	converter.report.add(source, "kept as annotation `%s%s`", ANNOTATION_PREFIX, name)
+}
+
+// Keep a structured field (or object) as a JSON-encoded "cdx:" annotation
+func (converter *cyclonedxConverter) keepJSON(annotations *[]*spdx.Annotation, source string, name string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		converter.report.add(source, "dropped; unable to encode as annotation: %s", err)
+		return
+	}
+	converter.keep(annotations, source, name, string(data))
+}
+
+func (converter *cyclonedxConverter) convert() *spdx.Document {
+	bom := converter.bom
+	metadata := bom.Metadata
+	if metadata == nil {
+		metadata = &cyclonedx.Metadata{}
+	}
+
+	document := &spdx.Document{
+		SPDXVersion: SPDX_VERSION,
+		DataLicense: spdx.DEFAULT_DATA_LICENSE,
+		SPDXID:      spdx.SPDXID_DOCUMENT,
+		Name:        documentName(bom),
+	}
+	converter.document = document
+	document.DocumentNamespace = converter.namespace(document.Name)
+	converter.convertMetadata(metadata)
+
+	// The (metadata) component the BOM describes, then all others
+	var described []string
+	if metadata.Component != nil {
+		described = append(described, converter.convertComponent("/metadata/component", metadata.Component, ""))
+	}
+	for i := range bom.Components {
+		spdxId := converter.convertComponent(fmt.Sprintf("/components/%d", i), &bom.Components[i], "")
+		if metadata.Component == nil {
+			described = append(described, spdxId)
+		}
+	}
+	// DESCRIBES (first) followed by the CONTAINS added by convertComponent()
+	var relationships []*spdx.Relationship
+	for _, spdxId := range described {
+		relationships = append(relationships, &spdx.Relationship{
+			SPDXElementID:      spdx.SPDXID_DOCUMENT,
+			RelationshipType:   RELATIONSHIP_DESCRIBES,
+			RelatedSPDXElement: spdxId,
+		})
+	}
+	document.Relationships = append(relationships, document.Relationships...)
+	converter.convertDependencies()
+	converter.convertAnnotations()
+
+	// Everything else is about the document (i.e., BOM) as a whole
+	annotations := &document.Annotations
+	converter.keep(annotations, "/serialNumber", "serialNumber", bom.SerialNumber)
+	if bom.Version != 0 {
+		converter.keep(annotations, "/version", "version", fmt.Sprint(bom.Version))
+	}
+	for i, reference := range bom.ExternalReferences {
+		converter.keepJSON(annotations, fmt.Sprintf("/externalReferences/%d", i), "externalReference", reference)
+	}
+	for i, service := range bom.Services {
+		converter.keepJSON(annotations, fmt.Sprintf("/services/%d", i), "service", service)
+	}
+	for i, composition := range bom.Compositions {
+		converter.keepJSON(annotations, fmt.Sprintf("/compositions/%d", i), "composition", composition)
+	}
+	for i, vulnerability := range bom.Vulnerabilities {
+		converter.keepJSON(annotations, fmt.Sprintf("/vulnerabilities/%d", i), "vulnerability", vulnerability)
+	}
+	for i, property := range bom.Properties {
+		converter.keepJSON(annotations, fmt.Sprintf("/properties/%d", i), "property", property)
+	}
+	return document
+}
+
+// Named after the component the BOM describes (if any)
+func documentName(bom *cyclonedx.Bom) string {
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		if component := bom.Metadata.Component; component.Version != "" {
+			return component.Name + "-" + component.Version
+		}
+		return bom.Metadata.Component.Name
+	}
+	if bom.SerialNumber != "" {
+		return bom.SerialNumber
+	}
+	return "CycloneDX BOM"
+}
+
+// A (unique) namespace derived from the BOM's serial number or, if none,
+// its content; i.e., the same BOM always converts to the same namespace
+func (converter *cyclonedxConverter) namespace(name string) string {
+	uuid := strings.TrimPrefix(converter.bom.SerialNumber, "urn:uuid:")
+	if uuid == "" {
+		data, _ := json.Marshal(converter.bom)
+		uuid = uuidFromURL("data:application/json," + string(data))
+	} else if uuid == converter.bom.SerialNumber {
+		uuid = uuidFromURL(uuid)
+	}
+	return SPDX_NAMESPACE_PREFIX + sanitize(name) + "-" + uuid
+}
+
+// SPDX times are UTC (to the second); e.g., "2010-01-29T18:30:22Z"
+func (converter *cyclonedxConverter) convertTime(source string, timestamp string) string {
+	if timestamp == "" {
+		return ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		converter.report.add(source, "invalid timestamp `%s`; kept as is", timestamp)
+		return timestamp
+	}
+	if converted := parsed.UTC().Format(SPDX_TIME_LAYOUT); converted != timestamp {
+		converter.report.add(source, "timestamp converted to UTC (to the second): `%s`", converted)
+		return converted
+	}
+	return timestamp
+}
+
+// ------------------------------------------------------------------------
+// Metadata
+// ------------------------------------------------------------------------
+
+// The converting tool, the BOM's tools, authors and manufacturer are the
+// document's creators
+func (converter *cyclonedxConverter) convertMetadata(metadata *cyclonedx.Metadata) {
+	creationInfo := &converter.document.CreationInfo
+	if metadata.Timestamp != "" {
+		creationInfo.Created = converter.convertTime("/metadata/timestamp", metadata.Timestamp)
+	} else {
+		creationInfo.Created = time.Now().UTC().Format(SPDX_TIME_LAYOUT)
+		converter.report.add("/metadata/timestamp", "missing; set to the time of conversion")
+	}
+
+	creators := []string{converter.annotator()}
+	addTool := func(name string, version string) {
+		if version != "" {
+			name += "-" + version
+		}
+		creators = append(creators, spdx.Entity{Type: spdx.ENTITY_TOOL, Name: name}.String())
+	}
+	if tools := metadata.Tools; tools != nil {
+		for _, tool := range tools.Tools {
+			addTool(tool.Name, tool.Version)
+		}
+		for _, component := range tools.Components {
+			addTool(component.Name, component.Version)
+		}
+		for _, service := range tools.Services {
+			addTool(service.Name, service.Version)
+		}
+	}
+	for _, author := range metadata.Authors {
+		creators = append(creators, spdx.Entity{Type: spdx.ENTITY_PERSON, Name: author.Name, Email: author.Email}.String())
+	}
+	for _, manufacturer := range []*cyclonedx.OrganizationalEntity{metadata.Manufacture, metadata.Manufacturer} {
+		if manufacturer != nil && manufacturer.Name != "" {
+			creators = append(creators, organization(manufacturer))
+		}
+	}
+	creationInfo.Creators = creators
+
+	annotations := &converter.document.Annotations
+	if metadata.Supplier != nil {
+		converter.keepJSON(annotations, "/metadata/supplier", "supplier", metadata.Supplier)
+	}
+	for i, lifecycle := range metadata.Lifecycles {
+		converter.keepJSON(annotations, fmt.Sprintf("/metadata/lifecycles/%d", i), "lifecycle", lifecycle)
+	}
+	for i, license := range metadata.Licenses {
+		converter.keepJSON(annotations, fmt.Sprintf("/metadata/licenses/%d", i), "license", license)
+	}
+	for i, property := range metadata.Properties {
+		converter.keepJSON(annotations, fmt.Sprintf("/metadata/properties/%d", i), "property", property)
+	}
+}
+
+func organization(entity *cyclonedx.OrganizationalEntity) string {
+	organization := spdx.Entity{Type: spdx.ENTITY_ORGANIZATION, Name: entity.Name}
+	if len(entity.Contact) > 0 {
+		organization.Email = entity.Contact[0].Email
+	}
+	return organization.String()
+}
+
+// ------------------------------------------------------------------------
+// Components
+// ------------------------------------------------------------------------
+
+// Generate a unique SPDXID from the component's bom-ref (or name and version)
+func (converter *cyclonedxConverter) newSpdxId(component *cyclonedx.Component) string {
+	name := component.BOMRef
+	if name == "" {
+		name = component.Name
+		if component.Version != "" {
+			name += "-" + component.Version
+		}
+	}
+	base := spdx.SPDXID_PREFIX + sanitize(strings.TrimPrefix(name, spdx.SPDXID_PREFIX))
+	spdxId := base
+	for i := 2; converter.spdxIds[spdxId]; i++ {
+		spdxId = fmt.Sprintf("%s-%d", base, i)
+	}
+	converter.spdxIds[spdxId] = true
+	if component.BOMRef != "" {
+		converter.refs[component.BOMRef] = spdxId
+	}
+	return spdxId
+}
+
+// SPDXIDs (and LicenseRefs) may only contain letters, numbers, "." and "-"
+func sanitize(value string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, value)
+}
+
+// Convert the component (and, recursively, its components) to packages
+func (converter *cyclonedxConverter) convertComponent(pointer string, component *cyclonedx.Component, parent string) string {
+	source := func(field string) string {
+		return pointer + "/" + field
+	}
+	filesAnalyzed := false
+	pkg := &spdx.Package{
+		SPDXID:           converter.newSpdxId(component),
+		Name:             component.Name,
+		VersionInfo:      component.Version,
+		Description:      component.Description,
+		DownloadLocation: spdx.NOASSERTION,
+		FilesAnalyzed:    &filesAnalyzed,
+		CopyrightText:    spdx.NOASSERTION,
+	}
+	converter.document.Packages = append(converter.document.Packages, pkg)
+	converter.packages[pkg.SPDXID] = pkg
+	annotations := &pkg.Annotations
+
+	if purpose, found := packagePurposes[component.Type]; found {
+		pkg.PrimaryPackagePurpose = purpose
+	} else {
+		pkg.PrimaryPackagePurpose = PACKAGE_PURPOSE_OTHER
+		converter.keep(annotations, source("type"), "type", component.Type)
+	}
+
+	// Supplier and originator (i.e., manufacturer or author)
+	if component.Supplier != nil && component.Supplier.Name != "" {
+		pkg.Supplier = organization(component.Supplier)
+	}
+	if component.Manufacturer != nil && component.Manufacturer.Name != "" {
+		pkg.Originator = organization(component.Manufacturer)
+		converter.report.add(source("manufacturer"), "mapped to the package originator")
+	}
+	for i, author := range component.Authors {
+		if pkg.Originator == "" {
+			pkg.Originator = spdx.Entity{Type: spdx.ENTITY_PERSON, Name: author.Name, Email: author.Email}.String()
+			converter.report.add(source(fmt.Sprintf("authors/%d", i)), "mapped to the package originator")
+		} else {
+			converter.keepJSON(annotations, source(fmt.Sprintf("authors/%d", i)), "author", author)
+		}
+	}
+	if component.Author != "" {
+		if pkg.Originator == "" {
+			pkg.Originator = spdx.Entity{Type: spdx.ENTITY_PERSON, Name: component.Author}.String()
+			converter.report.add(source("author"), "mapped to the package originator")
+		} else {
+			converter.keep(annotations, source("author"), "author", component.Author)
+		}
+	}
+
+	for i, hash := range component.Hashes {
+		if algorithm, found := checksumAlgorithms[hash.Algorithm]; found {
+			pkg.Checksums = append(pkg.Checksums, spdx.Checksum{Algorithm: algorithm, ChecksumValue: hash.Value})
+		} else {
+			converter.keep(annotations, source(fmt.Sprintf("hashes/%d", i)), "hash", hash.Algorithm+": "+hash.Value)
+		}
+	}
+	pkg.LicenseDeclared, pkg.LicenseConcluded = converter.convertLicenses(source("licenses"), component.Licenses)
+	if component.Copyright != "" {
+		pkg.CopyrightText = component.Copyright
+	}
+	converter.convertIdentifiers(pkg, component)
+	converter.convertExternalReferences(pkg, pointer, component.ExternalReferences)
+
+	converter.keep(annotations, source("group"), "group", component.Group)
+	converter.keep(annotations, source("publisher"), "publisher", component.Publisher)
+	converter.keep(annotations, source("scope"), "scope", component.Scope)
+	converter.keep(annotations, source("mime-type"), "mime-type", component.MIMEType)
+	if component.SWID != nil {
+		converter.keepJSON(annotations, source("swid"), "swid", component.SWID)
+	}
+	if component.Modified != nil {
+		converter.keepJSON(annotations, source("modified"), "modified", component.Modified)
+	}
+	if component.Pedigree != nil {
+		converter.keepJSON(annotations, source("pedigree"), "pedigree", component.Pedigree)
+	}
+	if component.Evidence != nil {
+		converter.keepJSON(annotations, source("evidence"), "evidence", component.Evidence)
+	}
+	if component.ReleaseNotes != nil {
+		converter.keepJSON(annotations, source("releaseNotes"), "releaseNotes", component.ReleaseNotes)
+	}
+	for i, property := range component.Properties {
+		converter.keepJSON(annotations, source(fmt.Sprintf("properties/%d", i)), "property", property)
+	}
+
+	if parent != "" {
+		converter.document.Relationships = append(converter.document.Relationships, &spdx.Relationship{
+			SPDXElementID:      parent,
+			RelationshipType:   RELATIONSHIP_CONTAINS,
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+	for i := range component.Components {
+		converter.convertComponent(fmt.Sprintf("%s/components/%d", pointer, i), &component.Components[i], pkg.SPDXID)
+	}
+	return pkg.SPDXID
+}
+
+// Licenses acknowledged (since 1.6) as "concluded" are the concluded
+// license; all others, the declared license. Several licenses are combined
+// into a conjunctive ("AND") expression.
+func (converter *cyclonedxConverter) convertLicenses(source string, licenses []cyclonedx.LicenseChoice) (declared string, concluded string) {
+	var declaredTerms, concludedTerms []string
+	for i, choice := range licenses {
+		term := choice.Expression
+		acknowledgement := choice.Acknowledgement
+		if license := choice.License; license != nil {
+			acknowledgement = license.Acknowledgement
+			if term = license.ID; term == "" {
+				term = converter.licenseRef(fmt.Sprintf("%s/%d/license", source, i), license)
+			}
+		}
+		if term == "" {
+			continue
+		}
+		for _, licenseRef := range spdx.LicenseRefs(term) {
+			if !converter.licenses[licenseRef] {
+				converter.licenses[licenseRef] = true
+				converter.document.HasExtractedLicensingInfos = append(converter.document.HasExtractedLicensingInfos,
+					&spdx.ExtractedLicensingInfo{LicenseId: licenseRef, ExtractedText: spdx.NOASSERTION})
+				converter.report.add(fmt.Sprintf("%s/%d", source, i), "license text of `%s` unknown; set to `%s`", licenseRef, spdx.NOASSERTION)
+			}
+		}
+		if strings.ContainsAny(term, " ") && len(licenses) > 1 {
+			term = "(" + term + ")"
+		}
+		if acknowledgement == "concluded" {
+			concludedTerms = append(concludedTerms, term)
+		} else {
+			declaredTerms = append(declaredTerms, term)
+		}
+	}
+
+	declared, concluded = spdx.NOASSERTION, spdx.NOASSERTION
+	if len(declaredTerms) > 0 {
+		declared = strings.Join(declaredTerms, " AND ")
+	}
+	if len(concludedTerms) > 0 {
+		concluded = strings.Join(concludedTerms, " AND ")
+	}
+	if len(declaredTerms) > 1 || len(concludedTerms) > 1 {
+		converter.report.add(source, "multiple licenses combined into an `AND` expression")
+	}
+	return
+}
+
+// Licenses known by name (only) are declared as extracted licensing info
+func (converter *cyclonedxConverter) licenseRef(source string, license *cyclonedx.License) string {
+	licenseRef := spdx.LICENSE_REF_PREFIX + sanitize(license.Name)
+	if converter.licenses[licenseRef] {
+		return licenseRef
+	}
+	converter.licenses[licenseRef] = true
+
+	info := &spdx.ExtractedLicensingInfo{LicenseId: licenseRef, Name: license.Name}
+	if license.Text != nil && license.Text.Content != "" && license.Text.Encoding == "" {
+		info.ExtractedText = license.Text.Content
+	} else {
+		info.ExtractedText = spdx.NOASSERTION
+		converter.report.add(source, "license text unknown (or encoded); set to `%s`", spdx.NOASSERTION)
+	}
+	if license.URL != "" {
+		info.SeeAlsos = []string{license.URL}
+	}
+	converter.document.HasExtractedLicensingInfos = append(converter.document.HasExtractedLicensingInfos, info)
+	converter.report.add(source, "license name mapped to `%s`", licenseRef)
+	return licenseRef
+}
+
+// Package URLs, CPEs, SWHIDs and gitoids are (SPDX) external references
+func (converter *cyclonedxConverter) convertIdentifiers(pkg *spdx.Package, component *cyclonedx.Component) {
+	addRef := func(category string, referenceType string, locator string) {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, &spdx.ExternalRef{
+			ReferenceCategory: category,
+			ReferenceType:     referenceType,
+			ReferenceLocator:  locator,
+		})
+	}
+	if component.PURL != "" {
+		addRef(REFERENCE_CATEGORY_PACKAGE_MANAGER, EXTERNAL_REF_PURL, component.PURL)
+	}
+	if component.CPE != "" {
+		referenceType := EXTERNAL_REF_CPE22
+		if strings.HasPrefix(component.CPE, "cpe:2.3:") {
+			referenceType = EXTERNAL_REF_CPE23
+		}
+		addRef(REFERENCE_CATEGORY_SECURITY, referenceType, component.CPE)
+	}
+	for _, swhid := range component.SWHID {
+		addRef(REFERENCE_CATEGORY_PERSISTENT_ID, EXTERNAL_REF_SWH, swhid)
+	}
+	for _, omniborId := range component.OmniborID {
+		addRef(REFERENCE_CATEGORY_PERSISTENT_ID, EXTERNAL_REF_GITOID, omniborId)
+	}
+}
+
+// The (first) website and distribution (or VCS) are the package's home page
+// and download location; advisories and all other references are external
+// references (of category SECURITY and OTHER respectively)
+func (converter *cyclonedxConverter) convertExternalReferences(pkg *spdx.Package, pointer string, references []cyclonedx.ExternalReference) {
+	for i, reference := range references {
+		source := fmt.Sprintf("%s/externalReferences/%d", pointer, i)
+		switch {
+		case reference.Type == EXTERNAL_REFERENCE_WEBSITE && pkg.Homepage == "":
+			pkg.Homepage = reference.URL
+		case (reference.Type == EXTERNAL_REFERENCE_DISTRIBUTION || reference.Type == EXTERNAL_REFERENCE_VCS) &&
+			pkg.DownloadLocation == spdx.NOASSERTION:
+			pkg.DownloadLocation = reference.URL
+		case strings.ContainsAny(reference.URL, " \t\n"):
+			// locators may not contain whitespace
+			converter.keepJSON(&pkg.Annotations, source, "externalReference", reference)
+			continue
+		case reference.Type == EXTERNAL_REFERENCE_ADVISORIES:
+			pkg.ExternalRefs = append(pkg.ExternalRefs, &spdx.ExternalRef{
+				ReferenceCategory: REFERENCE_CATEGORY_SECURITY,
+				ReferenceType:     EXTERNAL_REF_ADVISORY,
+				ReferenceLocator:  reference.URL,
+				Comment:           reference.Comment,
+			})
+		default:
+			pkg.ExternalRefs = append(pkg.ExternalRefs, &spdx.ExternalRef{
+				ReferenceCategory: REFERENCE_CATEGORY_OTHER,
+				ReferenceType:     reference.Type,
+				ReferenceLocator:  reference.URL,
+				Comment:           reference.Comment,
+			})
+			converter.report.add(source, "mapped to an external reference of category `%s`", REFERENCE_CATEGORY_OTHER)
+		}
+		if len(reference.Hashes) > 0 {
+			converter.keepJSON(&pkg.Annotations, source+"/hashes", "externalReferenceHashes", reference.Hashes)
+		}
+	}
+}
+
+// ------------------------------------------------------------------------
+// Dependencies and annotations
+// ------------------------------------------------------------------------
+
+// Each dependency becomes a DEPENDS_ON relationship; a component that
+// (explicitly) has no dependencies, DEPENDS_ON NONE
+func (converter *cyclonedxConverter) convertDependencies() {
+	for i, dependency := range converter.bom.Dependencies {
+		source := fmt.Sprintf("/dependencies/%d", i)
+		spdxId, found := converter.refs[dependency.Ref]
+		if !found {
+			converter.keepJSON(&converter.document.Annotations, source, "dependency", dependency)
+			continue
+		}
+
+		related := dependency.DependsOn
+		if len(related) == 0 {
+			related = []string{""}
+		}
+		for j, ref := range related {
+			relatedId := spdx.NONE
+			if ref != "" {
+				if relatedId, found = converter.refs[ref]; !found {
+					converter.keep(&converter.packages[spdxId].Annotations, fmt.Sprintf("%s/dependsOn/%d", source, j), "dependsOn", ref)
+					continue
+				}
+			}
+			converter.document.Relationships = append(converter.document.Relationships, &spdx.Relationship{
+				SPDXElementID:      spdxId,
+				RelationshipType:   RELATIONSHIP_DEPENDS_ON,
+				RelatedSPDXElement: relatedId,
+			})
+		}
+		if len(dependency.Provides) > 0 {
+			converter.keepJSON(&converter.packages[spdxId].Annotations, source+"/provides", "provides", dependency.Provides)
+		}
+	}
+}
+
+// Annotations of components are (OTHER) annotations of their packages;
+// all others, of the document
+func (converter *cyclonedxConverter) convertAnnotations() {
+	for i, annotation := range converter.bom.Annotations {
+		source := fmt.Sprintf("/annotations/%d", i)
+		var annotator string
+		switch {
+		case annotation.Annotator.Individual != nil:
+			individual := annotation.Annotator.Individual
+			annotator = spdx.Entity{Type: spdx.ENTITY_PERSON, Name: individual.Name, Email: individual.Email}.String()
+		case annotation.Annotator.Organization != nil:
+			annotator = organization(annotation.Annotator.Organization)
+		case annotation.Annotator.Component != nil:
+			component := annotation.Annotator.Component
+			annotator = spdx.Entity{Type: spdx.ENTITY_TOOL, Name: strings.TrimSuffix(component.Name+"-"+component.Version, "-")}.String()
+		case annotation.Annotator.Service != nil:
+			annotator = spdx.Entity{Type: spdx.ENTITY_TOOL, Name: annotation.Annotator.Service.Name}.String()
+			converter.report.add(source+"/annotator/service", "service mapped to a (tool) annotator")
+		}
+
+		for j, subject := range annotation.Subjects {
+			converted := &spdx.Annotation{
+				Annotator:      annotator,
+				AnnotationDate: converter.convertTime(source+"/timestamp", annotation.Timestamp),
+				AnnotationType: ANNOTATION_TYPE_OTHER,
+				Comment:        annotation.Text,
+			}
+			if pkg := converter.packages[converter.refs[subject]]; pkg != nil {
+				pkg.Annotations = append(pkg.Annotations, converted)
+			} else {
+				converter.document.Annotations = append(converter.document.Annotations, converted)
+				converter.report.add(fmt.Sprintf("%s/subjects/%d", source, j), "subject `%s` is not a component; annotates the document", subject)
+			}
+		}
+	}
+}
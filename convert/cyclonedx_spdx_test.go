@@ -0,0 +1,185 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/cyclonedx"
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/mrutkows/go-skeleton/spdx"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const TEST_TOOL = "go-skeleton-1.0.0"
+
+func parseCycloneDXFile(t *testing.T, filename string) *cyclonedx.Bom {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	bom, err := cyclonedx.ParseJSON(file)
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return bom
+}
+
+// Every CycloneDX example must convert to a valid (and consistent) SPDX
+// document, both as JSON and as tag-value
+func TestCycloneDXToSpdxValidates(t *testing.T) {
+	filenames, err := filepath.Glob("../cyclonedx/testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	embeddedSchema, err := schema.Lookup(schema.FORMAT_SPDX, "2.3")
+	assert.NoError(t, err)
+	jsonSchema, err := embeddedSchema.Compile()
+	assert.NoError(t, err)
+
+	for _, filename := range filenames {
+		document, _, err := CycloneDXToSpdx(parseCycloneDXFile(t, filename), TEST_TOOL)
+		assert.NoError(t, err)
+		assert.Empty(t, document.Check(), filename)
+
+		var output bytes.Buffer
+		assert.NoError(t, document.WriteJSON(&output))
+		result, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(output.Bytes()))
+		assert.NoError(t, err)
+		assert.True(t, result.Valid(), "%s: %v", filename, result.Errors())
+
+		output.Reset()
+		assert.NoError(t, document.WriteTagValue(&output))
+		parsed, err := spdx.ParseTagValue(&output)
+		if assert.NoError(t, err, filename) {
+			assert.Empty(t, parsed.Check(), filename)
+			assert.Equal(t, len(document.Packages), len(parsed.Packages), filename)
+			assert.Equal(t, len(document.Relationships), len(parsed.Relationships), filename)
+		}
+	}
+}
+
+func TestCycloneDXToSpdx(t *testing.T) {
+	bom := &cyclonedx.Bom{
+		BOMFormat:    cyclonedx.BOM_FORMAT,
+		SpecVersion:  cyclonedx.SPEC_VERSION_1_6,
+		SerialNumber: "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79",
+		Metadata: &cyclonedx.Metadata{
+			Timestamp: "2024-01-02T03:04:05.678+01:00",
+			Tools:     &cyclonedx.Tools{Components: []cyclonedx.Component{{Type: "application", Name: "syft", Version: "1.0"}}},
+			Authors:   []cyclonedx.OrganizationalContact{{Name: "Jane Doe", Email: "jane@example.com"}},
+			Component: &cyclonedx.Component{
+				Type:    "application",
+				BOMRef:  "pkg:golang/app@1.0",
+				Name:    "app",
+				Version: "1.0",
+				Components: []cyclonedx.Component{{
+					Type:     "library",
+					BOMRef:   "lib",
+					Name:     "lib",
+					Version:  "2.0",
+					Scope:    "optional",
+					PURL:     "pkg:golang/lib@2.0",
+					CPE:      "cpe:2.3:a:acme:lib:2.0:*:*:*:*:*:*:*",
+					Hashes:   []cyclonedx.Hash{{Algorithm: "SHA-256", Value: "abc"}, {Algorithm: "SHA-256-X", Value: "def"}},
+					Licenses: []cyclonedx.LicenseChoice{{License: &cyclonedx.License{ID: "MIT"}}, {License: &cyclonedx.License{Name: "Acme License", Acknowledgement: "concluded"}}},
+					ExternalReferences: []cyclonedx.ExternalReference{
+						{Type: "website", URL: "https://example.com/lib"},
+						{Type: "vcs", URL: "git+https://example.com/lib.git"},
+						{Type: "issue-tracker", URL: "https://example.com/lib/issues"},
+					},
+				}},
+			},
+		},
+		Components: []cyclonedx.Component{{Type: "platform", Name: "runtime", Version: "3.0"}},
+		Dependencies: []cyclonedx.Dependency{
+			{Ref: "pkg:golang/app@1.0", DependsOn: []string{"lib", "unknown"}},
+			{Ref: "lib"},
+		},
+		Annotations: []cyclonedx.Annotation{{
+			Subjects:  []string{"lib"},
+			Annotator: cyclonedx.Annotator{Individual: &cyclonedx.OrganizationalContact{Name: "John Doe"}},
+			Timestamp: "2024-01-02T00:00:00Z",
+			Text:      "reviewed",
+		}},
+	}
+	document, report, err := CycloneDXToSpdx(bom, TEST_TOOL)
+	assert.NoError(t, err)
+	assert.Empty(t, document.Check())
+
+	assert.Equal(t, "app-1.0", document.Name)
+	assert.Equal(t, "https://spdx.org/spdxdocs/app-1.0-3e671687-395b-41f5-a30f-a58921a69b79", document.DocumentNamespace)
+	assert.Equal(t, "2024-01-02T02:04:05Z", document.CreationInfo.Created)
+	assert.Equal(t, []string{"Tool: go-skeleton-1.0.0", "Tool: syft-1.0", "Person: Jane Doe (jane@example.com)"}, document.CreationInfo.Creators)
+
+	// SPDXIDs are generated from bom-refs (or names)
+	var spdxIds []string
+	for _, pkg := range document.Packages {
+		spdxIds = append(spdxIds, pkg.SPDXID)
+	}
+	assert.Equal(t, []string{"SPDXRef-pkg-golang-app-1.0", "SPDXRef-lib", "SPDXRef-runtime-3.0"}, spdxIds)
+
+	lib := document.Package("SPDXRef-lib")
+	assert.Equal(t, "LIBRARY", lib.PrimaryPackagePurpose)
+	assert.Equal(t, "https://example.com/lib", lib.Homepage)
+	assert.Equal(t, "git+https://example.com/lib.git", lib.DownloadLocation)
+	assert.Equal(t, []spdx.Checksum{{Algorithm: "SHA256", ChecksumValue: "abc"}}, lib.Checksums)
+	assert.Equal(t, "MIT", lib.LicenseDeclared)
+	assert.Equal(t, "LicenseRef-Acme-License", lib.LicenseConcluded)
+	assert.Equal(t, "LicenseRef-Acme-License", document.HasExtractedLicensingInfos[0].LicenseId)
+	assert.Equal(t, []*spdx.ExternalRef{
+		{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/lib@2.0"},
+		{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: "cpe:2.3:a:acme:lib:2.0:*:*:*:*:*:*:*"},
+		{ReferenceCategory: "OTHER", ReferenceType: "issue-tracker", ReferenceLocator: "https://example.com/lib/issues"},
+	}, lib.ExternalRefs)
+
+	// fields without an SPDX equivalent are kept as annotations
+	var comments []string
+	for _, annotation := range lib.Annotations {
+		comments = append(comments, annotation.Comment)
+	}
+	assert.Equal(t, []string{`cdx:hash: SHA-256-X: def`, `cdx:scope: optional`, `reviewed`}, comments)
+	assert.Equal(t, "Person: John Doe", lib.Annotations[2].Annotator)
+	assert.Equal(t, "cdx:dependsOn: unknown", document.Package("SPDXRef-pkg-golang-app-1.0").Annotations[0].Comment)
+	assert.Equal(t, "OTHER", document.Package("SPDXRef-runtime-3.0").PrimaryPackagePurpose)
+
+	var relationships []string
+	for _, relationship := range document.Relationships {
+		relationships = append(relationships, relationship.SPDXElementID+" "+relationship.RelationshipType+" "+relationship.RelatedSPDXElement)
+	}
+	assert.Equal(t, []string{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-pkg-golang-app-1.0",
+		"SPDXRef-pkg-golang-app-1.0 CONTAINS SPDXRef-lib",
+		"SPDXRef-pkg-golang-app-1.0 DEPENDS_ON SPDXRef-lib",
+		"SPDXRef-lib DEPENDS_ON NONE",
+	}, relationships)
+
+	var sources []string
+	for _, approximation := range report.Approximations {
+		sources = append(sources, approximation.Source)
+	}
+	assert.Contains(t, sources, "/metadata/component/components/0/externalReferences/2")
+	assert.Contains(t, sources, "/components/0/type")
+	assert.Contains(t, sources, "/dependencies/0/dependsOn/1")
+}
@@ -0,0 +1,146 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+// Mappings between SPDX and CycloneDX enumerated values
+
+// Namespace of the CycloneDX properties that keep SPDX fields
+// without a CycloneDX equivalent (e.g., "spdx:licenseComments")
+const PROPERTY_PREFIX = "spdx:"
+
+// SPDX checksum algorithm to CycloneDX hash algorithm
+var hashAlgorithms = map[string]string{
+	"MD5":         "MD5",
+	"SHA1":        "SHA-1",
+	"SHA256":      "SHA-256",
+	"SHA384":      "SHA-384",
+	"SHA512":      "SHA-512",
+	"SHA3-256":    "SHA3-256",
+	"SHA3-384":    "SHA3-384",
+	"SHA3-512":    "SHA3-512",
+	"BLAKE2b-256": "BLAKE2b-256",
+	"BLAKE2b-384": "BLAKE2b-384",
+	"BLAKE2b-512": "BLAKE2b-512",
+	"BLAKE3":      "BLAKE3",
+}
+
+// CycloneDX hash algorithm to SPDX checksum algorithm
+var checksumAlgorithms = invert(hashAlgorithms)
+
+// SPDX (2.3) primary package purpose to CycloneDX component type; purposes
+// not listed here have no CycloneDX equivalent. Note: tag-value documents
+// use "OPERATING-SYSTEM", JSON documents "OPERATING_SYSTEM".
+var componentTypes = map[string]string{
+	"APPLICATION":      "application",
+	"FRAMEWORK":        "framework",
+	"LIBRARY":          "library",
+	"CONTAINER":        "container",
+	"OPERATING-SYSTEM": "operating-system",
+	"OPERATING_SYSTEM": "operating-system",
+	"DEVICE":           "device",
+	"FIRMWARE":         "firmware",
+	"FILE":             "file",
+}
+
+// CycloneDX component type to SPDX primary package purpose; types not
+// listed here (e.g., "platform") have no SPDX equivalent
+var packagePurposes = map[string]string{
+	"application":      "APPLICATION",
+	"framework":        "FRAMEWORK",
+	"library":          "LIBRARY",
+	"container":        "CONTAINER",
+	"operating-system": "OPERATING_SYSTEM",
+	"device":           "DEVICE",
+	"firmware":         "FIRMWARE",
+	"file":             "FILE",
+}
+
+const PACKAGE_PURPOSE_OTHER = "OTHER"
+
+const (
+	COMPONENT_TYPE_LIBRARY     = "library"
+	COMPONENT_TYPE_FILE        = "file"
+	COMPONENT_TYPE_APPLICATION = "application"
+)
+
+// SPDX relationships that (after normalizing their direction) describe
+// "<element> depends on <related element>"; the value is true where the
+// relationship is stated the other way around (e.g., "DEPENDENCY_OF")
+var dependencyRelationships = map[string]bool{
+	"DEPENDS_ON":             false,
+	"STATIC_LINK":            false,
+	"DYNAMIC_LINK":           false,
+	"DEPENDENCY_OF":          true,
+	"BUILD_DEPENDENCY_OF":    true,
+	"DEV_DEPENDENCY_OF":      true,
+	"OPTIONAL_DEPENDENCY_OF": true,
+	"PROVIDED_DEPENDENCY_OF": true,
+	"RUNTIME_DEPENDENCY_OF":  true,
+	"TEST_DEPENDENCY_OF":     true,
+}
+
+const (
+	RELATIONSHIP_DESCRIBES    = "DESCRIBES"
+	RELATIONSHIP_DESCRIBED_BY = "DESCRIBED_BY"
+	RELATIONSHIP_CONTAINS     = "CONTAINS"
+	RELATIONSHIP_CONTAINED_BY = "CONTAINED_BY"
+	RELATIONSHIP_DEPENDS_ON   = "DEPENDS_ON"
+)
+
+// SPDX external reference types mapped to component identifiers
+const (
+	EXTERNAL_REF_PURL   = "purl"
+	EXTERNAL_REF_CPE22  = "cpe22Type"
+	EXTERNAL_REF_CPE23  = "cpe23Type"
+	EXTERNAL_REF_SWH    = "swh"
+	EXTERNAL_REF_GITOID = "gitoid"
+
+	EXTERNAL_REF_ADVISORY = "advisory"
+)
+
+// SPDX (2.3) external reference categories
+const (
+	REFERENCE_CATEGORY_PACKAGE_MANAGER = "PACKAGE-MANAGER"
+	REFERENCE_CATEGORY_SECURITY        = "SECURITY"
+	REFERENCE_CATEGORY_PERSISTENT_ID   = "PERSISTENT-ID"
+	REFERENCE_CATEGORY_OTHER           = "OTHER"
+)
+
+// SPDX external reference types (with URL locators) mapped to CycloneDX
+// external references; all others are kept as properties
+var externalReferenceTypes = map[string]string{
+	EXTERNAL_REF_ADVISORY: "advisories",
+	"url":                 "website",
+}
+
+// CycloneDX external reference types with an SPDX package field
+const (
+	EXTERNAL_REFERENCE_WEBSITE      = "website"
+	EXTERNAL_REFERENCE_DISTRIBUTION = "distribution"
+	EXTERNAL_REFERENCE_VCS          = "vcs"
+	EXTERNAL_REFERENCE_ADVISORIES   = "advisories"
+	EXTERNAL_REFERENCE_BOM          = "bom"
+)
+
+func invert(mapping map[string]string) map[string]string {
+	inverted := make(map[string]string, len(mapping))
+	for key, value := range mapping {
+		inverted[value] = key
+	}
+	return inverted
+}
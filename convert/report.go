@@ -0,0 +1,58 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"fmt"
+	"io"
+)
+
+// A source field that could not be converted as is; it was either mapped to
+// the closest equivalent, kept as a (namespaced) property or dropped
+type Approximation struct {
+	Source  string `json:"source"`  // e.g., "SPDXRef-Package/licenseComments"
+	Message string `json:"message"` // e.g., "kept as property `spdx:licenseComments`"
+}
+
+func (approximation Approximation) String() string {
+	return fmt.Sprintf("%s: %s", approximation.Source, approximation.Message)
+}
+
+// The approximations made while converting a document
+type Report struct {
+	Approximations []Approximation `json:"approximations"`
+}
+
+func (report *Report) add(source string, format string, a ...interface{}) {
+	report.Approximations = append(report.Approximations, Approximation{
+		Source:  source,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// Write the report as (human-readable) text
+func (report *Report) Write(writer io.Writer) (err error) {
+	_, err = fmt.Fprintf(writer, "Conversion report: %d field(s) approximated\n", len(report.Approximations))
+	for _, approximation := range report.Approximations {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(writer, "  %s\n", approximation)
+	}
+	return
+}
@@ -0,0 +1,669 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mrutkows/go-skeleton/cyclonedx"
+	"github.com/mrutkows/go-skeleton/spdx"
+)
+
+// SpdxToCycloneDX converts an SPDX document to a CycloneDX BOM of the given
+// spec. version. Packages and files become (nested) components, relationships
+// become dependencies (or nesting) and SPDX fields without an equivalent are
+// kept as "spdx:" properties; the report lists every such approximation.
+func SpdxToCycloneDX(document *spdx.Document, specVersion string) (*cyclonedx.Bom, *Report, error) {
+	if !cyclonedx.IsSupported(specVersion) {
+		return nil, nil, fmt.Errorf("unsupported CycloneDX spec. version: `%s` (expected one of: %s)",
+			specVersion, strings.Join(cyclonedx.SpecVersions, ", "))
+	}
+
+	converter := &spdxConverter{
+		document:     document,
+		specVersion:  specVersion,
+		report:       &Report{},
+		components:   map[string]*cyclonedx.Component{},
+		parents:      map[string]string{},
+		children:     map[string][]string{},
+		dependencies: map[string]*cyclonedx.Dependency{},
+	}
+	bom := converter.convert()
+
+	// Remove anything (still) not supported by the spec. version
+	if err := convertVersion(bom, specVersion, converter.report); err != nil {
+		return nil, nil, err
+	}
+	return bom, converter.report, nil
+}
+
+// CycloneDXToVersion converts the BOM (in place) to another CycloneDX spec.
+// version; whatever that version cannot represent is listed in the report.
+func CycloneDXToVersion(bom *cyclonedx.Bom, specVersion string) (*Report, error) {
+	report := &Report{}
+	if err := convertVersion(bom, specVersion, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func convertVersion(bom *cyclonedx.Bom, specVersion string, report *Report) error {
+	incompatibilities, err := bom.ConvertTo(specVersion)
+	if err != nil {
+		return err
+	}
+	for _, incompatibility := range incompatibilities {
+		report.add(incompatibility.Pointer, "%s", incompatibility.Message)
+	}
+	return nil
+}
+
+type spdxConverter struct {
+	document    *spdx.Document
+	specVersion string
+	report      *Report
+	metadata    *cyclonedx.Metadata
+
+	// Components (by SPDXID) in document order and how they are nested
+	components map[string]*cyclonedx.Component
+	order      []string
+	parents    map[string]string
+	children   map[string][]string
+	described  []string
+
+	dependencies    map[string]*cyclonedx.Dependency
+	dependencyOrder []string
+	annotations     []cyclonedx.Annotation
+}
+
+func (converter *spdxConverter) supports(specVersion string) bool {
+	return cyclonedx.CompareVersions(converter.specVersion, specVersion) >= 0
+}
+
+// Keep a field without CycloneDX equivalent as an "spdx:" property
+func (converter *spdxConverter) keep(properties *[]cyclonedx.Property, source string, name string, value string) {
+	if value == "" {
+		return
+	}
+	*properties = append(*properties, cyclonedx.Property{Name: PROPERTY_PREFIX + name, Value: value})
+	converter.report.add(source, "kept as property `%s%s`", PROPERTY_PREFIX, name)
+}
+
+// Keep a structured field (or object) as a JSON-encoded "spdx:" property
+func (converter *spdxConverter) keepJSON(properties *[]cyclonedx.Property, source string, name string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		converter.report.add(source, "dropped; unable to encode as property: %s", err)
+		return
+	}
+	converter.keep(properties, source, name, string(data))
+}
+
+func (converter *spdxConverter) keepExtra(properties *[]cyclonedx.Property, spdxId string, extra map[string]json.RawMessage) {
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		converter.keep(properties, spdxId+"/"+name, name, string(extra[name]))
+	}
+}
+
+func isAssertion(value string) bool {
+	return value != "" && value != spdx.NOASSERTION && value != spdx.NONE
+}
+
+func (converter *spdxConverter) convert() *cyclonedx.Bom {
+	document := converter.document
+	bom := &cyclonedx.Bom{
+		BOMFormat:   cyclonedx.BOM_FORMAT,
+		SpecVersion: converter.specVersion,
+		Version:     1,
+	}
+	converter.metadata = &cyclonedx.Metadata{Timestamp: document.CreationInfo.Created}
+	bom.Metadata = converter.metadata
+
+	for _, pkg := range document.Packages {
+		converter.addComponent(pkg.SPDXID, converter.convertPackage(pkg))
+	}
+	for _, file := range document.Files {
+		converter.addComponent(file.SPDXID, converter.convertFile(file))
+	}
+	for _, pkg := range document.Packages {
+		for _, spdxId := range pkg.HasFiles {
+			converter.nest(pkg.SPDXID, spdxId)
+		}
+	}
+	converter.convertSnippets()
+	converter.convertDocument(bom)
+	converter.convertRelationships()
+
+	// Assemble the (nested) components; the single element the document
+	// describes (if any) becomes the BOM's (metadata) component
+	root := ""
+	if len(converter.described) == 1 && converter.components[converter.described[0]] != nil &&
+		converter.parents[converter.described[0]] == "" {
+		root = converter.described[0]
+	} else if len(converter.described) > 1 {
+		converter.keepJSON(&converter.metadata.Properties, spdx.SPDXID_DOCUMENT+"/documentDescribes",
+			"documentDescribes", converter.described)
+	}
+	for _, spdxId := range converter.order {
+		if converter.parents[spdxId] != "" {
+			continue
+		}
+		component := converter.assemble(spdxId)
+		if spdxId == root {
+			converter.metadata.Component = &component
+		} else {
+			bom.Components = append(bom.Components, component)
+		}
+	}
+
+	for _, spdxId := range converter.dependencyOrder {
+		bom.Dependencies = append(bom.Dependencies, *converter.dependencies[spdxId])
+	}
+	bom.Annotations = converter.annotations
+	return bom
+}
+
+func (converter *spdxConverter) addComponent(spdxId string, component *cyclonedx.Component) {
+	// SPDX files (and packages) need not have a version; before 1.4, components must
+	if component.Version == "" && !converter.supports(cyclonedx.SPEC_VERSION_1_4) {
+		component.Version = spdx.NOASSERTION
+		converter.report.add(spdxId, "component version is required before CycloneDX %s; set to `%s`",
+			cyclonedx.SPEC_VERSION_1_4, spdx.NOASSERTION)
+	}
+	converter.components[spdxId] = component
+	converter.order = append(converter.order, spdxId)
+}
+
+func (converter *spdxConverter) assemble(spdxId string) cyclonedx.Component {
+	component := *converter.components[spdxId]
+	for _, child := range converter.children[spdxId] {
+		component.Components = append(component.Components, converter.assemble(child))
+	}
+	return component
+}
+
+// Nest the child component within its parent (i.e., "CONTAINS"); returns
+// false where that is not possible (e.g., the child is nested elsewhere)
+func (converter *spdxConverter) nest(parent string, child string) bool {
+	if converter.components[parent] == nil || converter.components[child] == nil || parent == child {
+		return false
+	}
+	if current, nested := converter.parents[child]; nested {
+		return current == parent
+	}
+	for ancestor := parent; ancestor != ""; ancestor = converter.parents[ancestor] {
+		if ancestor == child {
+			return false // i.e., a cycle
+		}
+	}
+	converter.parents[child] = parent
+	converter.children[parent] = append(converter.children[parent], child)
+	return true
+}
+
+// ------------------------------------------------------------------------
+// Document
+// ------------------------------------------------------------------------
+
+func (converter *spdxConverter) convertDocument(bom *cyclonedx.Bom) {
+	document := converter.document
+	metadata := converter.metadata
+	source := func(field string) string {
+		return spdx.SPDXID_DOCUMENT + "/" + field
+	}
+
+	if document.DocumentNamespace != "" {
+		// a (version 5) UUID derived from the namespace; the same document
+		// always converts to the same serial number
+		bom.SerialNumber = "urn:uuid:" + uuidFromURL(document.DocumentNamespace)
+		converter.keep(&metadata.Properties, source("documentNamespace"), "documentNamespace", document.DocumentNamespace)
+	}
+	converter.keep(&metadata.Properties, source("spdxVersion"), "spdxVersion", document.SPDXVersion)
+	converter.keep(&metadata.Properties, source("name"), "name", document.Name)
+	converter.keep(&metadata.Properties, source("comment"), "comment", document.Comment)
+	converter.keep(&metadata.Properties, source("creationInfo/licenseListVersion"), "licenseListVersion", document.CreationInfo.LicenseListVersion)
+	converter.keep(&metadata.Properties, source("creationInfo/comment"), "creatorComment", document.CreationInfo.Comment)
+
+	for i, creator := range document.CreationInfo.Creators {
+		converter.convertCreator(source(fmt.Sprintf("creationInfo/creators/%d", i)), creator)
+	}
+
+	for _, ref := range document.ExternalDocumentRefs {
+		externalReference := cyclonedx.ExternalReference{
+			URL:     ref.SpdxDocument,
+			Type:    EXTERNAL_REFERENCE_BOM,
+			Comment: ref.ExternalDocumentId,
+		}
+		if algorithm, found := hashAlgorithms[ref.Checksum.Algorithm]; found {
+			externalReference.Hashes = []cyclonedx.Hash{{Algorithm: algorithm, Value: ref.Checksum.ChecksumValue}}
+		}
+		bom.ExternalReferences = append(bom.ExternalReferences, externalReference)
+		converter.report.add(source("externalDocumentRefs/"+ref.ExternalDocumentId),
+			"mapped to an external reference of type `bom` (with the reference ID as comment)")
+	}
+
+	for _, spdxId := range document.DocumentDescribes {
+		converter.describe(spdxId)
+	}
+	for i, info := range document.HasExtractedLicensingInfos {
+		converter.keepJSON(&metadata.Properties, source(fmt.Sprintf("hasExtractedLicensingInfos/%d", i)), "extractedLicensingInfo", info)
+	}
+	for i, annotation := range document.Annotations {
+		converter.keepJSON(&metadata.Properties, source(fmt.Sprintf("annotations/%d", i)), "annotation", annotation)
+	}
+	for i, review := range document.Reviews {
+		converter.keepJSON(&metadata.Properties, source(fmt.Sprintf("revieweds/%d", i)), "review", review)
+	}
+	converter.keepExtra(&metadata.Properties, spdx.SPDXID_DOCUMENT, document.Extra)
+}
+
+// Tools become (tool) components; people and organizations, BOM authors
+func (converter *spdxConverter) convertCreator(source string, creator string) {
+	metadata := converter.metadata
+	entity, ok := spdx.ParseEntity(creator)
+	if !ok {
+		converter.keep(&metadata.Properties, source, "creator", creator)
+		return
+	}
+
+	switch entity.Type {
+	case spdx.ENTITY_TOOL:
+		name, version := splitToolVersion(entity.Name)
+		if metadata.Tools == nil {
+			metadata.Tools = &cyclonedx.Tools{}
+		}
+		if converter.supports(cyclonedx.SPEC_VERSION_1_5) {
+			metadata.Tools.Components = append(metadata.Tools.Components, cyclonedx.Component{
+				Type:    COMPONENT_TYPE_APPLICATION,
+				Name:    name,
+				Version: version,
+			})
+		} else {
+			metadata.Tools.Tools = append(metadata.Tools.Tools, cyclonedx.Tool{Name: name, Version: version})
+		}
+	case spdx.ENTITY_ORGANIZATION:
+		converter.report.add(source, "organization mapped to a BOM author")
+		fallthrough
+	default:
+		metadata.Authors = append(metadata.Authors, cyclonedx.OrganizationalContact{Name: entity.Name, Email: entity.Email})
+	}
+}
+
+// Tool names are (by convention) suffixed by their version, e.g., "tool-1.2.3"
+func splitToolVersion(tool string) (name string, version string) {
+	if separator := strings.LastIndex(tool, "-"); separator > 0 && separator < len(tool)-1 {
+		if next := tool[separator+1]; next >= '0' && next <= '9' {
+			return tool[:separator], tool[separator+1:]
+		}
+	}
+	return tool, ""
+}
+
+func (converter *spdxConverter) describe(spdxId string) {
+	for _, described := range converter.described {
+		if described == spdxId {
+			return
+		}
+	}
+	converter.described = append(converter.described, spdxId)
+}
+
+// ------------------------------------------------------------------------
+// Packages, files and snippets
+// ------------------------------------------------------------------------
+
+func (converter *spdxConverter) convertPackage(pkg *spdx.Package) *cyclonedx.Component {
+	source := func(field string) string {
+		return pkg.SPDXID + "/" + field
+	}
+	component := &cyclonedx.Component{
+		Type:        COMPONENT_TYPE_LIBRARY,
+		BOMRef:      pkg.SPDXID,
+		Name:        pkg.Name,
+		Version:     pkg.VersionInfo,
+		Description: pkg.Description,
+	}
+	properties := &component.Properties
+
+	if pkg.PrimaryPackagePurpose != "" {
+		if componentType, found := componentTypes[pkg.PrimaryPackagePurpose]; found {
+			component.Type = componentType
+		} else {
+			converter.keep(properties, source("primaryPackagePurpose"), "primaryPackagePurpose", pkg.PrimaryPackagePurpose)
+		}
+	}
+
+	if supplier, ok := spdx.ParseEntity(pkg.Supplier); ok {
+		component.Supplier = &cyclonedx.OrganizationalEntity{Name: supplier.Name}
+		if supplier.Email != "" {
+			component.Supplier.Contact = []cyclonedx.OrganizationalContact{{Email: supplier.Email}}
+		}
+		if supplier.Type != spdx.ENTITY_ORGANIZATION {
+			converter.report.add(source("supplier"), "%s mapped to a supplier (organization)", strings.ToLower(supplier.Type))
+		}
+	}
+	if originator, ok := spdx.ParseEntity(pkg.Originator); ok {
+		component.Author = originator.Name
+		converter.report.add(source("originator"), "mapped to the component author")
+	}
+
+	if isAssertion(pkg.DownloadLocation) {
+		referenceType := EXTERNAL_REFERENCE_DISTRIBUTION
+		if strings.HasPrefix(pkg.DownloadLocation, "git+") || strings.HasPrefix(pkg.DownloadLocation, "svn+") ||
+			strings.HasPrefix(pkg.DownloadLocation, "hg+") || strings.HasPrefix(pkg.DownloadLocation, "bzr+") {
+			referenceType = EXTERNAL_REFERENCE_VCS
+		}
+		component.ExternalReferences = append(component.ExternalReferences,
+			cyclonedx.ExternalReference{URL: pkg.DownloadLocation, Type: referenceType})
+	}
+	if isAssertion(pkg.Homepage) {
+		component.ExternalReferences = append(component.ExternalReferences,
+			cyclonedx.ExternalReference{URL: pkg.Homepage, Type: EXTERNAL_REFERENCE_WEBSITE})
+	}
+
+	component.Hashes = converter.convertChecksums(properties, pkg.SPDXID, pkg.Checksums)
+	component.Licenses = converter.convertLicenses(properties, pkg.SPDXID, pkg.LicenseDeclared, pkg.LicenseConcluded)
+	if isAssertion(pkg.CopyrightText) {
+		component.Copyright = pkg.CopyrightText
+	}
+	converter.convertExternalRefs(component, pkg.SPDXID, pkg.ExternalRefs)
+
+	if pkg.FilesAnalyzed != nil {
+		converter.keep(properties, source("filesAnalyzed"), "filesAnalyzed", strconv.FormatBool(*pkg.FilesAnalyzed))
+	}
+	if pkg.PackageVerificationCode != nil {
+		converter.keepJSON(properties, source("packageVerificationCode"), "packageVerificationCode", pkg.PackageVerificationCode)
+	}
+	if len(pkg.LicenseInfoFromFiles) > 0 {
+		converter.keepJSON(properties, source("licenseInfoFromFiles"), "licenseInfoFromFiles", pkg.LicenseInfoFromFiles)
+	}
+	converter.keep(properties, source("licenseComments"), "licenseComments", pkg.LicenseComments)
+	converter.keep(properties, source("packageFileName"), "packageFileName", pkg.PackageFileName)
+	converter.keep(properties, source("sourceInfo"), "sourceInfo", pkg.SourceInfo)
+	converter.keep(properties, source("summary"), "summary", pkg.Summary)
+	converter.keep(properties, source("comment"), "comment", pkg.Comment)
+	for i, text := range pkg.AttributionTexts {
+		converter.keep(properties, source(fmt.Sprintf("attributionTexts/%d", i)), "attributionText", text)
+	}
+	converter.keep(properties, source("releaseDate"), "releaseDate", pkg.ReleaseDate)
+	converter.keep(properties, source("builtDate"), "builtDate", pkg.BuiltDate)
+	converter.keep(properties, source("validUntilDate"), "validUntilDate", pkg.ValidUntilDate)
+
+	converter.convertAnnotations(properties, pkg.SPDXID, pkg.Annotations)
+	converter.keepExtra(properties, pkg.SPDXID, pkg.Extra)
+	return component
+}
+
+func (converter *spdxConverter) convertFile(file *spdx.File) *cyclonedx.Component {
+	source := func(field string) string {
+		return file.SPDXID + "/" + field
+	}
+	component := &cyclonedx.Component{
+		Type:   COMPONENT_TYPE_FILE,
+		BOMRef: file.SPDXID,
+		Name:   file.FileName,
+	}
+	properties := &component.Properties
+
+	component.Hashes = converter.convertChecksums(properties, file.SPDXID, file.Checksums)
+	component.Licenses = converter.convertLicenses(properties, file.SPDXID, "", file.LicenseConcluded)
+	if isAssertion(file.CopyrightText) {
+		component.Copyright = file.CopyrightText
+	}
+
+	if len(file.FileTypes) > 0 {
+		converter.keepJSON(properties, source("fileTypes"), "fileTypes", file.FileTypes)
+	}
+	if len(file.LicenseInfoInFiles) > 0 {
+		converter.keepJSON(properties, source("licenseInfoInFiles"), "licenseInfoInFiles", file.LicenseInfoInFiles)
+	}
+	converter.keep(properties, source("licenseComments"), "licenseComments", file.LicenseComments)
+	converter.keep(properties, source("comment"), "comment", file.Comment)
+	converter.keep(properties, source("noticeText"), "noticeText", file.NoticeText)
+	if len(file.FileContributors) > 0 {
+		converter.keepJSON(properties, source("fileContributors"), "fileContributors", file.FileContributors)
+	}
+	for i, text := range file.AttributionTexts {
+		converter.keep(properties, source(fmt.Sprintf("attributionTexts/%d", i)), "attributionText", text)
+	}
+	if len(file.FileDependencies) > 0 {
+		converter.keepJSON(properties, source("fileDependencies"), "fileDependencies", file.FileDependencies)
+	}
+	for i, artifactOf := range file.ArtifactOfs {
+		converter.keepJSON(properties, source(fmt.Sprintf("artifactOfs/%d", i)), "artifactOf", artifactOf)
+	}
+
+	converter.convertAnnotations(properties, file.SPDXID, file.Annotations)
+	converter.keepExtra(properties, file.SPDXID, file.Extra)
+	return component
+}
+
+// CycloneDX has no snippets; each is kept (whole) as a property of its file
+func (converter *spdxConverter) convertSnippets() {
+	for _, snippet := range converter.document.Snippets {
+		properties := &converter.metadata.Properties
+		if file := converter.components[snippet.SnippetFromFile]; file != nil {
+			properties = &file.Properties
+		}
+		converter.keepJSON(properties, snippet.SPDXID, "snippet", snippet)
+	}
+}
+
+func (converter *spdxConverter) convertChecksums(properties *[]cyclonedx.Property, spdxId string, checksums []spdx.Checksum) (hashes []cyclonedx.Hash) {
+	for i, checksum := range checksums {
+		if algorithm, found := hashAlgorithms[checksum.Algorithm]; found {
+			hashes = append(hashes, cyclonedx.Hash{Algorithm: algorithm, Value: checksum.ChecksumValue})
+		} else {
+			converter.keep(properties, fmt.Sprintf("%s/checksums/%d", spdxId, i), "checksum",
+				checksum.Algorithm+": "+checksum.ChecksumValue)
+		}
+	}
+	return
+}
+
+// Since 1.6, both the declared and concluded license are kept (distinguished
+// by their "acknowledgement") unless either is an expression, which must be
+// the only license. Otherwise, the declared (or, if none, the concluded)
+// license is used and any other is kept as a property.
+func (converter *spdxConverter) convertLicenses(properties *[]cyclonedx.Property, spdxId string, declared string, concluded string) (licenses []cyclonedx.LicenseChoice) {
+	acknowledge := converter.supports(cyclonedx.SPEC_VERSION_1_6)
+	if isAssertion(declared) {
+		licenses = append(licenses, licenseChoice(declared, "declared"))
+	}
+	if isAssertion(concluded) {
+		licenses = append(licenses, licenseChoice(concluded, "concluded"))
+	}
+
+	if len(licenses) == 2 && (!acknowledge || licenses[0].License == nil || licenses[1].License == nil) {
+		licenses = licenses[:1]
+		if concluded != declared {
+			converter.keep(properties, spdxId+"/licenseConcluded", "licenseConcluded", concluded)
+		}
+	} else if len(licenses) == 1 && !isAssertion(declared) && !acknowledge {
+		converter.report.add(spdxId+"/licenseConcluded", "concluded license mapped to the component license")
+	}
+	if !acknowledge {
+		for i := range licenses {
+			licenses[i].Acknowledgement = ""
+			if licenses[i].License != nil {
+				licenses[i].License.Acknowledgement = ""
+			}
+		}
+	}
+
+	// "NONE" (i.e., no license) has no equivalent
+	if declared == spdx.NONE {
+		converter.keep(properties, spdxId+"/licenseDeclared", "licenseDeclared", declared)
+	}
+	if concluded == spdx.NONE {
+		converter.keep(properties, spdxId+"/licenseConcluded", "licenseConcluded", concluded)
+	}
+	return
+}
+
+// A single SPDX license ID becomes a license; anything else (including a
+// "LicenseRef-"), a license expression
+func licenseChoice(expression string, acknowledgement string) cyclonedx.LicenseChoice {
+	if strings.ContainsAny(expression, " ()") || strings.Contains(expression, spdx.LICENSE_REF_PREFIX) {
+		return cyclonedx.LicenseChoice{Expression: expression, Acknowledgement: acknowledgement}
+	}
+	return cyclonedx.LicenseChoice{License: &cyclonedx.License{ID: expression, Acknowledgement: acknowledgement}}
+}
+
+// Package URLs, CPEs and (since 1.6) SWHIDs and gitoids become component
+// identifiers; advisories and URLs, external references
+func (converter *spdxConverter) convertExternalRefs(component *cyclonedx.Component, spdxId string, refs []*spdx.ExternalRef) {
+	for i, ref := range refs {
+		source := fmt.Sprintf("%s/externalRefs/%d", spdxId, i)
+		mapped := true
+		switch {
+		case ref.ReferenceType == EXTERNAL_REF_PURL && component.PURL == "":
+			component.PURL = ref.ReferenceLocator
+		case (ref.ReferenceType == EXTERNAL_REF_CPE22 || ref.ReferenceType == EXTERNAL_REF_CPE23) && component.CPE == "":
+			component.CPE = ref.ReferenceLocator
+		case ref.ReferenceType == EXTERNAL_REF_SWH && converter.supports(cyclonedx.SPEC_VERSION_1_6):
+			component.SWHID = append(component.SWHID, ref.ReferenceLocator)
+		case ref.ReferenceType == EXTERNAL_REF_GITOID && converter.supports(cyclonedx.SPEC_VERSION_1_6):
+			component.OmniborID = append(component.OmniborID, ref.ReferenceLocator)
+		case externalReferenceTypes[ref.ReferenceType] != "":
+			component.ExternalReferences = append(component.ExternalReferences, cyclonedx.ExternalReference{
+				URL:     ref.ReferenceLocator,
+				Type:    externalReferenceTypes[ref.ReferenceType],
+				Comment: ref.Comment,
+			})
+			continue
+		default:
+			mapped = false
+		}
+
+		// keep the (whole) reference where its category or comment would be lost
+		if !mapped || ref.Comment != "" {
+			converter.keepJSON(&component.Properties, source, "externalRef", ref)
+		}
+	}
+}
+
+// Annotations are supported since 1.5; before, they are kept as properties
+func (converter *spdxConverter) convertAnnotations(properties *[]cyclonedx.Property, spdxId string, annotations []*spdx.Annotation) {
+	for i, annotation := range annotations {
+		source := fmt.Sprintf("%s/annotations/%d", spdxId, i)
+		entity, ok := spdx.ParseEntity(annotation.Annotator)
+		if !ok || !converter.supports(cyclonedx.SPEC_VERSION_1_5) {
+			converter.keepJSON(properties, source, "annotation", annotation)
+			continue
+		}
+
+		var annotator cyclonedx.Annotator
+		switch entity.Type {
+		case spdx.ENTITY_TOOL:
+			name, version := splitToolVersion(entity.Name)
+			annotator.Component = &cyclonedx.Component{Type: COMPONENT_TYPE_APPLICATION, Name: name, Version: version}
+		case spdx.ENTITY_ORGANIZATION:
+			annotator.Organization = &cyclonedx.OrganizationalEntity{Name: entity.Name}
+			if entity.Email != "" {
+				annotator.Organization.Contact = []cyclonedx.OrganizationalContact{{Email: entity.Email}}
+			}
+		default:
+			annotator.Individual = &cyclonedx.OrganizationalContact{Name: entity.Name, Email: entity.Email}
+		}
+		converter.annotations = append(converter.annotations, cyclonedx.Annotation{
+			Subjects:  []string{spdxId},
+			Annotator: annotator,
+			Timestamp: annotation.AnnotationDate,
+			Text:      annotation.Comment,
+		})
+		converter.report.add(source+"/annotationType", "annotation type `%s` has no equivalent; dropped", annotation.AnnotationType)
+	}
+}
+
+// ------------------------------------------------------------------------
+// Relationships
+// ------------------------------------------------------------------------
+
+func (converter *spdxConverter) convertRelationships() {
+	for i, relationship := range converter.document.Relationships {
+		element, related := relationship.SPDXElementID, relationship.RelatedSPDXElement
+		mapped := false
+		switch relationship.RelationshipType {
+		case RELATIONSHIP_DESCRIBES:
+			if mapped = element == spdx.SPDXID_DOCUMENT; mapped {
+				converter.describe(related)
+			}
+		case RELATIONSHIP_DESCRIBED_BY:
+			if mapped = related == spdx.SPDXID_DOCUMENT; mapped {
+				converter.describe(element)
+			}
+		case RELATIONSHIP_CONTAINS:
+			mapped = converter.nest(element, related)
+		case RELATIONSHIP_CONTAINED_BY:
+			mapped = converter.nest(related, element)
+		default:
+			if reversed, found := dependencyRelationships[relationship.RelationshipType]; found {
+				if reversed {
+					element, related = related, element
+				}
+				if mapped = converter.addDependency(element, related); mapped && relationship.RelationshipType != RELATIONSHIP_DEPENDS_ON {
+					converter.report.add(fmt.Sprintf("%s/relationships/%d", spdx.SPDXID_DOCUMENT, i),
+						"`%s` mapped to a dependency", relationship.RelationshipType)
+				}
+			}
+		}
+
+		// keep the (whole) relationship where it (or its comment) would be lost
+		if !mapped || relationship.Comment != "" {
+			properties := &converter.metadata.Properties
+			if component := converter.components[relationship.SPDXElementID]; component != nil {
+				properties = &component.Properties
+			}
+			converter.keepJSON(properties, fmt.Sprintf("%s/relationships/%d", spdx.SPDXID_DOCUMENT, i), "relationship", relationship)
+		}
+	}
+}
+
+// Record that the component depends on the related component; a related
+// element of NONE records that the component has no dependencies
+func (converter *spdxConverter) addDependency(spdxId string, related string) bool {
+	if converter.components[spdxId] == nil || (related != spdx.NONE && converter.components[related] == nil) {
+		return false
+	}
+	dependency, found := converter.dependencies[spdxId]
+	if !found {
+		dependency = &cyclonedx.Dependency{Ref: spdxId}
+		converter.dependencies[spdxId] = dependency
+		converter.dependencyOrder = append(converter.dependencyOrder, spdxId)
+	}
+	if related == spdx.NONE {
+		return true
+	}
+	for _, ref := range dependency.DependsOn {
+		if ref == related {
+			return true
+		}
+	}
+	dependency.DependsOn = append(dependency.DependsOn, related)
+	return true
+}
@@ -0,0 +1,189 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/cyclonedx"
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/mrutkows/go-skeleton/spdx"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func parseSpdxFile(t *testing.T, filename string) *spdx.Document {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var document *spdx.Document
+	switch {
+	case strings.HasSuffix(filename, ".json"):
+		document, err = spdx.ParseJSON(file)
+	case strings.HasSuffix(filename, ".yaml"):
+		document, err = spdx.ParseYAML(file)
+	default:
+		document, err = spdx.ParseTagValue(file)
+	}
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return document
+}
+
+// Every SPDX example, converted to any spec. version, must be valid against that version's schema
+func TestSpdxToCycloneDXValidates(t *testing.T) {
+	filenames, err := filepath.Glob("../spdx/testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, specVersion := range cyclonedx.SpecVersions {
+		embeddedSchema, err := schema.Lookup(schema.FORMAT_CYCLONEDX, specVersion)
+		assert.NoError(t, err)
+		jsonSchema, err := embeddedSchema.Compile()
+		assert.NoError(t, err)
+
+		for _, filename := range filenames {
+			bom, report, err := SpdxToCycloneDX(parseSpdxFile(t, filename), specVersion)
+			assert.NoError(t, err)
+			assert.NotNil(t, report)
+
+			var output bytes.Buffer
+			assert.NoError(t, bom.WriteJSON(&output))
+			result, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(output.Bytes()))
+			assert.NoError(t, err)
+			assert.True(t, result.Valid(), "%s (%s): %v", filename, specVersion, result.Errors())
+		}
+	}
+}
+
+func TestSpdxToCycloneDX(t *testing.T) {
+	document := parseSpdxFile(t, "../spdx/testdata/SPDXJSONExample-v2.3.spdx.json")
+	bom, report, err := SpdxToCycloneDX(document, cyclonedx.SPEC_VERSION_1_6)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "urn:uuid:"+uuidFromURL(document.DocumentNamespace), bom.SerialNumber)
+	assert.Equal(t, document.CreationInfo.Created, bom.Metadata.Timestamp)
+	assert.Equal(t, "LicenseFind", bom.Metadata.Tools.Components[0].Name)
+	assert.Equal(t, "1.0", bom.Metadata.Tools.Components[0].Version)
+
+	var pkg *cyclonedx.Component
+	for i := range bom.Components {
+		if bom.Components[i].BOMRef == "SPDXRef-Package" {
+			pkg = &bom.Components[i]
+		}
+	}
+	if !assert.NotNil(t, pkg) {
+		return
+	}
+	assert.Equal(t, "glibc", pkg.Name)
+	assert.Equal(t, "2.11.1", pkg.Version)
+	assert.Equal(t, "cpe:2.3:a:pivotal_software:spring_framework:4.1.0:*:*:*:*:*:*:*", pkg.CPE)
+	assert.Contains(t, pkg.Hashes, cyclonedx.Hash{Algorithm: "SHA-1", Value: "85ed0817af83a24ad8da68c2b5094de69833983c"})
+	// an expression must be the only license; the other is kept
+	if assert.Len(t, pkg.Licenses, 1) {
+		assert.Equal(t, "declared", pkg.Licenses[0].Acknowledgement)
+		assert.Equal(t, "(LGPL-2.0-only AND LicenseRef-3)", pkg.Licenses[0].Expression)
+	}
+	assert.Contains(t, pkg.Properties, cyclonedx.Property{Name: "spdx:licenseConcluded", Value: "(LGPL-2.0-only OR LicenseRef-3)"})
+	assert.Contains(t, pkg.Properties, cyclonedx.Property{Name: "spdx:sourceInfo", Value: document.Package("SPDXRef-Package").SourceInfo})
+
+	// Element annotations (since 1.5) refer to their subject
+	var subjects []string
+	for _, annotation := range bom.Annotations {
+		subjects = append(subjects, annotation.Subjects...)
+	}
+	assert.Equal(t, []string{"SPDXRef-Package", "SPDXRef-File"}, subjects)
+
+	// Every approximation refers to its source field
+	assert.NotEmpty(t, report.Approximations)
+	for _, approximation := range report.Approximations {
+		assert.NotEmpty(t, approximation.Source)
+		assert.NotEmpty(t, approximation.Message)
+	}
+}
+
+func TestSpdxToCycloneDXRelationships(t *testing.T) {
+	document := &spdx.Document{
+		SPDXID:            spdx.SPDXID_DOCUMENT,
+		DocumentNamespace: "https://example.com/spdx/doc",
+		Packages: []*spdx.Package{
+			{SPDXID: "SPDXRef-App", Name: "app", VersionInfo: "1.0", PrimaryPackagePurpose: "APPLICATION"},
+			{SPDXID: "SPDXRef-Lib", Name: "lib", VersionInfo: "2.0"},
+			{SPDXID: "SPDXRef-Nested", Name: "nested", VersionInfo: "3.0"},
+		},
+		Relationships: []*spdx.Relationship{
+			{SPDXElementID: spdx.SPDXID_DOCUMENT, RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-App"},
+			{SPDXElementID: "SPDXRef-App", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Lib"},
+			{SPDXElementID: "SPDXRef-App", RelationshipType: "DEV_DEPENDENCY_OF", RelatedSPDXElement: "SPDXRef-Lib"},
+			{SPDXElementID: "SPDXRef-Lib", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Nested"},
+			{SPDXElementID: "SPDXRef-Nested", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-Lib"},
+			{SPDXElementID: "SPDXRef-Nested", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: spdx.NONE},
+			{SPDXElementID: "SPDXRef-Lib", RelationshipType: "GENERATED_FROM", RelatedSPDXElement: "SPDXRef-App"},
+		},
+	}
+	bom, report, err := SpdxToCycloneDX(document, cyclonedx.SPEC_VERSION_1_5)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SPDXRef-App", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, "application", bom.Metadata.Component.Type)
+	if assert.Len(t, bom.Components, 1) {
+		assert.Equal(t, "SPDXRef-Lib", bom.Components[0].BOMRef)
+		assert.Equal(t, "SPDXRef-Nested", bom.Components[0].Components[0].BOMRef)
+	}
+	assert.Equal(t, []cyclonedx.Dependency{
+		{Ref: "SPDXRef-App", DependsOn: []string{"SPDXRef-Lib"}},
+		{Ref: "SPDXRef-Lib", DependsOn: []string{"SPDXRef-App"}},
+		{Ref: "SPDXRef-Nested"},
+	}, bom.Dependencies)
+
+	// The (cyclic) CONTAINS and GENERATED_FROM have no equivalent
+	var sources []string
+	for _, approximation := range report.Approximations {
+		sources = append(sources, approximation.Source)
+	}
+	assert.Contains(t, sources, "SPDXRef-DOCUMENT/relationships/2")
+	assert.Contains(t, sources, "SPDXRef-DOCUMENT/relationships/4")
+	assert.Contains(t, sources, "SPDXRef-DOCUMENT/relationships/6")
+	assert.Len(t, bom.Components[0].Properties, 1)
+	assert.Len(t, bom.Components[0].Components[0].Properties, 1)
+}
+
+func TestSpdxToCycloneDXSpecVersion(t *testing.T) {
+	document := parseSpdxFile(t, "../spdx/testdata/SPDXJSONExample-v2.3.spdx.json")
+	bom, _, err := SpdxToCycloneDX(document, cyclonedx.SPEC_VERSION_1_4)
+	assert.NoError(t, err)
+	assert.Equal(t, cyclonedx.SPEC_VERSION_1_4, bom.SpecVersion)
+	assert.Empty(t, bom.Annotations)
+	assert.NotEmpty(t, bom.Metadata.Tools.Tools)
+
+	_, _, err = SpdxToCycloneDX(document, "2.0")
+	assert.Error(t, err)
+}
+
+func TestUUIDFromURL(t *testing.T) {
+	// as (e.g.) Python's uuid.uuid5(uuid.NAMESPACE_URL, "http://python.org/")
+	assert.Equal(t, "4c565f0d-3f5a-5890-b41b-20cf47701c5e", uuidFromURL("http://python.org/"))
+}
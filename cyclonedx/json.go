@@ -0,0 +1,64 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// ParseJSON reads a CycloneDX (1.2 through 1.6) JSON BOM
+func ParseJSON(reader io.Reader) (*Bom, error) {
+	bom := new(Bom)
+	decoder := json.NewDecoder(reader)
+	if err := decoder.Decode(bom); err != nil {
+		return nil, err
+	}
+	return bom, nil
+}
+
+// WriteJSON writes the BOM as (indented) CycloneDX JSON; use ConvertTo()
+// first to write the BOM for a spec. version other than its own
+func (bom *Bom) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bom)
+}
+
+// Legacy tools (i.e., before 1.5) are encoded as an array; otherwise, as an
+// object with the tool components and services. A BOM may only use one form.
+func (tools Tools) MarshalJSON() ([]byte, error) {
+	if len(tools.Components) == 0 && len(tools.Services) == 0 {
+		if tools.Tools == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(tools.Tools)
+	}
+	type plain Tools
+	return json.Marshal(plain(tools))
+}
+
+func (tools *Tools) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		return json.Unmarshal(data, &tools.Tools)
+	}
+	type plain Tools
+	return json.Unmarshal(data, (*plain)(tools))
+}
@@ -0,0 +1,108 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseJSONFile(t *testing.T, filename string) *Bom {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	bom, err := ParseJSON(file)
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return bom
+}
+
+// Decoding, then re-encoding, a BOM must not lose (or alter) any property
+func TestJSONRoundTrip(t *testing.T) {
+	for _, name := range []string{
+		"valid-annotation.json",
+		"valid-compositions.json",
+		"valid-component-authors.json",
+		"valid-component-types.json",
+		"valid-external-reference.json",
+		"valid-license-expression.json",
+		"valid-patch.json",
+		"valid-properties.json",
+		"valid-release-notes.json",
+		"valid-service.json",
+	} {
+		filename := filepath.Join("testdata", name)
+		input, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var output bytes.Buffer
+		assert.NoError(t, parseJSONFile(t, filename).WriteJSON(&output))
+
+		var expected, actual interface{}
+		assert.NoError(t, json.Unmarshal(input, &expected))
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &actual))
+		assert.Equal(t, expected, actual, name)
+	}
+}
+
+func TestJSONModel(t *testing.T) {
+	bom := parseJSONFile(t, "testdata/valid-bom.json")
+	assert.Equal(t, BOM_FORMAT, bom.BOMFormat)
+	assert.Equal(t, SPEC_VERSION_1_6, bom.SpecVersion)
+	assert.Equal(t, 1, bom.Version)
+	assert.Equal(t, "Samantha Wright", bom.Metadata.Authors[0].Name)
+	assert.Equal(t, "Acme Application", bom.Metadata.Component.Name)
+	assert.Equal(t, 2, len(bom.Components))
+	assert.Equal(t, "Apache-2.0", bom.Components[0].Licenses[0].License.ID)
+	assert.Equal(t, "SHA-512", bom.Components[0].Hashes[3].Algorithm)
+	assert.Equal(t, 2, len(bom.Components[0].Pedigree.Ancestors))
+	assert.Equal(t, "pkg:npm/acme/component@1.0.0", bom.Dependencies[0].DependsOn[0])
+
+	bom = parseJSONFile(t, "testdata/valid-vulnerability.json")
+	vulnerability := bom.Vulnerabilities[0]
+	assert.Equal(t, "SNYK-JAVA-COMFASTERXMLJACKSONCORE-32111", vulnerability.ID)
+	assert.Equal(t, []int{184, 502}, vulnerability.CWEs)
+	assert.Equal(t, 9.8, *vulnerability.Ratings[0].Score)
+	assert.Equal(t, "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.9.4", vulnerability.Affects[0].Ref)
+	assert.NotEmpty(t, vulnerability.Analysis.State)
+}
+
+func TestJSONTools(t *testing.T) {
+	bom := parseJSONFile(t, "testdata/valid-metadata-tool.json")
+	assert.Empty(t, bom.Metadata.Tools.Tools)
+	assert.Equal(t, "Awesome Tool", bom.Metadata.Tools.Components[0].Name)
+	assert.Equal(t, "Acme Signing Server", bom.Metadata.Tools.Services[0].Name)
+
+	bom = parseJSONFile(t, "testdata/valid-metadata-tool-deprecated.json")
+	assert.Equal(t, "Awesome Vendor", bom.Metadata.Tools.Tools[0].Vendor)
+
+	data, err := json.Marshal(bom.Metadata.Tools)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('['), data[0])
+}
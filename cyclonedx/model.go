@@ -0,0 +1,407 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cyclonedx
+
+// Typed CycloneDX object model (spec. versions 1.2 through 1.6)
+// Note: field names (and JSON property names) follow the CycloneDX JSON schema;
+// a `cdx:"<version>"` tag marks a field first supported in that spec. version
+// (fields without the tag are supported by all versions).
+// Fields are ordered as their XML elements are sequenced by the CycloneDX XSD;
+// types without `xml` tags are (un)marshalled by xml.go.
+// Not (yet) modeled: formulation, definitions, declarations, model cards,
+// cryptographic properties, signatures and (1.5+) component identity evidence.
+
+const (
+	BOM_FORMAT = "CycloneDX"
+)
+
+type Bom struct {
+	JSONSchema         string              `json:"$schema,omitempty" xml:"-"`
+	BOMFormat          string              `json:"bomFormat" xml:"-"`
+	SpecVersion        string              `json:"specVersion" xml:"-"`
+	SerialNumber       string              `json:"serialNumber,omitempty" xml:"serialNumber,attr,omitempty"`
+	Version            int                 `json:"version,omitempty" xml:"version,attr,omitempty"`
+	Metadata           *Metadata           `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	Components         []Component         `json:"components,omitempty" xml:"components>component,omitempty"`
+	Services           []Service           `json:"services,omitempty" xml:"services>service,omitempty"`
+	ExternalReferences []ExternalReference `json:"externalReferences,omitempty" xml:"externalReferences>reference,omitempty"`
+	Dependencies       []Dependency        `json:"dependencies,omitempty" xml:"dependencies>dependency,omitempty"`
+	Compositions       []Composition       `json:"compositions,omitempty" xml:"compositions>composition,omitempty" cdx:"1.3"`
+	Properties         []Property          `json:"properties,omitempty" xml:"properties>property,omitempty" cdx:"1.5"`
+	Vulnerabilities    []Vulnerability     `json:"vulnerabilities,omitempty" xml:"vulnerabilities>vulnerability,omitempty" cdx:"1.4"`
+	Annotations        []Annotation        `json:"annotations,omitempty" xml:"annotations>annotation,omitempty" cdx:"1.5"`
+}
+
+type Metadata struct {
+	Timestamp    string                  `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	Lifecycles   []Lifecycle             `json:"lifecycles,omitempty" xml:"lifecycles>lifecycle,omitempty" cdx:"1.5"`
+	Tools        *Tools                  `json:"tools,omitempty" xml:"tools,omitempty"`
+	Authors      []OrganizationalContact `json:"authors,omitempty" xml:"authors>author,omitempty"`
+	Component    *Component              `json:"component,omitempty" xml:"component,omitempty"`
+	Manufacturer *OrganizationalEntity   `json:"manufacturer,omitempty" xml:"manufacturer,omitempty" cdx:"1.6"`
+	Manufacture  *OrganizationalEntity   `json:"manufacture,omitempty" xml:"manufacture,omitempty"` // deprecated (1.6)
+	Supplier     *OrganizationalEntity   `json:"supplier,omitempty" xml:"supplier,omitempty"`
+	Licenses     Licenses                `json:"licenses,omitempty" xml:"licenses,omitempty" cdx:"1.3"`
+	Properties   []Property              `json:"properties,omitempty" xml:"properties>property,omitempty" cdx:"1.3"`
+}
+
+// Either a pre-defined Phase or a custom Name (and Description)
+type Lifecycle struct {
+	Phase       string `json:"phase,omitempty" xml:"phase,omitempty"`
+	Name        string `json:"name,omitempty" xml:"name,omitempty"`
+	Description string `json:"description,omitempty" xml:"description,omitempty"`
+}
+
+// Tools are either the (legacy) list of Tool or, since 1.5, the
+// components and services used to create the BOM (see json.go and xml.go)
+type Tools struct {
+	Tools      []Tool      `json:"-" xml:"tool"`
+	Components []Component `json:"components,omitempty" xml:"components>component,omitempty" cdx:"1.5"`
+	Services   []Service   `json:"services,omitempty" xml:"services>service,omitempty" cdx:"1.5"`
+}
+
+// Deprecated (1.5) in favor of Tools' Components and Services
+type Tool struct {
+	Vendor             string              `json:"vendor,omitempty" xml:"vendor,omitempty"`
+	Name               string              `json:"name,omitempty" xml:"name,omitempty"`
+	Version            string              `json:"version,omitempty" xml:"version,omitempty"`
+	Hashes             []Hash              `json:"hashes,omitempty" xml:"hashes>hash,omitempty"`
+	ExternalReferences []ExternalReference `json:"externalReferences,omitempty" xml:"externalReferences>reference,omitempty" cdx:"1.4"`
+}
+
+type OrganizationalEntity struct {
+	BOMRef  string                  `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty" cdx:"1.5"`
+	Name    string                  `json:"name,omitempty" xml:"name,omitempty"`
+	Address *PostalAddress          `json:"address,omitempty" xml:"address,omitempty" cdx:"1.6"`
+	URL     []string                `json:"url,omitempty" xml:"url,omitempty"`
+	Contact []OrganizationalContact `json:"contact,omitempty" xml:"contact,omitempty"`
+}
+
+type OrganizationalContact struct {
+	BOMRef string `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty" cdx:"1.5"`
+	Name   string `json:"name,omitempty" xml:"name,omitempty"`
+	Email  string `json:"email,omitempty" xml:"email,omitempty"`
+	Phone  string `json:"phone,omitempty" xml:"phone,omitempty"`
+}
+
+type PostalAddress struct {
+	BOMRef              string `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Country             string `json:"country,omitempty" xml:"country,omitempty"`
+	Region              string `json:"region,omitempty" xml:"region,omitempty"`
+	Locality            string `json:"locality,omitempty" xml:"locality,omitempty"`
+	PostOfficeBoxNumber string `json:"postOfficeBoxNumber,omitempty" xml:"postOfficeBoxNumber,omitempty"`
+	PostalCode          string `json:"postalCode,omitempty" xml:"postalCode,omitempty"`
+	StreetAddress       string `json:"streetAddress,omitempty" xml:"streetAddress,omitempty"`
+}
+
+type Component struct {
+	Type               string                  `json:"type" xml:"type,attr"`
+	MIMEType           string                  `json:"mime-type,omitempty" xml:"mime-type,attr,omitempty"`
+	BOMRef             string                  `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Supplier           *OrganizationalEntity   `json:"supplier,omitempty" xml:"supplier,omitempty"`
+	Manufacturer       *OrganizationalEntity   `json:"manufacturer,omitempty" xml:"manufacturer,omitempty" cdx:"1.6"`
+	Authors            []OrganizationalContact `json:"authors,omitempty" xml:"authors>author,omitempty" cdx:"1.6"`
+	Author             string                  `json:"author,omitempty" xml:"author,omitempty"` // deprecated (1.6)
+	Publisher          string                  `json:"publisher,omitempty" xml:"publisher,omitempty"`
+	Group              string                  `json:"group,omitempty" xml:"group,omitempty"`
+	Name               string                  `json:"name" xml:"name"`
+	Version            string                  `json:"version,omitempty" xml:"version,omitempty"` // required before 1.4
+	Description        string                  `json:"description,omitempty" xml:"description,omitempty"`
+	Scope              string                  `json:"scope,omitempty" xml:"scope,omitempty"`
+	Hashes             []Hash                  `json:"hashes,omitempty" xml:"hashes>hash,omitempty"`
+	Licenses           Licenses                `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	Copyright          string                  `json:"copyright,omitempty" xml:"copyright,omitempty"`
+	CPE                string                  `json:"cpe,omitempty" xml:"cpe,omitempty"`
+	PURL               string                  `json:"purl,omitempty" xml:"purl,omitempty"`
+	OmniborID          []string                `json:"omniborId,omitempty" xml:"omniborId,omitempty" cdx:"1.6"`
+	SWHID              []string                `json:"swhid,omitempty" xml:"swhid,omitempty" cdx:"1.6"`
+	SWID               *SWID                   `json:"swid,omitempty" xml:"swid,omitempty"`
+	Modified           *bool                   `json:"modified,omitempty" xml:"modified,omitempty"` // deprecated (1.3)
+	Pedigree           *Pedigree               `json:"pedigree,omitempty" xml:"pedigree,omitempty"`
+	ExternalReferences []ExternalReference     `json:"externalReferences,omitempty" xml:"externalReferences>reference,omitempty"`
+	Properties         []Property              `json:"properties,omitempty" xml:"properties>property,omitempty" cdx:"1.3"`
+	Components         []Component             `json:"components,omitempty" xml:"components>component,omitempty"`
+	Evidence           *Evidence               `json:"evidence,omitempty" xml:"evidence,omitempty" cdx:"1.3"`
+	ReleaseNotes       *ReleaseNotes           `json:"releaseNotes,omitempty" xml:"releaseNotes,omitempty" cdx:"1.4"`
+}
+
+type SWID struct {
+	TagID      string        `json:"tagId" xml:"tagId,attr"`
+	Name       string        `json:"name" xml:"name,attr"`
+	Version    string        `json:"version,omitempty" xml:"version,attr,omitempty"`
+	TagVersion *int          `json:"tagVersion,omitempty" xml:"tagVersion,attr,omitempty"`
+	Patch      *bool         `json:"patch,omitempty" xml:"patch,attr,omitempty"`
+	Text       *AttachedText `json:"text,omitempty" xml:"text,omitempty"`
+	URL        string        `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+type AttachedText struct {
+	ContentType string `json:"contentType,omitempty" xml:"content-type,attr,omitempty"`
+	Encoding    string `json:"encoding,omitempty" xml:"encoding,attr,omitempty"`
+	Content     string `json:"content" xml:",chardata"`
+}
+
+type Pedigree struct {
+	Ancestors   []Component `json:"ancestors,omitempty" xml:"ancestors>component,omitempty"`
+	Descendants []Component `json:"descendants,omitempty" xml:"descendants>component,omitempty"`
+	Variants    []Component `json:"variants,omitempty" xml:"variants>component,omitempty"`
+	Commits     []Commit    `json:"commits,omitempty" xml:"commits>commit,omitempty"`
+	Patches     []Patch     `json:"patches,omitempty" xml:"patches>patch,omitempty"`
+	Notes       string      `json:"notes,omitempty" xml:"notes,omitempty"`
+}
+
+type Commit struct {
+	UID       string              `json:"uid,omitempty" xml:"uid,omitempty"`
+	URL       string              `json:"url,omitempty" xml:"url,omitempty"`
+	Author    *IdentifiableAction `json:"author,omitempty" xml:"author,omitempty"`
+	Committer *IdentifiableAction `json:"committer,omitempty" xml:"committer,omitempty"`
+	Message   string              `json:"message,omitempty" xml:"message,omitempty"`
+}
+
+type IdentifiableAction struct {
+	Timestamp string `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	Name      string `json:"name,omitempty" xml:"name,omitempty"`
+	Email     string `json:"email,omitempty" xml:"email,omitempty"`
+}
+
+type Patch struct {
+	Type     string  `json:"type" xml:"type,attr"`
+	Diff     *Diff   `json:"diff,omitempty" xml:"diff,omitempty"`
+	Resolves []Issue `json:"resolves,omitempty" xml:"resolves>issue,omitempty"`
+}
+
+type Diff struct {
+	Text *AttachedText `json:"text,omitempty" xml:"text,omitempty"`
+	URL  string        `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+// An issue (e.g., defect or security fix) resolved by a patch or release
+type Issue struct {
+	Type        string   `json:"type" xml:"type,attr"`
+	ID          string   `json:"id,omitempty" xml:"id,omitempty"`
+	Name        string   `json:"name,omitempty" xml:"name,omitempty"`
+	Description string   `json:"description,omitempty" xml:"description,omitempty"`
+	Source      *Source  `json:"source,omitempty" xml:"source,omitempty"`
+	References  []string `json:"references,omitempty" xml:"references>url,omitempty"`
+}
+
+type Source struct {
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	URL  string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+type Evidence struct {
+	Licenses  Licenses    `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	Copyright []Copyright `json:"copyright,omitempty" xml:"copyright>text,omitempty"`
+}
+
+type Copyright struct {
+	Text string `json:"text" xml:",chardata"`
+}
+
+type ReleaseNotes struct {
+	Type          string     `json:"type" xml:"type"`
+	Title         string     `json:"title,omitempty" xml:"title,omitempty"`
+	FeaturedImage string     `json:"featuredImage,omitempty" xml:"featuredImage,omitempty"`
+	SocialImage   string     `json:"socialImage,omitempty" xml:"socialImage,omitempty"`
+	Description   string     `json:"description,omitempty" xml:"description,omitempty"`
+	Timestamp     string     `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	Aliases       []string   `json:"aliases,omitempty" xml:"aliases>alias,omitempty"`
+	Tags          []string   `json:"tags,omitempty" xml:"tags>tag,omitempty"`
+	Resolves      []Issue    `json:"resolves,omitempty" xml:"resolves>issue,omitempty"`
+	Notes         []Note     `json:"notes,omitempty" xml:"notes>note,omitempty"`
+	Properties    []Property `json:"properties,omitempty" xml:"properties>property,omitempty"`
+}
+
+type Note struct {
+	Locale string       `json:"locale,omitempty" xml:"locale,omitempty"`
+	Text   AttachedText `json:"text" xml:"text"`
+}
+
+type Service struct {
+	BOMRef               string                `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	Provider             *OrganizationalEntity `json:"provider,omitempty" xml:"provider,omitempty"`
+	Group                string                `json:"group,omitempty" xml:"group,omitempty"`
+	Name                 string                `json:"name" xml:"name"`
+	Version              string                `json:"version,omitempty" xml:"version,omitempty"`
+	Description          string                `json:"description,omitempty" xml:"description,omitempty"`
+	Endpoints            []string              `json:"endpoints,omitempty" xml:"endpoints>endpoint,omitempty"`
+	Authenticated        *bool                 `json:"authenticated,omitempty" xml:"authenticated,omitempty"`
+	CrossesTrustBoundary *bool                 `json:"x-trust-boundary,omitempty" xml:"x-trust-boundary,omitempty"`
+	TrustZone            string                `json:"trustZone,omitempty" xml:"trustZone,omitempty" cdx:"1.5"`
+	Data                 DataFlows             `json:"data,omitempty" xml:"data,omitempty"`
+	Licenses             Licenses              `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	ExternalReferences   []ExternalReference   `json:"externalReferences,omitempty" xml:"externalReferences>reference,omitempty"`
+	Properties           []Property            `json:"properties,omitempty" xml:"properties>property,omitempty" cdx:"1.3"`
+	Services             []Service             `json:"services,omitempty" xml:"services>service,omitempty"`
+	ReleaseNotes         *ReleaseNotes         `json:"releaseNotes,omitempty" xml:"releaseNotes,omitempty" cdx:"1.4"`
+}
+
+// In XML, either a list of classifications or (since 1.5) of named data flows (see xml.go)
+type DataFlows []ServiceData
+
+type ServiceData struct {
+	Flow           string `json:"flow"`
+	Classification string `json:"classification"`
+	Name           string `json:"name,omitempty" cdx:"1.5"`
+	Description    string `json:"description,omitempty" cdx:"1.5"`
+}
+
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Provides  []string `json:"provides,omitempty" cdx:"1.6"`
+}
+
+type ExternalReference struct {
+	URL     string `json:"url" xml:"url"`
+	Comment string `json:"comment,omitempty" xml:"comment,omitempty"`
+	Type    string `json:"type" xml:"type,attr"`
+	Hashes  []Hash `json:"hashes,omitempty" xml:"hashes>hash,omitempty" cdx:"1.3"`
+}
+
+type Hash struct {
+	Algorithm string `json:"alg" xml:"alg,attr"`
+	Value     string `json:"content" xml:",chardata"`
+}
+
+// In XML, either a list of licenses or a single expression (see xml.go)
+type Licenses []LicenseChoice
+
+// A license choice is either a License or an SPDX license Expression
+type LicenseChoice struct {
+	License         *License `json:"license,omitempty"`
+	Expression      string   `json:"expression,omitempty"`
+	BOMRef          string   `json:"bom-ref,omitempty" cdx:"1.5"`         // expression only
+	Acknowledgement string   `json:"acknowledgement,omitempty" cdx:"1.6"` // expression only
+}
+
+// A License is identified by either an SPDX license ID or a Name
+type License struct {
+	BOMRef          string        `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty" cdx:"1.5"`
+	ID              string        `json:"id,omitempty" xml:"id,omitempty"`
+	Name            string        `json:"name,omitempty" xml:"name,omitempty"`
+	Acknowledgement string        `json:"acknowledgement,omitempty" xml:"acknowledgement,attr,omitempty" cdx:"1.6"`
+	Text            *AttachedText `json:"text,omitempty" xml:"text,omitempty"`
+	URL             string        `json:"url,omitempty" xml:"url,omitempty"`
+	Properties      []Property    `json:"properties,omitempty" xml:"properties>property,omitempty" cdx:"1.5"`
+}
+
+type Vulnerability struct {
+	BOMRef         string                   `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+	ID             string                   `json:"id,omitempty" xml:"id,omitempty"`
+	Source         *Source                  `json:"source,omitempty" xml:"source,omitempty"`
+	References     []VulnerabilityReference `json:"references,omitempty" xml:"references>reference,omitempty"`
+	Ratings        []Rating                 `json:"ratings,omitempty" xml:"ratings>rating,omitempty"`
+	CWEs           []int                    `json:"cwes,omitempty" xml:"cwes>cwe,omitempty"`
+	Description    string                   `json:"description,omitempty" xml:"description,omitempty"`
+	Detail         string                   `json:"detail,omitempty" xml:"detail,omitempty"`
+	Recommendation string                   `json:"recommendation,omitempty" xml:"recommendation,omitempty"`
+	Workaround     string                   `json:"workaround,omitempty" xml:"workaround,omitempty" cdx:"1.5"`
+	ProofOfConcept *ProofOfConcept          `json:"proofOfConcept,omitempty" xml:"proofOfConcept,omitempty" cdx:"1.5"`
+	Advisories     []Advisory               `json:"advisories,omitempty" xml:"advisories>advisory,omitempty"`
+	Created        string                   `json:"created,omitempty" xml:"created,omitempty"`
+	Published      string                   `json:"published,omitempty" xml:"published,omitempty"`
+	Updated        string                   `json:"updated,omitempty" xml:"updated,omitempty"`
+	Rejected       string                   `json:"rejected,omitempty" xml:"rejected,omitempty" cdx:"1.5"`
+	Credits        *Credits                 `json:"credits,omitempty" xml:"credits,omitempty"`
+	Tools          *Tools                   `json:"tools,omitempty" xml:"tools,omitempty"`
+	Analysis       *Analysis                `json:"analysis,omitempty" xml:"analysis,omitempty"`
+	Affects        []Affects                `json:"affects,omitempty" xml:"affects>target,omitempty"`
+	Properties     []Property               `json:"properties,omitempty" xml:"properties>property,omitempty"`
+}
+
+type VulnerabilityReference struct {
+	ID     string  `json:"id" xml:"id"`
+	Source *Source `json:"source" xml:"source"`
+}
+
+type Rating struct {
+	Source        *Source  `json:"source,omitempty" xml:"source,omitempty"`
+	Score         *float64 `json:"score,omitempty" xml:"score,omitempty"`
+	Severity      string   `json:"severity,omitempty" xml:"severity,omitempty"`
+	Method        string   `json:"method,omitempty" xml:"method,omitempty"`
+	Vector        string   `json:"vector,omitempty" xml:"vector,omitempty"`
+	Justification string   `json:"justification,omitempty" xml:"justification,omitempty"`
+}
+
+type ProofOfConcept struct {
+	ReproductionSteps  string         `json:"reproductionSteps,omitempty" xml:"reproductionSteps,omitempty"`
+	Environment        string         `json:"environment,omitempty" xml:"environment,omitempty"`
+	SupportingMaterial []AttachedText `json:"supportingMaterial,omitempty" xml:"supportingMaterial>attachment,omitempty"`
+}
+
+type Advisory struct {
+	Title string `json:"title,omitempty" xml:"title,omitempty"`
+	URL   string `json:"url" xml:"url"`
+}
+
+type Credits struct {
+	Organizations []OrganizationalEntity  `json:"organizations,omitempty" xml:"organizations>organization,omitempty"`
+	Individuals   []OrganizationalContact `json:"individuals,omitempty" xml:"individuals>individual,omitempty"`
+}
+
+type Analysis struct {
+	State         string   `json:"state,omitempty" xml:"state,omitempty"`
+	Justification string   `json:"justification,omitempty" xml:"justification,omitempty"`
+	Response      []string `json:"response,omitempty" xml:"responses>response,omitempty"`
+	Detail        string   `json:"detail,omitempty" xml:"detail,omitempty"`
+	FirstIssued   string   `json:"firstIssued,omitempty" xml:"firstIssued,omitempty" cdx:"1.5"`
+	LastUpdated   string   `json:"lastUpdated,omitempty" xml:"lastUpdated,omitempty" cdx:"1.5"`
+}
+
+type Affects struct {
+	Ref      string             `json:"ref" xml:"ref"`
+	Versions []AffectedVersions `json:"versions,omitempty" xml:"versions>version,omitempty"`
+}
+
+// Either a Version or a (version) Range
+type AffectedVersions struct {
+	Version string `json:"version,omitempty" xml:"version,omitempty"`
+	Range   string `json:"range,omitempty" xml:"range,omitempty"`
+	Status  string `json:"status,omitempty" xml:"status,omitempty"`
+}
+
+type Composition struct {
+	BOMRef          string   `json:"bom-ref,omitempty" cdx:"1.5"`
+	Aggregate       string   `json:"aggregate"`
+	Assemblies      []string `json:"assemblies,omitempty"`
+	Dependencies    []string `json:"dependencies,omitempty"`
+	Vulnerabilities []string `json:"vulnerabilities,omitempty" cdx:"1.5"`
+}
+
+type Property struct {
+	Name  string `json:"name" xml:"name,attr"`
+	Value string `json:"value,omitempty" xml:",chardata"`
+}
+
+type Annotation struct {
+	BOMRef    string    `json:"bom-ref,omitempty"`
+	Subjects  []string  `json:"subjects"`
+	Annotator Annotator `json:"annotator"`
+	Timestamp string    `json:"timestamp"`
+	Text      string    `json:"text"`
+}
+
+// Exactly one of the annotator's fields is set
+type Annotator struct {
+	Organization *OrganizationalEntity  `json:"organization,omitempty" xml:"organization,omitempty"`
+	Individual   *OrganizationalContact `json:"individual,omitempty" xml:"individual,omitempty"`
+	Component    *Component             `json:"component,omitempty" xml:"component,omitempty"`
+	Service      *Service               `json:"service,omitempty" xml:"service,omitempty"`
+}
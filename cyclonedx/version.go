@@ -0,0 +1,258 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cyclonedx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	SPEC_VERSION_1_2 = "1.2"
+	SPEC_VERSION_1_3 = "1.3"
+	SPEC_VERSION_1_4 = "1.4"
+	SPEC_VERSION_1_5 = "1.5"
+	SPEC_VERSION_1_6 = "1.6"
+
+	LATEST_SPEC_VERSION = SPEC_VERSION_1_6
+)
+
+// Supported spec. versions (in order)
+var SpecVersions = []string{SPEC_VERSION_1_2, SPEC_VERSION_1_3, SPEC_VERSION_1_4, SPEC_VERSION_1_5, SPEC_VERSION_1_6}
+
+// The $schema URL for each spec. version (JSON)
+func SchemaURL(specVersion string) string {
+	return fmt.Sprintf("http://cyclonedx.org/schema/bom-%s.schema.json", specVersion)
+}
+
+// The first spec. version that supports each (enumerated) value; values
+// not listed here are supported by all versions
+var componentTypes = map[string]string{
+	"platform":               SPEC_VERSION_1_5,
+	"device-driver":          SPEC_VERSION_1_5,
+	"machine-learning-model": SPEC_VERSION_1_5,
+	"data":                   SPEC_VERSION_1_5,
+	"cryptographic-asset":    SPEC_VERSION_1_6,
+}
+
+// The (approximate) component type used by versions that predate a type
+var componentTypeFallbacks = map[string]string{
+	"platform":      "framework",
+	"device-driver": "firmware",
+}
+
+const DEFAULT_COMPONENT_TYPE_FALLBACK = "file"
+
+var externalReferenceTypes = map[string]string{
+	"release-notes":             SPEC_VERSION_1_4,
+	"distribution-intake":       SPEC_VERSION_1_5,
+	"security-contact":          SPEC_VERSION_1_5,
+	"model-card":                SPEC_VERSION_1_5,
+	"log":                       SPEC_VERSION_1_5,
+	"configuration":             SPEC_VERSION_1_5,
+	"evidence":                  SPEC_VERSION_1_5,
+	"formulation":               SPEC_VERSION_1_5,
+	"attestation":               SPEC_VERSION_1_5,
+	"threat-model":              SPEC_VERSION_1_5,
+	"adversary-model":           SPEC_VERSION_1_5,
+	"risk-assessment":           SPEC_VERSION_1_5,
+	"vulnerability-assertion":   SPEC_VERSION_1_5,
+	"exploitability-statement":  SPEC_VERSION_1_5,
+	"pentest-report":            SPEC_VERSION_1_5,
+	"static-analysis-report":    SPEC_VERSION_1_5,
+	"dynamic-analysis-report":   SPEC_VERSION_1_5,
+	"runtime-analysis-report":   SPEC_VERSION_1_5,
+	"component-analysis-report": SPEC_VERSION_1_5,
+	"maturity-report":           SPEC_VERSION_1_5,
+	"certification-report":      SPEC_VERSION_1_5,
+	"codified-infrastructure":   SPEC_VERSION_1_5,
+	"quality-metrics":           SPEC_VERSION_1_5,
+	"poam":                      SPEC_VERSION_1_5,
+	"source-distribution":       SPEC_VERSION_1_6,
+	"electronic-signature":      SPEC_VERSION_1_6,
+	"digital-signature":         SPEC_VERSION_1_6,
+	"rfc-9116":                  SPEC_VERSION_1_6,
+}
+
+const EXTERNAL_REFERENCE_TYPE_OTHER = "other"
+
+// Something in the BOM the target spec. version cannot represent (as is)
+type Incompatibility struct {
+	Pointer string // JSON pointer (RFC 6901) to the (original) value
+	Message string
+}
+
+func (incompatibility Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s", incompatibility.Pointer, incompatibility.Message)
+}
+
+// CompareVersions compares two (supported) spec. versions, e.g., CompareVersions("1.3", "1.5") < 0
+func CompareVersions(a string, b string) int {
+	return indexOf(a) - indexOf(b)
+}
+
+func indexOf(specVersion string) int {
+	for i, version := range SpecVersions {
+		if version == specVersion {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsSupported reports whether the spec. version is one this model supports
+func IsSupported(specVersion string) bool {
+	return indexOf(specVersion) >= 0
+}
+
+// ConvertTo changes the BOM (in place) to the given spec. version. Fields the
+// version does not support are removed; values it does not support (e.g.,
+// newer component types) are replaced by their closest equivalent. Each
+// such change is returned so that it can be reported.
+func (bom *Bom) ConvertTo(specVersion string) ([]Incompatibility, error) {
+	if !IsSupported(specVersion) {
+		return nil, fmt.Errorf("unsupported CycloneDX spec. version: `%s` (expected one of: %s)",
+			specVersion, strings.Join(SpecVersions, ", "))
+	}
+
+	converter := &converter{target: specVersion}
+	if bom.Metadata != nil {
+		bom.Metadata.Tools = converter.convertTools("/metadata/tools", bom.Metadata.Tools)
+	}
+	for i := range bom.Vulnerabilities {
+		pointer := fmt.Sprintf("/vulnerabilities/%d/tools", i)
+		bom.Vulnerabilities[i].Tools = converter.convertTools(pointer, bom.Vulnerabilities[i].Tools)
+	}
+	converter.walk("", reflect.ValueOf(bom).Elem())
+
+	bom.SpecVersion = specVersion
+	if bom.JSONSchema != "" {
+		bom.JSONSchema = SchemaURL(specVersion)
+	}
+	return converter.incompatibilities, nil
+}
+
+type converter struct {
+	target            string
+	incompatibilities []Incompatibility
+}
+
+func (converter *converter) report(pointer string, format string, a ...interface{}) {
+	converter.incompatibilities = append(converter.incompatibilities, Incompatibility{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (converter *converter) supports(since string) bool {
+	return since == "" || CompareVersions(converter.target, since) >= 0
+}
+
+// Before 1.5, tools are only described by (legacy) name, vendor and version
+func (converter *converter) convertTools(pointer string, tools *Tools) *Tools {
+	if tools == nil || converter.supports(SPEC_VERSION_1_5) {
+		return tools
+	}
+	for i, component := range tools.Components {
+		tools.Tools = append(tools.Tools, Tool{
+			Vendor:             component.Group,
+			Name:               component.Name,
+			Version:            component.Version,
+			Hashes:             component.Hashes,
+			ExternalReferences: component.ExternalReferences,
+		})
+		converter.report(fmt.Sprintf("%s/components/%d", pointer, i), "tool component converted to a (legacy) tool")
+	}
+	for i, service := range tools.Services {
+		vendor := service.Group
+		if service.Provider != nil && service.Provider.Name != "" {
+			vendor = service.Provider.Name
+		}
+		tools.Tools = append(tools.Tools, Tool{
+			Vendor:             vendor,
+			Name:               service.Name,
+			Version:            service.Version,
+			ExternalReferences: service.ExternalReferences,
+		})
+		converter.report(fmt.Sprintf("%s/services/%d", pointer, i), "tool service converted to a (legacy) tool")
+	}
+	tools.Components, tools.Services = nil, nil
+	return tools
+}
+
+// Recursively remove fields (and replace values) the target does not support
+func (converter *converter) walk(pointer string, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			converter.walk(pointer, value.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			converter.walk(fmt.Sprintf("%s/%d", pointer, i), value.Index(i))
+		}
+	case reflect.Struct:
+		converter.convertValues(pointer, value)
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			fieldPointer := pointer
+			if name != "-" {
+				fieldPointer = pointer + "/" + name
+			}
+
+			if since := field.Tag.Get("cdx"); !converter.supports(since) {
+				if !value.Field(i).IsZero() {
+					converter.report(fieldPointer, "not supported before CycloneDX %s; removed", since)
+					value.Field(i).Set(reflect.Zero(field.Type))
+				}
+				continue
+			}
+			converter.walk(fieldPointer, value.Field(i))
+		}
+	}
+}
+
+// Replace enumerated values that the target does not (yet) support
+func (converter *converter) convertValues(pointer string, value reflect.Value) {
+	switch element := value.Addr().Interface().(type) {
+	case *Component:
+		if since, found := componentTypes[element.Type]; found && !converter.supports(since) {
+			fallback, found := componentTypeFallbacks[element.Type]
+			if !found {
+				fallback = DEFAULT_COMPONENT_TYPE_FALLBACK
+			}
+			converter.report(pointer+"/type", "component type `%s` not supported before CycloneDX %s; replaced by `%s`",
+				element.Type, since, fallback)
+			element.Type = fallback
+		}
+		if element.Version == "" && !converter.supports(SPEC_VERSION_1_4) {
+			converter.report(pointer+"/version", "component version is required before CycloneDX %s", SPEC_VERSION_1_4)
+		}
+	case *ExternalReference:
+		if since, found := externalReferenceTypes[element.Type]; found && !converter.supports(since) {
+			converter.report(pointer+"/type", "external reference type `%s` not supported before CycloneDX %s; replaced by `%s`",
+				element.Type, since, EXTERNAL_REFERENCE_TYPE_OTHER)
+			if element.Comment == "" {
+				element.Comment = element.Type
+			}
+			element.Type = EXTERNAL_REFERENCE_TYPE_OTHER
+		}
+	}
+}
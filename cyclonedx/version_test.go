@@ -0,0 +1,115 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cyclonedx
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Every BOM, converted to any spec. version, must be valid against that version's schema
+func TestConvertToValidates(t *testing.T) {
+	filenames, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, specVersion := range SpecVersions {
+		embeddedSchema, err := schema.Lookup(schema.FORMAT_CYCLONEDX, specVersion)
+		assert.NoError(t, err)
+		jsonSchema, err := embeddedSchema.Compile()
+		assert.NoError(t, err)
+
+		for _, filename := range filenames {
+			bom := parseJSONFile(t, filename)
+			_, err := bom.ConvertTo(specVersion)
+			assert.NoError(t, err)
+
+			var output bytes.Buffer
+			assert.NoError(t, bom.WriteJSON(&output))
+			result, err := jsonSchema.Validate(gojsonschema.NewBytesLoader(output.Bytes()))
+			assert.NoError(t, err)
+			assert.True(t, result.Valid(), "%s (%s): %v", filename, specVersion, result.Errors())
+		}
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	bom := &Bom{
+		BOMFormat:   BOM_FORMAT,
+		SpecVersion: SPEC_VERSION_1_6,
+		JSONSchema:  SchemaURL(SPEC_VERSION_1_6),
+		Metadata: &Metadata{
+			Tools:      &Tools{Components: []Component{{Type: "application", Group: "acme", Name: "tool", Version: "1.0"}}},
+			Properties: []Property{{Name: "a", Value: "b"}},
+		},
+		Components: []Component{{
+			Type: "data",
+			Name: "dataset",
+			Components: []Component{{
+				Type:               "platform",
+				Name:               "nested",
+				Version:            "1.0",
+				OmniborID:          []string{"gitoid:blob:sha1:a94a8fe5ccb19ba61c4c0873d391e987982fbbd3"},
+				ExternalReferences: []ExternalReference{{URL: "https://example.com", Type: "rfc-9116"}},
+			}},
+		}},
+		Vulnerabilities: []Vulnerability{{ID: "CVE-2021-44228"}},
+	}
+
+	incompatibilities, err := bom.ConvertTo(SPEC_VERSION_1_2)
+	assert.NoError(t, err)
+	assert.Equal(t, SPEC_VERSION_1_2, bom.SpecVersion)
+	assert.Equal(t, "http://cyclonedx.org/schema/bom-1.2.schema.json", bom.JSONSchema)
+
+	var pointers []string
+	for _, incompatibility := range incompatibilities {
+		pointers = append(pointers, incompatibility.Pointer)
+	}
+	assert.Equal(t, []string{
+		"/metadata/tools/components/0",
+		"/metadata/properties",
+		"/components/0/type",
+		"/components/0/version",
+		"/components/0/components/0/type",
+		"/components/0/components/0/omniborId",
+		"/components/0/components/0/externalReferences/0/type",
+		"/vulnerabilities",
+	}, pointers)
+
+	assert.Equal(t, []Tool{{Vendor: "acme", Name: "tool", Version: "1.0"}}, bom.Metadata.Tools.Tools)
+	assert.Nil(t, bom.Metadata.Properties)
+	assert.Nil(t, bom.Vulnerabilities)
+	assert.Equal(t, DEFAULT_COMPONENT_TYPE_FALLBACK, bom.Components[0].Type)
+	nested := bom.Components[0].Components[0]
+	assert.Equal(t, "framework", nested.Type)
+	assert.Nil(t, nested.OmniborID)
+	assert.Equal(t, EXTERNAL_REFERENCE_TYPE_OTHER, nested.ExternalReferences[0].Type)
+	assert.Equal(t, "rfc-9116", nested.ExternalReferences[0].Comment)
+
+	// converting to the same (or a newer) version changes nothing
+	incompatibilities, err = bom.ConvertTo(SPEC_VERSION_1_6)
+	assert.NoError(t, err)
+	assert.Empty(t, incompatibilities)
+
+	_, err = bom.ConvertTo("2.0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,488 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cyclonedx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Each spec. version has its own XML namespace (e.g., "http://cyclonedx.org/schema/bom/1.5")
+const XML_NAMESPACE_PREFIX = "http://cyclonedx.org/schema/bom/"
+
+func XMLNamespace(specVersion string) string {
+	return XML_NAMESPACE_PREFIX + specVersion
+}
+
+// ParseXML reads a CycloneDX (1.2 through 1.6) XML BOM; the spec. version
+// is taken from the (versioned) namespace of the root `<bom>` element.
+func ParseXML(reader io.Reader) (*Bom, error) {
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no root element")
+		} else if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local != "bom" || !strings.HasPrefix(start.Name.Space, XML_NAMESPACE_PREFIX) {
+			return nil, fmt.Errorf("not a CycloneDX BOM: unexpected root element `%s` (namespace: `%s`)", start.Name.Local, start.Name.Space)
+		}
+		specVersion := strings.TrimPrefix(start.Name.Space, XML_NAMESPACE_PREFIX)
+		if !IsSupported(specVersion) {
+			return nil, fmt.Errorf("unsupported CycloneDX spec. version: `%s` (expected one of: %s)",
+				specVersion, strings.Join(SpecVersions, ", "))
+		}
+
+		bom := &Bom{BOMFormat: BOM_FORMAT, SpecVersion: specVersion}
+		if err = decoder.DecodeElement(bom, &start); err != nil {
+			return nil, err
+		}
+		return bom, nil
+	}
+}
+
+// WriteXML writes the BOM as (indented) CycloneDX XML in the namespace of
+// its spec. version; use ConvertTo() first to write the BOM for another version.
+func (bom *Bom) WriteXML(writer io.Writer) error {
+	if !IsSupported(bom.SpecVersion) {
+		return fmt.Errorf("unsupported CycloneDX spec. version: `%s` (expected one of: %s)",
+			bom.SpecVersion, strings.Join(SpecVersions, ", "))
+	}
+
+	// Unlike the JSON schema, the 1.5 XSD has no bom-ref on license expressions
+	if CompareVersions(bom.SpecVersion, SPEC_VERSION_1_6) < 0 {
+		var restore []func()
+		walkLicenseChoices(reflect.ValueOf(bom).Elem(), func(choice *LicenseChoice) {
+			if choice.Expression != "" && choice.BOMRef != "" {
+				bomRef := choice.BOMRef
+				choice.BOMRef = ""
+				restore = append(restore, func() { choice.BOMRef = bomRef })
+			}
+		})
+		defer func() {
+			for _, undo := range restore {
+				undo()
+			}
+		}()
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	start := xml.StartElement{Name: xml.Name{Space: XMLNamespace(bom.SpecVersion), Local: "bom"}}
+	if err := encodeElement(encoder, reflect.ValueOf(bom), start); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func walkLicenseChoices(value reflect.Value, visit func(*LicenseChoice)) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			walkLicenseChoices(value.Elem(), visit)
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			walkLicenseChoices(value.Index(i), visit)
+		}
+	case reflect.Struct:
+		if choice, ok := value.Addr().Interface().(*LicenseChoice); ok {
+			visit(choice)
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			walkLicenseChoices(value.Field(i), visit)
+		}
+	}
+}
+
+var marshalerType = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+
+// encodeElement writes a (model) struct as encoder.EncodeElement() would,
+// except that the parents of `a>b` fields are only written for non-empty
+// values; encoding/xml writes (empty) parents regardless of omitempty.
+func encodeElement(encoder *xml.Encoder, value reflect.Value, start xml.StartElement) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || reflect.PtrTo(value.Type()).Implements(marshalerType) {
+		return encoder.EncodeElement(value.Interface(), start)
+	}
+
+	type field struct {
+		value   reflect.Value
+		name    string
+		options string
+	}
+	var fields []field
+	for i := 0; i < value.NumField(); i++ {
+		tag := value.Type().Field(i).Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+		name, options := tag, ""
+		if index := strings.Index(tag, ","); index >= 0 {
+			name, options = tag[:index], tag[index:]
+		}
+		if name == "" {
+			name = value.Type().Field(i).Name
+		}
+		fieldValue := value.Field(i)
+		if isEmptyXML(fieldValue) && (strings.Contains(options, ",omitempty") || strings.Contains(name, ">")) {
+			continue
+		}
+
+		if strings.Contains(options, ",attr") {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: name}, Value: fmt.Sprint(reflect.Indirect(fieldValue).Interface())})
+		} else {
+			fields = append(fields, field{fieldValue, name, options})
+		}
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range fields {
+		if strings.Contains(field.options, ",chardata") {
+			if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(field.value.Interface()))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path := strings.Split(field.name, ">")
+		parents, name := path[:len(path)-1], path[len(path)-1]
+		for _, parent := range parents {
+			if err := encoder.EncodeToken(element(parent)); err != nil {
+				return err
+			}
+		}
+		var err error
+		if field.value.Kind() == reflect.Slice && !field.value.Type().Implements(marshalerType) {
+			for i := 0; i < field.value.Len() && err == nil; i++ {
+				err = encodeElement(encoder, field.value.Index(i), element(name))
+			}
+		} else {
+			err = encodeElement(encoder, field.value, element(name))
+		}
+		if err != nil {
+			return err
+		}
+		for i := len(parents) - 1; i >= 0; i-- {
+			if err := encoder.EncodeToken(element(parents[i]).End()); err != nil {
+				return err
+			}
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// As encoding/xml's omitempty, i.e., structs are never empty
+func isEmptyXML(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return value.IsZero()
+}
+
+// Decode each child element of the current element (until its end element);
+// decode must either decode or skip the child.
+func decodeChildren(decoder *xml.Decoder, decode func(start xml.StartElement) error) error {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			if err = decode(token); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func element(name string) xml.StartElement {
+	return xml.StartElement{Name: xml.Name{Local: name}}
+}
+
+// Legacy tools (i.e., before 1.5) are a list of `<tool>`; otherwise, the tool
+// components and services. A BOM may only use one form (see MarshalJSON).
+func (tools Tools) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	type plain Tools
+	value := plain(tools)
+	if len(tools.Components) > 0 || len(tools.Services) > 0 {
+		value.Tools = nil
+	}
+	return encodeElement(encoder, reflect.ValueOf(value), start)
+}
+
+// An SPDX license expression (attributes are only supported since 1.6)
+type xmlExpression struct {
+	BOMRef          string `xml:"bom-ref,attr,omitempty"`
+	Acknowledgement string `xml:"acknowledgement,attr,omitempty"`
+	Expression      string `xml:",chardata"`
+}
+
+func (licenses Licenses) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, choice := range licenses {
+		var err error
+		if choice.License != nil {
+			err = encodeElement(encoder, reflect.ValueOf(choice.License), element("license"))
+		} else {
+			err = encoder.EncodeElement(xmlExpression{choice.BOMRef, choice.Acknowledgement, choice.Expression}, element("expression"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+func (licenses *Licenses) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(decoder, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "license":
+			license := new(License)
+			if err := decoder.DecodeElement(license, &child); err != nil {
+				return err
+			}
+			*licenses = append(*licenses, LicenseChoice{License: license})
+		case "expression":
+			var expression xmlExpression
+			if err := decoder.DecodeElement(&expression, &child); err != nil {
+				return err
+			}
+			*licenses = append(*licenses, LicenseChoice{
+				Expression:      expression.Expression,
+				BOMRef:          expression.BOMRef,
+				Acknowledgement: expression.Acknowledgement,
+			})
+		default:
+			return decoder.Skip()
+		}
+		return nil
+	})
+}
+
+type xmlClassification struct {
+	Flow           string `xml:"flow,attr"`
+	Classification string `xml:",chardata"`
+}
+
+type xmlDataFlow struct {
+	Name           string             `xml:"name,attr,omitempty"`
+	Description    string             `xml:"description,attr,omitempty"`
+	Classification *xmlClassification `xml:"classification,omitempty"`
+}
+
+// Data flows are only written as (named) `<dataflow>` if any of them has a name or description
+func (flows DataFlows) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	named := false
+	for _, flow := range flows {
+		named = named || flow.Name != "" || flow.Description != ""
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, flow := range flows {
+		classification := xmlClassification{flow.Flow, flow.Classification}
+		var err error
+		if named {
+			err = encoder.EncodeElement(xmlDataFlow{flow.Name, flow.Description, &classification}, element("dataflow"))
+		} else {
+			err = encoder.EncodeElement(classification, element("classification"))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+func (flows *DataFlows) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	return decodeChildren(decoder, func(child xml.StartElement) error {
+		switch child.Name.Local {
+		case "classification":
+			var classification xmlClassification
+			if err := decoder.DecodeElement(&classification, &child); err != nil {
+				return err
+			}
+			*flows = append(*flows, ServiceData{Flow: classification.Flow, Classification: classification.Classification})
+		case "dataflow":
+			var dataFlow xmlDataFlow
+			if err := decoder.DecodeElement(&dataFlow, &child); err != nil {
+				return err
+			}
+			flow := ServiceData{Name: dataFlow.Name, Description: dataFlow.Description}
+			if dataFlow.Classification != nil {
+				flow.Flow, flow.Classification = dataFlow.Classification.Flow, dataFlow.Classification.Classification
+			}
+			*flows = append(*flows, flow)
+		default:
+			return decoder.Skip()
+		}
+		return nil
+	})
+}
+
+// ------------------------------------------------------------------------
+// XML references (i.e., `<subject ref="..."/>`) to other BOM elements
+// ------------------------------------------------------------------------
+
+type xmlReference struct {
+	Ref string `xml:"ref,attr"`
+}
+
+func toReferences(refs []string) []xmlReference {
+	var references []xmlReference
+	for _, ref := range refs {
+		references = append(references, xmlReference{ref})
+	}
+	return references
+}
+
+func fromReferences(references []xmlReference) []string {
+	var refs []string
+	for _, reference := range references {
+		refs = append(refs, reference.Ref)
+	}
+	return refs
+}
+
+// Dependencies nest a `<dependency>` (reference) for each dependsOn
+type xmlDependency struct {
+	Ref          string         `xml:"ref,attr"`
+	Dependencies []xmlReference `xml:"dependency"`
+	Provides     []xmlReference `xml:"provides"`
+}
+
+func (dependency Dependency) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(encoder, reflect.ValueOf(xmlDependency{
+		Ref:          dependency.Ref,
+		Dependencies: toReferences(dependency.DependsOn),
+		Provides:     toReferences(dependency.Provides),
+	}), start)
+}
+
+func (dependency *Dependency) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var value xmlDependency
+	if err := decoder.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	*dependency = Dependency{
+		Ref:       value.Ref,
+		DependsOn: fromReferences(value.Dependencies),
+		Provides:  fromReferences(value.Provides),
+	}
+	return nil
+}
+
+type xmlComposition struct {
+	BOMRef          string         `xml:"bom-ref,attr,omitempty"`
+	Aggregate       string         `xml:"aggregate"`
+	Assemblies      []xmlReference `xml:"assemblies>assembly,omitempty"`
+	Dependencies    []xmlReference `xml:"dependencies>dependency,omitempty"`
+	Vulnerabilities []xmlReference `xml:"vulnerabilities>vulnerability,omitempty"`
+}
+
+func (composition Composition) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(encoder, reflect.ValueOf(xmlComposition{
+		BOMRef:          composition.BOMRef,
+		Aggregate:       composition.Aggregate,
+		Assemblies:      toReferences(composition.Assemblies),
+		Dependencies:    toReferences(composition.Dependencies),
+		Vulnerabilities: toReferences(composition.Vulnerabilities),
+	}), start)
+}
+
+func (composition *Composition) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var value xmlComposition
+	if err := decoder.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	*composition = Composition{
+		BOMRef:          value.BOMRef,
+		Aggregate:       value.Aggregate,
+		Assemblies:      fromReferences(value.Assemblies),
+		Dependencies:    fromReferences(value.Dependencies),
+		Vulnerabilities: fromReferences(value.Vulnerabilities),
+	}
+	return nil
+}
+
+type xmlAnnotation struct {
+	BOMRef    string         `xml:"bom-ref,attr,omitempty"`
+	Subjects  []xmlReference `xml:"subjects>subject"`
+	Annotator Annotator      `xml:"annotator"`
+	Timestamp string         `xml:"timestamp"`
+	Text      string         `xml:"text"`
+}
+
+func (annotation Annotation) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return encodeElement(encoder, reflect.ValueOf(xmlAnnotation{
+		BOMRef:    annotation.BOMRef,
+		Subjects:  toReferences(annotation.Subjects),
+		Annotator: annotation.Annotator,
+		Timestamp: annotation.Timestamp,
+		Text:      annotation.Text,
+	}), start)
+}
+
+func (annotation *Annotation) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var value xmlAnnotation
+	if err := decoder.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	*annotation = Annotation{
+		BOMRef:    value.BOMRef,
+		Subjects:  fromReferences(value.Subjects),
+		Annotator: value.Annotator,
+		Timestamp: value.Timestamp,
+		Text:      value.Text,
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mrutkows/go-skeleton/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseXMLFile(t *testing.T, filename string) *Bom {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	bom, err := ParseXML(file)
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return bom
+}
+
+func TestXMLModel(t *testing.T) {
+	bom := parseXMLFile(t, "testdata/valid-bom.xml")
+	assert.Equal(t, BOM_FORMAT, bom.BOMFormat)
+	assert.Equal(t, SPEC_VERSION_1_6, bom.SpecVersion)
+	assert.Equal(t, "urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79", bom.SerialNumber)
+	assert.Equal(t, 1, bom.Version)
+	assert.Equal(t, "MD5", bom.Components[0].Hashes[0].Algorithm)
+	assert.Equal(t, "3942447fac867ae5cdb3229b658f4d48", bom.Components[0].Hashes[0].Value)
+	assert.Equal(t, "Apache-2.0", bom.Components[0].Licenses[0].License.ID)
+
+	bom = parseXMLFile(t, "testdata/valid-license-expression.xml")
+	assert.Equal(t, "EPL-2.0 OR GPL-2.0 WITH Classpath-exception-2.0", bom.Components[0].Licenses[0].Expression)
+
+	bom = parseXMLFile(t, "testdata/valid-dependency.xml")
+	assert.Equal(t, "library-b", bom.Dependencies[1].Ref)
+	assert.Equal(t, []string{"library-c"}, bom.Dependencies[1].DependsOn)
+
+	bom = parseXMLFile(t, "testdata/valid-service.xml")
+	data := bom.Services[0].Data
+	assert.Equal(t, 4, len(data))
+	assert.Equal(t, ServiceData{Flow: "inbound", Classification: "PII"}, data[0])
+
+	bom = parseXMLFile(t, "testdata/valid-component-swid-full.xml")
+	swid := bom.Components[0].SWID
+	assert.Equal(t, "Acme Application", swid.Name)
+	assert.Equal(t, "base64", swid.Text.Encoding)
+}
+
+func TestParseXMLErrors(t *testing.T) {
+	for _, input := range []string{
+		``,
+		`<bom xmlns="http://cyclonedx.org/schema/bom/0.9"/>`,
+		`<bom xmlns="http://example.com/bom/1.5"/>`,
+		`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/>`,
+		`<bom xmlns="http://cyclonedx.org/schema/bom/1.5"><components>`,
+	} {
+		_, err := ParseXML(strings.NewReader(input))
+		assert.Error(t, err, input)
+	}
+}
+
+// A BOM written as XML, then read back, must not lose (or alter) any property
+func TestXMLRoundTrip(t *testing.T) {
+	filenames, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, filename := range filenames {
+		bom := parseJSONFile(t, filename)
+		var expected bytes.Buffer
+		assert.NoError(t, bom.WriteJSON(&expected))
+
+		// the JSON schema URL has no XML equivalent
+		jsonSchema := bom.JSONSchema
+		var output bytes.Buffer
+		assert.NoError(t, bom.WriteXML(&output))
+		bom, err = ParseXML(&output)
+		assert.NoError(t, err, filename)
+		bom.JSONSchema = jsonSchema
+
+		var actual bytes.Buffer
+		assert.NoError(t, bom.WriteJSON(&actual))
+		assert.JSONEq(t, expected.String(), actual.String(), filename)
+	}
+}
+
+// Every BOM, converted to any spec. version, must be valid against that version's XSD
+func TestWriteXMLValidates(t *testing.T) {
+	filenames, err := filepath.Glob("testdata/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, specVersion := range SpecVersions {
+		embeddedSchema, err := schema.LookupXML(schema.FORMAT_CYCLONEDX, specVersion)
+		assert.NoError(t, err)
+		xmlSchema, err := embeddedSchema.CompileXML()
+		assert.NoError(t, err)
+
+		for _, filename := range filenames {
+			bom := parseJSONFile(t, filename)
+			_, err := bom.ConvertTo(specVersion)
+			assert.NoError(t, err)
+
+			var output bytes.Buffer
+			assert.NoError(t, bom.WriteXML(&output))
+			assert.True(t, strings.Contains(output.String(), `xmlns="`+XMLNamespace(specVersion)+`"`))
+			xmlErrors, err := xmlSchema.Validate(&output)
+			assert.NoError(t, err)
+			assert.Empty(t, xmlErrors, "%s (%s)", filename, specVersion)
+		}
+	}
+}
+
+// Only 1.6 supports a bom-ref on license expressions; it is kept in the model
+func TestWriteXMLExpressionRef(t *testing.T) {
+	bom := &Bom{
+		BOMFormat:   BOM_FORMAT,
+		SpecVersion: SPEC_VERSION_1_5,
+		Components: []Component{{
+			Type:     "library",
+			Name:     "acme",
+			Licenses: Licenses{{Expression: "MIT OR Apache-2.0", BOMRef: "license-1"}},
+		}},
+	}
+	var output bytes.Buffer
+	assert.NoError(t, bom.WriteXML(&output))
+	assert.Contains(t, output.String(), "<expression>MIT OR Apache-2.0</expression>")
+	assert.Equal(t, "license-1", bom.Components[0].Licenses[0].BOMRef)
+
+	bom.SpecVersion = SPEC_VERSION_1_6
+	output.Reset()
+	assert.NoError(t, bom.WriteXML(&output))
+	assert.Contains(t, output.String(), `<expression bom-ref="license-1">MIT OR Apache-2.0</expression>`)
+}
+
+func TestXMLDataFlows(t *testing.T) {
+	service := Service{Name: "api", Data: DataFlows{
+		{Flow: "inbound", Classification: "PII"},
+		{Flow: "outbound", Classification: "public", Name: "telemetry"},
+	}}
+	data, err := json.Marshal(service)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"name":"telemetry"`)
+
+	var output bytes.Buffer
+	bom := &Bom{BOMFormat: BOM_FORMAT, SpecVersion: SPEC_VERSION_1_6, Services: []Service{service}}
+	assert.NoError(t, bom.WriteXML(&output))
+	assert.Contains(t, output.String(), `<dataflow name="telemetry">`)
+
+	bom, err = ParseXML(&output)
+	assert.NoError(t, err)
+	assert.Equal(t, service.Data, bom.Services[0].Data)
+}
@@ -0,0 +1,133 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// Colorization modes (e.g., of the `--color` flag)
+const (
+	COLOR_AUTO = "auto" // colorize output to terminals only (default)
+	COLOR_ON   = "on"
+	COLOR_OFF  = "off"
+)
+
+var ColorModes = []string{COLOR_AUTO, COLOR_ON, COLOR_OFF}
+
+// Environment variables honored in COLOR_AUTO mode (see https://no-color.org)
+const (
+	ENV_NO_COLOR    = "NO_COLOR"
+	ENV_FORCE_COLOR = "FORCE_COLOR"
+)
+
+func ValidateColorMode(mode string) error {
+	switch mode {
+	case COLOR_AUTO, COLOR_ON, COLOR_OFF:
+		return nil
+	}
+	return fmt.Errorf("unsupported color mode: `%s` (expected one of: %s)", mode, strings.Join(ColorModes, ", "))
+}
+
+// UseColor decides whether output to the writer is colorized. COLOR_ON and
+// COLOR_OFF are explicit (i.e., override the environment); in COLOR_AUTO mode,
+// a (non-empty) NO_COLOR disables, and then a FORCE_COLOR (other than "0" or
+// "false") enables color; otherwise, only output to a terminal is colorized.
+func UseColor(mode string, writer io.Writer) bool {
+	switch mode {
+	case COLOR_ON:
+		return true
+	case COLOR_OFF:
+		return false
+	}
+	if os.Getenv(ENV_NO_COLOR) != "" {
+		return false
+	}
+	if force := os.Getenv(ENV_FORCE_COLOR); force != "" {
+		return force != "0" && force != "false"
+	}
+	return isTerminal(writer)
+}
+
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	return ok && (isatty.IsTerminal(file.Fd()) || isatty.IsCygwinTerminal(file.Fd()))
+}
+
+// Colors are always enabled here; whether to colorize is decided by UseColor()
+// (rather than by the color package, which only checks if stdout is a terminal)
+func newColor(attributes ...color.Attribute) *color.Color {
+	c := color.New(attributes...)
+	c.EnableColor()
+	return c
+}
+
+func colorize(c *color.Color, colored bool, value string) string {
+	if !colored || c == nil {
+		return value
+	}
+	return c.Sprint(value)
+}
+
+// See colors here: https://en.wikipedia.org/wiki/ANSI_escape_code#Colors
+var levelColors = map[Level]*color.Color{
+	DEBUG:   newColor(color.FgGreen),
+	TRACE:   newColor(color.FgCyan),
+	INFO:    newColor(color.FgWhite),
+	WARNING: newColor(color.FgHiYellow),
+	ERROR:   newColor(color.FgHiRed),
+}
+
+// Colors of (formatted) keys and values by kind
+var (
+	keyColor    = newColor(color.FgWhite, color.Bold)
+	stringColor = newColor(color.FgGreen)
+	numberColor = newColor(color.FgCyan)
+	boolColor   = newColor(color.FgYellow)
+	nilColor    = newColor(color.FgMagenta)
+	errorColor  = newColor(color.FgHiRed)
+)
+
+// The colorization mode of FormatStruct() and FormatInterfaceAsPrettyJson()
+// Note: read by each formatter (e.g., while logging); so, set atomically
+var formatColor atomic.Value
+
+func init() {
+	formatColor.Store(COLOR_AUTO)
+}
+
+// SetFormatColor sets the colorization mode of formatted output; in COLOR_AUTO
+// mode, it is colorized if stderr (i.e., the default log output) is a terminal.
+func SetFormatColor(mode string) error {
+	if err := ValidateColorMode(mode); err != nil {
+		return err
+	}
+	formatColor.Store(mode)
+	return nil
+}
+
+func formatColored() bool {
+	return UseColor(formatColor.Load().(string), os.Stderr)
+}
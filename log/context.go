@@ -0,0 +1,101 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"sort"
+)
+
+// With returns a child logger that adds the field to every record it logs,
+// e.g., ProjectLogger.With("document", filename)
+// Note: child loggers share their parent's configuration (e.g., level and
+// outputs); changing it (using either) changes it for both
+func (log *MiniLogger) With(name string, value interface{}) *MiniLogger {
+	return &MiniLogger{
+		core:   log.core,
+		fields: mergeFields(log.fields, []NamedValue{{Name: name, Value: value}}),
+	}
+}
+
+// WithFields returns a child logger (see With()) that adds all the fields;
+// they are output sorted by name
+func (log *MiniLogger) WithFields(fields map[string]interface{}) *MiniLogger {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	added := make([]NamedValue, len(names))
+	for i, name := range names {
+		added[i] = NamedValue{Name: name, Value: fields[name]}
+	}
+	return &MiniLogger{
+		core:   log.core,
+		fields: mergeFields(log.fields, added),
+	}
+}
+
+// GetFields returns the fields the logger adds to every record
+func (log *MiniLogger) GetFields() []NamedValue {
+	return append([]NamedValue(nil), log.fields...)
+}
+
+// The fields, followed by the overrides; a field is replaced (in place)
+// by an override of the same name
+// Note: neither slice is modified, as both may be shared
+func mergeFields(fields []NamedValue, overrides []NamedValue) []NamedValue {
+	if len(overrides) == 0 {
+		return fields
+	} else if len(fields) == 0 {
+		return overrides
+	}
+
+	merged := append(make([]NamedValue, 0, len(fields)+len(overrides)), fields...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == override.Name {
+				merged[i].Value, replaced = override.Value, true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the context that carries the logger, e.g.,
+// for a command to pass its (child) logger down to the functions it calls
+func NewContext(ctx context.Context, logger *MiniLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by the context (see NewContext()), if any
+func FromContext(ctx context.Context) (*MiniLogger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	logger, ok := ctx.Value(contextKey{}).(*MiniLogger)
+	return logger, ok && logger != nil
+}
@@ -0,0 +1,310 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Log output formats
+const (
+	FORMAT_TEXT = "text" // human-readable (and, for TRACE and DEBUG, timestamped) lines
+	FORMAT_JSON = "json" // one JSON object per line, e.g., for log aggregators
+)
+
+var Formats = []string{FORMAT_TEXT, FORMAT_JSON}
+
+// A single log entry, before it is encoded in the logger's format
+type logRecord struct {
+	level     Level
+	time      time.Time
+	file      string // basic filename (e.g., "root.go")
+	line      int
+	pc        uintptr // of the caller (if known)
+	function  string  // module.function (e.g., "cmd.Execute")
+	goroutine int64   // ID of the goroutine that logged the record (0, if not looked up)
+	tag       string  // e.g., "ENTER"
+	value     interface{}
+	fields    []NamedValue // of the goroutine's context
+	nested    bool         // whether the nesting level (depth) is tracked, i.e., indentation is enabled
+	depth     int
+	indent    string // (per nesting level) indentation
+}
+
+// The (typed) arguments of, or values returned by, a function as traced by
+// Enter() and Exit(); values longer than maxLength (if > 0) are cut short
+type callValues struct {
+	values    []interface{}
+	maxLength int
+}
+
+// A function argument (or return value) paired with its (parameter) name
+type NamedValue struct {
+	Name  string
+	Value interface{}
+}
+
+// Arg names an argument traced by Enter(), e.g., Enter(log.Arg("format", format))
+func Arg(name string, value interface{}) NamedValue {
+	return NamedValue{Name: name, Value: value}
+}
+
+// At most, the first MAX_HEX_PREVIEW bytes of []byte values are shown (as hex)
+const MAX_HEX_PREVIEW = 16
+
+func (values callValues) String() string {
+	return values.format(false)
+}
+
+// e.g., "(format(string):json, (int):2)"; errors are highlighted (if colored)
+func (values callValues) format(colored bool) string {
+	redactor := GetRedactor()
+	sb := bytes.NewBufferString("(")
+	for index, value := range values.values {
+		name, formatted := "", ""
+		if named, ok := value.(NamedValue); ok {
+			name, value = named.Name, named.Value
+		}
+		if redactor.IsSecretName(name) {
+			formatted = REDACTED
+		} else {
+			formatted = formatValue(redactor, value, values.maxLength)
+		}
+		typed := fmt.Sprintf("%s(%T):%s", name, value, formatted)
+		if _, isError := value.(error); isError {
+			typed = colorize(errorColor, colored, typed)
+		}
+		sb.WriteString(typed)
+		if (index + 1) < len(values.values) {
+			sb.WriteString(", ")
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// Note: values are redacted before they are cut short (so that no part of a secret remains)
+func formatValue(redactor *Redactor, value interface{}, maxLength int) string {
+	if data, ok := value.([]byte); ok {
+		if redactor.redactsBytes(data) {
+			return fmt.Sprintf("len=%d %s", len(data), REDACTED)
+		}
+		return formatBytes(data)
+	}
+	return truncate(redactor.redactText(fmt.Sprintf("%+v", value)), maxLength)
+}
+
+// e.g., "len=1024 hex=3c3f786d6c2076657273696f6e3d2231..."
+func formatBytes(data []byte) string {
+	preview, more := data, ""
+	if len(preview) > MAX_HEX_PREVIEW {
+		preview, more = preview[:MAX_HEX_PREVIEW], "..."
+	}
+	return fmt.Sprintf("len=%d hex=%x%s", len(data), preview, more)
+}
+
+func truncate(text string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+	return string([]rune(text)[:maxLength]) + "..."
+}
+
+// e.g., "[TRACE] [2022-06-01 17:10:04.163212] root.go(55) cmd.init.1(): ENTER"
+func encodeText(record logRecord, colored bool) []byte {
+	redactor := GetRedactor()
+	// Setup "string builder" and initialize with log-level prefix
+	sb := bytes.NewBufferString(fmt.Sprintf("[%s] ", colorize(levelColors[record.level], colored, LevelNames[record.level])))
+
+	// Append UTC timestamp if TRACE (or DEBUG) enabled
+	if record.level == TRACE || record.level == DEBUG {
+		// UTC time shows fractions of a second
+		// TODO: add setting to show milli or micro seconds supported by "time" package
+		tmp := record.time.String()
+		// create a (left) slice of the timestamp omitting the " +0000 UTC" portion
+		sb.WriteString(fmt.Sprintf("[%s] ", tmp[:strings.Index(tmp, "+")-1]))
+	}
+
+	sb.WriteString(strings.Repeat(record.indent, record.depth))
+	sb.WriteString(fmt.Sprintf("%s(%d) %s()", record.file, record.line, record.function))
+
+	// Append (optional) tag; call (i.e., entry and exit) values are appended to the tag
+	tag, value := record.tag, record.value
+	if values, ok := value.(callValues); ok {
+		tag, value = tag+values.format(colored), nil
+	}
+	if tag != "" {
+		sb.WriteString(fmt.Sprintf(": %s", tag))
+	}
+
+	// Append (optional) value
+	if value != nil {
+		sb.WriteString(": " + redactor.redactText(fmt.Sprintf("%+v", value)))
+	}
+
+	// Append (context) fields, e.g., " {file=bom.json, format=json}"
+	if len(record.fields) > 0 {
+		sb.WriteString(" {")
+		for index, field := range record.fields {
+			if index > 0 {
+				sb.WriteString(", ")
+			}
+			value := redactor.redactText(fmt.Sprintf("%+v", redactor.redactField(field.Name, field.Value)))
+			sb.WriteString(fmt.Sprintf("%s=%s", colorize(keyColor, colored, field.Name), value))
+		}
+		sb.WriteByte('}')
+	}
+	sb.WriteByte('\n')
+	return sb.Bytes()
+}
+
+type jsonRecord struct {
+	Level     string          `json:"level"`
+	Time      string          `json:"time"`
+	File      string          `json:"file"`
+	Line      int             `json:"line"`
+	Function  string          `json:"function"`
+	Goroutine int64           `json:"goroutine,omitempty"`
+	Depth     *int            `json:"depth,omitempty"`
+	Tag       string          `json:"tag,omitempty"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	Fields    json.RawMessage `json:"fields,omitempty"`
+}
+
+// e.g., {"level":"TRACE","time":"2022-06-01T17:10:04.163212Z","file":"root.go","line":55,"function":"cmd.init.1","goroutine":1,"tag":"ENTER"}
+func encodeJSON(record logRecord) []byte {
+	redactor := GetRedactor()
+	var depth *int
+	if record.nested {
+		depth = &record.depth
+	}
+	data, err := marshalJSON(jsonRecord{
+		Level:     record.level.String(),
+		Time:      record.time.Format(time.RFC3339Nano),
+		File:      record.file,
+		Line:      record.line,
+		Function:  record.function,
+		Goroutine: record.goroutine,
+		Depth:     depth,
+		Tag:       record.tag,
+		Value:     redactor.redactJSON(jsonValue(record.value)),
+		Fields:    jsonFields(redactor, record.fields),
+	})
+	if err != nil {
+		// Note: should not happen; all values are (already) encoded
+		data, _ = marshalJSON(redactor.redactText(fmt.Sprintf("%+v", record)))
+	}
+	return append(data, '\n')
+}
+
+// Structured values are kept as JSON; errors (and values JSON cannot encode) as strings
+func jsonValue(value interface{}) json.RawMessage {
+	switch typed := value.(type) {
+	case nil:
+		return nil
+	case error:
+		value = typed.Error()
+	case callValues:
+		elements := make([]json.RawMessage, len(typed.values))
+		for i, element := range typed.values {
+			elements[i] = jsonCallValue(GetRedactor(), element, typed.maxLength)
+		}
+		value = elements
+	}
+
+	data, err := marshalJSON(value)
+	if err != nil {
+		data, _ = marshalJSON(fmt.Sprintf("%+v", value))
+	}
+	return data
+}
+
+// Fields become an object (keeping the order they were set in)
+func jsonFields(redactor *Redactor, fields []NamedValue) json.RawMessage {
+	if len(fields) == 0 {
+		return nil
+	}
+	sb := bytes.NewBufferString("{")
+	for index, field := range fields {
+		if index > 0 {
+			sb.WriteByte(',')
+		}
+		name, _ := marshalJSON(field.Name)
+		sb.Write(name)
+		sb.WriteByte(':')
+		if value := redactor.redactJSON(jsonValue(redactor.redactField(field.Name, field.Value))); value != nil {
+			sb.Write(value)
+		} else {
+			sb.WriteString("null")
+		}
+	}
+	sb.WriteByte('}')
+	return sb.Bytes()
+}
+
+// Named values become {"name":..., "value":...}, []byte values {"length":..., "hex":...}
+// and (only) strings are cut short
+// Note: (as formatValue()) values are redacted before they are cut short
+func jsonCallValue(redactor *Redactor, value interface{}, maxLength int) json.RawMessage {
+	switch typed := value.(type) {
+	case nil:
+		return json.RawMessage("null")
+	case NamedValue:
+		data, _ := marshalJSON(struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		}{typed.Name, jsonCallValue(redactor, redactor.redactField(typed.Name, typed.Value), maxLength)})
+		return data
+	case []byte:
+		if redactor.redactsBytes(typed) {
+			data, _ := marshalJSON(struct {
+				Length int    `json:"length"`
+				Hex    string `json:"hex"`
+			}{len(typed), REDACTED})
+			return data
+		}
+		preview, more := typed, ""
+		if len(preview) > MAX_HEX_PREVIEW {
+			preview, more = preview[:MAX_HEX_PREVIEW], "..."
+		}
+		data, _ := marshalJSON(struct {
+			Length int    `json:"length"`
+			Hex    string `json:"hex"`
+		}{len(typed), fmt.Sprintf("%x%s", preview, more)})
+		return data
+	case string:
+		value = truncate(redactor.RedactString(typed), maxLength)
+	}
+	return redactor.redactJSON(jsonValue(value))
+}
+
+// As json.Marshal(), but without escaping HTML characters (e.g., `<`)
+func marshalJSON(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
@@ -18,72 +18,490 @@
 package log
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
 
+	"github.com/fatih/color"
 	"github.com/hokaccha/go-prettyjson"
+	"gopkg.in/yaml.v3"
 )
 
-func FormatMap(mapName string, field map[string]interface{}) (string, error) {
+// Maps can (also) be formatted as FORMAT_JSON or FORMAT_YAML
+const FORMAT_YAML = "yaml"
 
-	var sb strings.Builder
+var MapFormats = []string{FORMAT_TEXT, FORMAT_JSON, FORMAT_YAML}
+
+// FormatMap formats the map (of any type) entry by entry, sorted by key and
+// recursing into nested maps, slices and structs (see FormatStruct())
+// Output is colorized as configured by SetFormatColor()
+func FormatMap(mapName string, field interface{}) (string, error) {
+	return FormatMapAs(mapName, field, FORMAT_TEXT)
+}
 
-	if reflect.ValueOf(field).Kind() != reflect.Map {
+// FormatMapAs formats the map as text (see FormatMap()), JSON or YAML; the
+// name only titles text output. All (nested) maps are sorted by key, so
+// that output is the same for the same map.
+func FormatMapAs(mapName string, field interface{}, format string) (string, error) {
+	formatter := newStructFormatter(formatColored())
+	value := reflect.ValueOf(field)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Map {
 		return "", fmt.Errorf("invalid `Map`; actual Type: (%v)", reflect.TypeOf(field))
 	}
 
-	// m is a map[string]interface.
-	// loop over keys and values in the map.
-	for k, v := range field {
-		sb.WriteString(fmt.Sprintf("[%s]: %+v", k, v))
+	switch format {
+	case FORMAT_TEXT:
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("  %s (%s) = {\n", mapName, reflect.TypeOf(field)))
+		if value.Len() > 0 {
+			sb.WriteString(formatter.entries(value, 1))
+		} else {
+			sb.WriteString("\t<empty>\n")
+		}
+		sb.WriteString("  }\n")
+		return sb.String(), nil
+	case FORMAT_JSON:
+		var buffer bytes.Buffer
+		encoder := json.NewEncoder(&buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(formatter.tree(value, 0)); err != nil {
+			return "", err
+		}
+		return buffer.String(), nil
+	case FORMAT_YAML:
+		data, err := yaml.Marshal(formatter.tree(value, 0))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
 	}
+	return "", fmt.Errorf("unsupported map format: `%s` (expected one of: %s)", format, strings.Join(MapFormats, ", "))
+}
 
-	return sb.String(), nil
+// Struct fields are formatted as their `log` tag specifies, e.g.,
+// `log:"name"` renames, `log:"-"` hides and `log:",redact"` (or
+// `log:"name,redact"`) redacts the field (i.e., its value is not output)
+// Note: fields (and map entries) with secret names are also redacted (see Redactor)
+const STRUCT_TAG = "log"
+
+// Nested structs, slices and maps (deeper than this) are elided, e.g., "{...}"
+const DEFAULT_FORMAT_DEPTH = 5
+
+// Note: read by each formatter (e.g., while logging); so, set atomically
+var formatDepth atomic.Int64
+
+func init() {
+	formatDepth.Store(DEFAULT_FORMAT_DEPTH)
+}
+
+// SetFormatDepth sets the depth (of nested structs, slices and maps) of
+// FormatStruct() output; 0 (or less) is unlimited
+func SetFormatDepth(depth int) {
+	formatDepth.Store(int64(depth))
 }
 
+func GetFormatDepth() int {
+	return int(formatDepth.Load())
+}
+
+// FormatStruct formats the struct (or pointer to a struct) field by field,
+// following pointers and recursing into nested structs, slices and maps
+// Output is colorized as configured by SetFormatColor()
 func FormatStruct(structName string, field interface{}) (string, error) {
+	return formatStruct(structName, field, formatColored())
+}
+
+func formatStruct(structName string, field interface{}, colored bool) (string, error) {
+	formatter := newStructFormatter(colored)
 
-	if reflect.ValueOf(field).Kind() != reflect.Struct {
+	value := reflect.ValueOf(field)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return "", fmt.Errorf("invalid `Struct`; nil pointer of Type: (%v)", reflect.TypeOf(field))
+		}
+		formatter.enter(value)
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
 		return "", fmt.Errorf("invalid `Struct`; actual Type: (%v)", reflect.TypeOf(field))
 	}
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("  %s (%s) = {\n", structName, reflect.TypeOf(field)))
+	if fields := formatter.fields(value, 1); fields != "" {
+		sb.WriteString(fields)
+	} else {
+		sb.WriteString("\t<empty>\n")
+	}
+	sb.WriteString("  }\n")
+	return sb.String(), nil
+}
+
+// Colorize keys/values (if enabled):
+// i.e., keys=white, string=green, floats/ints=cyan, bool=yellow, nil=magenta
+type structFormatter struct {
+	colored  bool
+	maxDepth int
+	redactor *Redactor
+	visiting map[visit]bool // pointers, maps and slices being formatted, i.e., to detect cycles
+}
 
-	structNames := reflect.TypeOf(field)
-	numNames := structNames.NumField()
+// A pointer, map or slice (by its elements, which slices of other lengths,
+// or other types, may share) being formatted
+type visit struct {
+	pointer   uintptr
+	length    int
+	valueType reflect.Type // e.g., a struct and (a pointer to) its first field share the address
+}
 
-	// TODO: optionally, colorize keys/values; see "github.com/fatih/color" package
-	// e.g., keys=white, string=green, floats/ints=cyan, bool=yellow, nil=magenta
-	if numNames > 0 {
-		flagValues := reflect.ValueOf(field)
-		var name string
-		var value interface{}
-		var fieldType string
+func newStructFormatter(colored bool) *structFormatter {
+	return &structFormatter{
+		colored:  colored,
+		maxDepth: GetFormatDepth(),
+		redactor: GetRedactor(),
+		visiting: map[visit]bool{},
+	}
+}
 
-		for i := 0; i < numNames; i++ {
-			name = structNames.Field(i).Name
-			// TODO: using the .String() method interace reduces `[]byte` values
-			// to "<[]uint8 Value>"; if you remove it, you see ALL the bytes
-			// A better solution might be to show the first 'x' bytes (slice/truncate)
-			value = flagValues.Field(i).String()
+// enter marks the (pointer, map or slice) value as being formatted; false
+// if it already is, i.e., the value (indirectly) contains itself
+func (formatter *structFormatter) enter(value reflect.Value) bool {
+	if formatter.visiting[visitOf(value)] {
+		return false
+	}
+	formatter.visiting[visitOf(value)] = true
+	return true
+}
 
-			//fmt.Printf("%t\n", reflect.Type.Field(i))
+func (formatter *structFormatter) leave(value reflect.Value) {
+	delete(formatter.visiting, visitOf(value))
+}
 
-			// reflect.ValueOf(flagValues.Field(i)).Kind() == reflect.Array
+func visitOf(value reflect.Value) visit {
+	if value.Kind() == reflect.Slice {
+		return visit{value.Pointer(), value.Len(), value.Type()}
+	}
+	return visit{value.Pointer(), 0, value.Type()}
+}
 
-			fieldType = fmt.Sprintf("(%+v)", flagValues.Field(i).Type())
-			line := fmt.Sprintf("\t%12s %-10s %s %v\n", name, fieldType, ":", value)
-			sb.WriteString(line)
+// One line per (visible) field, indented by depth
+func (formatter *structFormatter) fields(value reflect.Value, depth int) string {
+	var sb strings.Builder
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		name, hidden, redacted := parseStructTag(structType.Field(i))
+		redacted = redacted || formatter.redactor.IsSecretName(structType.Field(i).Name) || formatter.redactor.IsSecretName(name)
+		if !hidden {
+			formatter.writeEntry(&sb, name, value.Field(i), redacted, depth)
 		}
-	} else {
-		sb.WriteString("\t<empty>\n")
 	}
-	sb.WriteString("  }\n")
+	return sb.String()
+}
 
-	return sb.String(), nil
+// e.g., `log:"name,redact"`
+func parseStructTag(field reflect.StructField) (name string, hidden bool, redacted bool) {
+	name = field.Name
+	tag, ok := field.Tag.Lookup(STRUCT_TAG)
+	if !ok {
+		return
+	}
+	if tag == "-" {
+		return name, true, false
+	}
+	options := strings.Split(tag, ",")
+	if options[0] != "" {
+		name = options[0]
+	}
+	for _, option := range options[1:] {
+		if option == "redact" {
+			redacted = true
+		}
+	}
+	return
+}
+
+// e.g., "\t        Name (string)   : value"; struct fields, slice elements
+// (e.g., "[0]") and map entries (e.g., "[key]") alike
+func (formatter *structFormatter) writeEntry(sb *strings.Builder, name string, value reflect.Value, redacted bool, depth int) {
+	valueType := value.Type()
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		valueType = value.Elem().Type()
+	}
+	formatted := REDACTED
+	if !redacted {
+		formatted = formatter.format(value, depth)
+	}
+	// Note: pad (the name) before colorizing, as escape codes have no width
+	sb.WriteString(fmt.Sprintf("%s%s %-10s : %s\n", strings.Repeat("\t", depth),
+		colorize(keyColor, formatter.colored, fmt.Sprintf("%12s", name)), fmt.Sprintf("(%v)", valueType), formatted))
+}
+
+func (formatter *structFormatter) format(value reflect.Value, depth int) string {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return colorize(nilColor, formatter.colored, "<nil>")
+		}
+		if value.Kind() == reflect.Ptr {
+			if !formatter.enter(value) {
+				return colorize(nilColor, formatter.colored, "<cycle>")
+			}
+			defer formatter.leave(value)
+		}
+		return formatter.format(value.Elem(), depth)
+	case reflect.Struct:
+		// e.g., time.Time (whose fields are all unexported)
+		if stringer, ok := asStringer(value); ok && !hasExportedFields(value.Type()) {
+			return formatter.redactor.RedactString(stringer.String())
+		}
+		if formatter.elided(depth) {
+			return "{...}"
+		}
+		return "{\n" + formatter.fields(value, depth+1) + strings.Repeat("\t", depth) + "}"
+	case reflect.Map:
+		if value.IsNil() {
+			return colorize(nilColor, formatter.colored, "<nil>")
+		} else if value.Len() == 0 {
+			return "{}"
+		} else if formatter.elided(depth) {
+			return "{...}"
+		} else if !formatter.enter(value) {
+			return colorize(nilColor, formatter.colored, "<cycle>")
+		}
+		defer formatter.leave(value)
+		return "{\n" + formatter.entries(value, depth+1) + strings.Repeat("\t", depth) + "}"
+	case reflect.Slice:
+		if value.IsNil() {
+			return colorize(nilColor, formatter.colored, "<nil>")
+		} else if value.Type().Elem().Kind() == reflect.Uint8 {
+			if formatter.redactor.redactsBytes(value.Bytes()) {
+				return fmt.Sprintf("len=%d %s", value.Len(), REDACTED)
+			}
+			return formatBytes(value.Bytes())
+		} else if value.Len() > 0 {
+			if !formatter.enter(value) {
+				return colorize(nilColor, formatter.colored, "<cycle>")
+			}
+			defer formatter.leave(value)
+		}
+		fallthrough
+	case reflect.Array:
+		if value.Len() == 0 {
+			return "[]"
+		} else if formatter.elided(depth) {
+			return "[...]"
+		}
+		var sb strings.Builder
+		for i := 0; i < value.Len(); i++ {
+			formatter.writeEntry(&sb, fmt.Sprintf("[%d]", i), value.Index(i), false, depth+1)
+		}
+		return "[\n" + sb.String() + strings.Repeat("\t", depth) + "]"
+	}
+	// Note: fmt uses the String() method (if any) of (exported) values
+	return colorize(valueColor(value), formatter.colored, formatter.redactor.RedactString(fmt.Sprintf("%v", value)))
+}
+
+// One line per map entry (sorted by key, e.g., "[key]"), indented by depth
+func (formatter *structFormatter) entries(value reflect.Value, depth int) string {
+	var sb strings.Builder
+	for _, key := range sortedKeys(value) {
+		name := fmt.Sprintf("%v", key)
+		formatter.writeEntry(&sb, "["+name+"]", value.MapIndex(key), formatter.redactor.IsSecretName(name), depth)
+	}
+	return sb.String()
+}
+
+// Numbers are sorted by value; all other keys as (formatted) strings
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		switch keys[i].Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return keys[i].Int() < keys[j].Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return keys[i].Uint() < keys[j].Uint()
+		case reflect.Float32, reflect.Float64:
+			return keys[i].Float() < keys[j].Float()
+		}
+		return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+	})
+	return keys
+}
+
+// A tree of the value that JSON (and YAML) can encode, i.e., where maps
+// (of any key type, sorted by key) and structs (per their `log` tags)
+// become orderedMaps and pointers are followed; secrets are redacted
+func (formatter *structFormatter) tree(value reflect.Value, depth int) interface{} {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Ptr {
+			if !formatter.enter(value) {
+				return "<cycle>"
+			}
+			defer formatter.leave(value)
+		}
+		return formatter.tree(value.Elem(), depth)
+	case reflect.Struct:
+		if stringer, ok := asStringer(value); ok && !hasExportedFields(value.Type()) {
+			return formatter.redactor.RedactString(stringer.String())
+		} else if formatter.elided(depth) {
+			return "{...}"
+		}
+		fields := orderedMap{}
+		structType := value.Type()
+		for i := 0; i < structType.NumField(); i++ {
+			name, hidden, redacted := parseStructTag(structType.Field(i))
+			if hidden {
+				continue
+			} else if redacted || formatter.redactor.IsSecretName(structType.Field(i).Name) || formatter.redactor.IsSecretName(name) {
+				fields = append(fields, NamedValue{name, REDACTED})
+			} else {
+				fields = append(fields, NamedValue{name, formatter.tree(value.Field(i), depth+1)})
+			}
+		}
+		return fields
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		} else if value.Len() > 0 && formatter.elided(depth) {
+			return "{...}"
+		} else if !formatter.enter(value) {
+			return "<cycle>"
+		}
+		defer formatter.leave(value)
+
+		entries := make(orderedMap, 0, value.Len())
+		for _, key := range sortedKeys(value) {
+			name := fmt.Sprintf("%v", key)
+			if formatter.redactor.IsSecretName(name) {
+				entries = append(entries, NamedValue{name, REDACTED})
+			} else {
+				entries = append(entries, NamedValue{name, formatter.tree(value.MapIndex(key), depth+1)})
+			}
+		}
+		return entries
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		} else if value.Type().Elem().Kind() == reflect.Uint8 {
+			if formatter.redactor.redactsBytes(value.Bytes()) {
+				return fmt.Sprintf("len=%d %s", value.Len(), REDACTED)
+			}
+			return formatBytes(value.Bytes())
+		} else if value.Len() > 0 {
+			if !formatter.enter(value) {
+				return "<cycle>"
+			}
+			defer formatter.leave(value)
+		}
+		fallthrough
+	case reflect.Array:
+		if value.Len() > 0 && formatter.elided(depth) {
+			return "[...]"
+		}
+		elements := make([]interface{}, value.Len())
+		for i := range elements {
+			elements[i] = formatter.tree(value.Index(i), depth+1)
+		}
+		return elements
+	case reflect.String:
+		return formatter.redactor.RedactString(value.String())
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Sprintf("%v", value)
+	}
+	// e.g., log.Level (as "TRACE") and time.Duration (as "1s")
+	if stringer, ok := asStringer(value); ok {
+		return formatter.redactor.RedactString(stringer.String())
+	} else if value.CanInterface() {
+		return value.Interface()
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// Entries (of a map or struct) encoded (as a JSON object or YAML mapping) in order
+type orderedMap []NamedValue
+
+func (entries orderedMap) MarshalJSON() ([]byte, error) {
+	sb := bytes.NewBufferString("{")
+	for index, entry := range entries {
+		if index > 0 {
+			sb.WriteByte(',')
+		}
+		name, _ := marshalJSON(entry.Name)
+		value, err := marshalJSON(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+		sb.Write(name)
+		sb.WriteByte(':')
+		sb.Write(value)
+	}
+	sb.WriteByte('}')
+	return sb.Bytes(), nil
+}
+
+func (entries orderedMap) MarshalYAML() (interface{}, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	for _, entry := range entries {
+		value := &yaml.Node{}
+		if err := value.Encode(entry.Value); err != nil {
+			return nil, err
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: entry.Name}, value)
+	}
+	return mapping, nil
+}
+
+func (formatter *structFormatter) elided(depth int) bool {
+	return formatter.maxDepth > 0 && depth > formatter.maxDepth
+}
+
+func asStringer(value reflect.Value) (fmt.Stringer, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+	stringer, ok := value.Interface().(fmt.Stringer)
+	return stringer, ok
+}
+
+func hasExportedFields(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+func valueColor(value reflect.Value) *color.Color {
+	switch value.Kind() {
+	case reflect.String:
+		return stringColor
+	case reflect.Bool:
+		return boolColor
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return numberColor
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if value.IsNil() {
+			return nilColor
+		}
+	}
+	return nil
 }
 
 // Output: {"ID":1,"Name":"Reds","Colors":["Crimson","Red","Ruby","Maroon"]}
@@ -96,9 +514,14 @@ func FormatInterfaceAsJson(a interface{}) string {
 	return ""
 }
 
-// Note: "go-prettyjson" colorizes output for shell output
+// Note: "go-prettyjson" output is colorized as configured by SetFormatColor()
 func FormatInterfaceAsPrettyJson(rawData interface{}) (string, error) {
 	formatter := prettyjson.NewFormatter()
+	formatter.DisabledColor = !formatColored()
+	for _, c := range []*color.Color{formatter.KeyColor, formatter.StringColor, formatter.BoolColor, formatter.NumberColor, formatter.NullColor} {
+		// i.e., regardless of whether stdout is a terminal
+		c.EnableColor()
+	}
 	bytes, err := formatter.Marshal(rawData)
 	if err != nil {
 		return fmt.Sprintf("unable to marshal data of type (%T)", rawData), err
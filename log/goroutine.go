@@ -0,0 +1,240 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Each goroutine carries its own logger context: its ID, the call tree it
+// traced (using Enter() and Exit()) and the fields set by SetContextField().
+// A context is only ever accessed by its own goroutine; so, other than to
+// look it up (which does not lock), no synchronization is needed.
+//
+// Note: a context is released once its goroutine exited all functions it
+// entered and cleared its fields. Contexts of goroutines that end (e.g.,
+// return early or panic) before then are released by a sweep of all
+// goroutines, scheduled (CONTEXTS_SWEEP_INTERVAL apart) while any context is
+// kept; until then, they are kept (but never used, as the runtime does not
+// reuse goroutine IDs). To pass fields explicitly (instead of per
+// goroutine), use With() and NewContext().
+type goroutineContext struct {
+	id      int64
+	entered []enteredFunction // functions entered (and not yet exited), outermost first
+	fields  []NamedValue      // output with every record (in the order set)
+	stored  bool              // whether the context is (currently) kept by goroutineContexts
+}
+
+// Contexts are only kept while they hold state (i.e., entered functions or fields)
+type goroutineContexts struct {
+	contexts sync.Map     // of *goroutineContext by goroutine ID
+	stored   atomic.Int64 // the number of (kept) contexts
+	sweeping atomic.Bool  // whether a sweep (of contexts of ended goroutines) is scheduled
+	mutex    sync.Mutex   // serializes sweeps
+}
+
+// Sweeps (which briefly stop all goroutines) are run (at most) this often
+const CONTEXTS_SWEEP_INTERVAL = time.Minute
+
+// An empty context (of an unknown goroutine); never modified
+var unknownContext = &goroutineContext{}
+
+// The calling goroutine's context if needed, i.e., if required (e.g., to
+// indent) or any goroutine's context is kept (as it may be the caller's);
+// otherwise, an (empty) unknownContext
+// Note: looking up the calling goroutine (see goroutineID()) is costly
+func (contexts *goroutineContexts) lookup(required bool) *goroutineContext {
+	if !required && contexts.stored.Load() == 0 {
+		return unknownContext
+	}
+	return contexts.current()
+}
+
+// The calling goroutine's context (which, if new, is not yet kept)
+func (contexts *goroutineContexts) current() *goroutineContext {
+	id := goroutineID()
+	if context, ok := contexts.contexts.Load(id); ok {
+		return context.(*goroutineContext)
+	}
+	return &goroutineContext{id: id}
+}
+
+// Keep (or release) the context as it gained (or lost) all state
+func (contexts *goroutineContexts) update(context *goroutineContext) {
+	empty := len(context.entered) == 0 && len(context.fields) == 0
+	switch {
+	case empty && context.stored:
+		contexts.contexts.Delete(context.id)
+		context.stored = false
+		contexts.stored.Add(-1)
+	case !empty && !context.stored:
+		contexts.contexts.Store(context.id, context)
+		context.stored = true
+		contexts.stored.Add(1)
+		contexts.scheduleSweep()
+	}
+}
+
+// Sweep (once the interval passed) unless a sweep is already scheduled
+func (contexts *goroutineContexts) scheduleSweep() {
+	if contexts.sweeping.CompareAndSwap(false, true) {
+		time.AfterFunc(CONTEXTS_SWEEP_INTERVAL, func() {
+			contexts.sweep()
+			// Note: contexts kept (by update()) while this sweep was
+			// scheduled did not schedule another; so, check (again)
+			contexts.sweeping.Store(false)
+			if contexts.stored.Load() > 0 {
+				contexts.scheduleSweep()
+			}
+		})
+	}
+}
+
+// Release the contexts of goroutines that ended (without releasing them)
+func (contexts *goroutineContexts) sweep() {
+	contexts.mutex.Lock()
+	defer contexts.mutex.Unlock()
+
+	// Note: contexts (are stored by their goroutines, so) are collected before
+	// the goroutines are; any that is not live (then) has ended
+	stored := map[int64]*goroutineContext{}
+	contexts.contexts.Range(func(id interface{}, context interface{}) bool {
+		stored[id.(int64)] = context.(*goroutineContext)
+		return true
+	})
+	live := liveGoroutineIDs()
+	for id, context := range stored {
+		if !live[id] && contexts.contexts.CompareAndDelete(id, context) {
+			context.stored = false
+			contexts.stored.Add(-1)
+		}
+	}
+}
+
+// The number of goroutines with a (kept) context
+func (contexts *goroutineContexts) count() (count int) {
+	contexts.contexts.Range(func(interface{}, interface{}) bool {
+		count++
+		return true
+	})
+	return
+}
+
+// A function that called Enter() and the stack depth it was called at
+type enteredFunction struct {
+	depth    int
+	function string // e.g., "cmd.readInput"
+}
+
+// The nesting level of a log record written by a caller (function) at the
+// given stack depth. Functions that returned without calling Exit() are
+// detected (and removed) as they are no longer on the stack at their depth.
+func (context *goroutineContext) level(tag string, config *loggerConfig, depth int, function string) int {
+	entered := context.entered
+	// remove functions that returned (or, for Enter(), that were not exited)
+	for len(entered) > 0 {
+		top := entered[len(entered)-1]
+		if top.depth < depth || top.depth == depth && top.function == function && tag != config.tagEnter {
+			break
+		}
+		entered = entered[:len(entered)-1]
+	}
+
+	level := len(entered)
+	switch tag {
+	case config.tagEnter:
+		entered = append(entered, enteredFunction{depth: depth, function: function})
+	case config.tagExit:
+		// Note: Exit() may be deferred, i.e., called by a function literal
+		// (e.g., "cmd.readInput.func1") of the function that entered
+		if top := level - 1; top >= 0 && (entered[top].depth == depth ||
+			entered[top].depth == depth-1 && strings.HasPrefix(function, entered[top].function+".")) {
+			entered = entered[:top]
+			level--
+		}
+	}
+	context.entered = entered
+	return level
+}
+
+// Set (or replace) the named field
+func (context *goroutineContext) setField(name string, value interface{}) {
+	for i := range context.fields {
+		if context.fields[i].Name == name {
+			context.fields[i].Value = value
+			return
+		}
+	}
+	context.fields = append(context.fields, NamedValue{Name: name, Value: value})
+}
+
+// The number of (logical, i.e., including inlined) frames on the caller's stack
+// skip is relative to the caller of stackDepth()
+func stackDepth(skip int) int {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+	for n == len(pcs) {
+		pcs = make([]uintptr, 2*len(pcs))
+		n = runtime.Callers(skip+2, pcs)
+	}
+
+	depth := 0
+	frames := runtime.CallersFrames(pcs[:n])
+	for more := true; more; depth++ {
+		_, more = frames.Next()
+	}
+	return depth
+}
+
+// The IDs of all (current) goroutines
+// Note: the stacks of all goroutines are (briefly) collected to do so
+func liveGoroutineIDs() map[int64]bool {
+	buffer := make([]byte, 64*1024)
+	n := runtime.Stack(buffer, true)
+	for n == len(buffer) {
+		buffer = make([]byte, 2*len(buffer))
+		n = runtime.Stack(buffer, true)
+	}
+
+	ids := map[int64]bool{}
+	for _, line := range bytes.Split(buffer[:n], []byte("\n")) {
+		if fields := bytes.Fields(line); len(fields) > 2 && string(fields[0]) == "goroutine" {
+			if id, err := strconv.ParseInt(string(fields[1]), 10, 64); err == nil {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// e.g., "goroutine 18 [running]:" (the only means to identify a goroutine)
+func goroutineID() int64 {
+	var buffer [64]byte
+	fields := bytes.Fields(buffer[:runtime.Stack(buffer[:], false)])
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseInt(string(fields[1]), 10, 64)
+	return id
+}
@@ -21,12 +21,14 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/fatih/color"
 )
 
 type Level int
@@ -45,38 +47,75 @@ const (
 	DEBUG                // 4 - Also, output internal logic and data (timestamps included)
 )
 
-// TODO: Allow colorization to be a configurable option.
-// on (default): for human-readable targets (e.g., console);
-// off: for (remote) logging targets (file, network) stream
-// See colors here: https://en.wikipedia.org/wiki/ANSI_escape_code#Colors
+// Level names are colorized (when output) as configured by SetColor()
 var LevelNames = map[Level]string{
-	DEBUG:   color.GreenString("DEBUG"),
-	TRACE:   color.CyanString("TRACE"),
-	INFO:    color.WhiteString("INFO"),
-	WARNING: color.HiYellowString("WARN"),
-	ERROR:   color.HiRedString("ERROR"),
+	DEBUG:   "DEBUG",
+	TRACE:   "TRACE",
+	INFO:    "INFO",
+	WARNING: "WARN",
+	ERROR:   "ERROR",
+}
+
+func (level Level) String() string {
+	if name, ok := LevelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(level))
 }
 
 var DEFAULT_LEVEL = INFO
 
+// By default, all levels are output to stderr (leaving stdout for command output)
+var DEFAULT_OUTPUT io.Writer = os.Stderr
+
+// A MiniLogger is safe for concurrent use (by multiple goroutines)
+// Its configuration is replaced (as a whole) by each change; so, logging
+// (i.e., the hot path) reads it without locking. Writes are serialized
+// per sink (writer) and each goroutine carries its own context.
 type MiniLogger struct {
-	logLevel      Level
-	indentEnabled bool
-	indentSpaces  uint
-	indentCounter uint
-	tagEnter      string
-	tagExit       string
+	core   *loggerCore  // shared by the logger and its children (see With())
+	fields []NamedValue // output with every record (of this logger)
+}
+
+type loggerCore struct {
+	mutex    sync.Mutex   // serializes configuration changes
+	config   atomic.Value // (current) *loggerConfig
+	sinks    *sinkRegistry
+	contexts *goroutineContexts // to indent nested Enter() and Exit() calls and to output fields
+}
+
+// Note: a loggerConfig is never modified once in use (see configure())
+type loggerConfig struct {
+	level          Level
+	indentEnabled  bool
+	indentSpaces   uint
+	maxValueLength int // of (formatted) Enter() and Exit() values; 0 is unlimited
+	tagEnter       string
+	tagExit        string
+	outputs        map[Level][]*sink // the writers (sinks) each level is output to
+	format         string            // i.e., FORMAT_TEXT or FORMAT_JSON
+	color          string            // i.e., COLOR_AUTO, COLOR_ON or COLOR_OFF
+	handler        slog.Handler      // if set, records are forwarded to it (instead of the outputs)
 }
 
 func NewDefaultLogger() *MiniLogger {
-	return &MiniLogger{
-		logLevel:      DEFAULT_LEVEL,
-		indentEnabled: false,
-		indentSpaces:  2,
-		indentCounter: 0,
-		tagEnter:      "ENTER",
-		tagExit:       "EXIT",
-	}
+	newLogger := &MiniLogger{core: &loggerCore{
+		sinks:    newSinkRegistry(),
+		contexts: &goroutineContexts{},
+	}}
+	newLogger.core.config.Store(&loggerConfig{
+		level:          DEFAULT_LEVEL,
+		indentEnabled:  false,
+		indentSpaces:   2,
+		maxValueLength: DEFAULT_MAX_VALUE_LENGTH,
+		tagEnter:       "ENTER",
+		tagExit:        "EXIT",
+		outputs:        map[Level][]*sink{},
+		format:         FORMAT_TEXT,
+		color:          COLOR_AUTO,
+	})
+	newLogger.SetOutput(DEFAULT_OUTPUT)
+	return newLogger
 }
 
 func NewLogger(level Level) *MiniLogger {
@@ -85,158 +124,362 @@ func NewLogger(level Level) *MiniLogger {
 	return newLogger
 }
 
+// The current configuration (which must not be modified)
+func (log *MiniLogger) settings() *loggerConfig {
+	return log.core.config.Load().(*loggerConfig)
+}
+
+// Apply a change to a copy of the configuration, which then replaces it
+func (log *MiniLogger) configure(change func(config *loggerConfig)) {
+	log.core.mutex.Lock()
+	defer log.core.mutex.Unlock()
+
+	config := *log.settings()
+	config.outputs = make(map[Level][]*sink, len(config.outputs))
+	for level, sinks := range log.settings().outputs {
+		config.outputs[level] = sinks
+	}
+	change(&config)
+	log.core.config.Store(&config)
+}
+
 func (log *MiniLogger) SetLevel(level Level) {
-	log.logLevel = level
+	log.configure(func(config *loggerConfig) {
+		config.level = level
+	})
 }
 
 func (log *MiniLogger) GetLevel() Level {
-	return log.logLevel
+	return log.settings().level
 }
 
 func (log *MiniLogger) GetLevelName() string {
-	return LevelNames[log.logLevel]
+	return LevelNames[log.GetLevel()]
+}
+
+// SetFormat selects how log output is encoded (i.e., FORMAT_TEXT or FORMAT_JSON)
+func (log *MiniLogger) SetFormat(format string) error {
+	switch format {
+	case FORMAT_TEXT, FORMAT_JSON:
+		log.configure(func(config *loggerConfig) {
+			config.format = format
+		})
+		return nil
+	}
+	return fmt.Errorf("unsupported log format: `%s` (expected one of: %s)", format, strings.Join(Formats, ", "))
+}
+
+func (log *MiniLogger) GetFormat() string {
+	return log.settings().format
+}
+
+// SetColor sets whether (text) output is colorized; in COLOR_AUTO mode,
+// this is decided for each writer (see UseColor)
+func (log *MiniLogger) SetColor(mode string) error {
+	if err := ValidateColorMode(mode); err != nil {
+		return err
+	}
+	log.configure(func(config *loggerConfig) {
+		config.color = mode
+	})
+	return nil
+}
+
+func (log *MiniLogger) GetColor() string {
+	return log.settings().color
+}
+
+// SetOutput outputs all levels to (only) the given writers
+func (log *MiniLogger) SetOutput(writers ...io.Writer) {
+	sinks := log.wrap(writers)
+	log.configure(func(config *loggerConfig) {
+		for level := range LevelNames {
+			config.outputs[level] = sinks
+		}
+	})
+}
+
+// SetLevelOutput outputs a single level to (only) the given writers;
+// for example, to route ERROR to stderr and everything else to a file
+func (log *MiniLogger) SetLevelOutput(level Level, writers ...io.Writer) {
+	sinks := log.wrap(writers)
+	log.configure(func(config *loggerConfig) {
+		config.outputs[level] = sinks
+	})
+}
+
+func (log *MiniLogger) wrap(writers []io.Writer) []*sink {
+	sinks := make([]*sink, len(writers))
+	for i, writer := range writers {
+		sinks[i] = log.core.sinks.sink(writer)
+	}
+	return sinks
+}
+
+// AddOutput also outputs the given levels (or, if none, all levels) to the writer
+func (log *MiniLogger) AddOutput(writer io.Writer, levels ...Level) {
+	if len(levels) == 0 {
+		for level := range LevelNames {
+			levels = append(levels, level)
+		}
+	}
+	added := log.core.sinks.sink(writer)
+	log.configure(func(config *loggerConfig) {
+		for _, level := range levels {
+			sinks := config.outputs[level]
+			config.outputs[level] = append(sinks[:len(sinks):len(sinks)], added)
+		}
+	})
+}
+
+// GetOutput returns a writer that writes to all of the level's writers
+// Note: as the logger's own writes, its writes are serialized per writer
+func (log *MiniLogger) GetOutput(level Level) io.Writer {
+	sinks := log.settings().outputs[level]
+	writers := make([]io.Writer, len(sinks))
+	for i, sink := range sinks {
+		writers[i] = sink
+	}
+	return io.MultiWriter(writers...)
 }
 
+// The (unwrapped) writers of the level
+func (log *MiniLogger) writers(level Level) []io.Writer {
+	sinks := log.settings().outputs[level]
+	writers := make([]io.Writer, len(sinks))
+	for i, sink := range sinks {
+		writers[i] = sink.writer
+	}
+	return writers
+}
+
+// Traced (Enter and Exit) values longer than this are cut short (e.g., file contents)
+const DEFAULT_MAX_VALUE_LENGTH = 128
+
+// SetMaxValueLength sets the length (in characters) beyond which traced
+// values are cut short; 0 (or less) shows values in full
+func (log *MiniLogger) SetMaxValueLength(length int) {
+	log.configure(func(config *loggerConfig) {
+		config.maxValueLength = length
+	})
+}
+
+func (log *MiniLogger) GetMaxValueLength() int {
+	return log.settings().maxValueLength
+}
+
+const MAX_INDENT_SPACES = 8
+
+// EnableIndent indents (text) output by the (per-goroutine) nesting level
+// of Enter() and Exit() calls, i.e., to show the call tree
+func (log *MiniLogger) EnableIndent(enabled bool) {
+	log.configure(func(config *loggerConfig) {
+		config.indentEnabled = enabled
+	})
+}
+
+func (log *MiniLogger) IsIndentEnabled() bool {
+	return log.settings().indentEnabled
+}
+
+// SetIndentSpaces sets the spaces per nesting level (at most MAX_INDENT_SPACES)
 func (log *MiniLogger) SetIndentSpaces(spaces uint) {
 	// Put some sensible limit on spaces
-	if spaces > 8 {
-		log.indentSpaces = spaces
+	if spaces <= MAX_INDENT_SPACES {
+		log.configure(func(config *loggerConfig) {
+			config.indentSpaces = spaces
+		})
 	}
 }
 
-func (log MiniLogger) Trace(value interface{}) {
+func (log *MiniLogger) GetIndentSpaces() uint {
+	return log.settings().indentSpaces
+}
+
+// SetContextField sets a field that is output with every record logged
+// by the calling goroutine (only), e.g., SetContextField("file", filename)
+func (log *MiniLogger) SetContextField(name string, value interface{}) {
+	context := log.core.contexts.current()
+	context.setField(name, value)
+	log.core.contexts.update(context)
+}
+
+// ClearContextFields removes all fields set by the calling goroutine
+func (log *MiniLogger) ClearContextFields() {
+	context := log.core.contexts.current()
+	context.fields = nil
+	log.core.contexts.update(context)
+}
+
+func (log *MiniLogger) Trace(value interface{}) {
 	log.dumpInterface(TRACE, "", value, STACK_SKIP)
 }
 
-func (log MiniLogger) Debug(value interface{}) {
+func (log *MiniLogger) Debug(value interface{}) {
 	log.dumpInterface(DEBUG, "", value, STACK_SKIP)
 }
 
-func (log MiniLogger) Info(value interface{}) {
+func (log *MiniLogger) Info(value interface{}) {
 	log.dumpInterface(INFO, "", value, STACK_SKIP)
 }
 
-func (log MiniLogger) Warning(value interface{}) {
+func (log *MiniLogger) Warning(value interface{}) {
 	log.dumpInterface(WARNING, "", value, STACK_SKIP)
 }
 
-// TODO: use fmt.fError ins some manner and/or os.Stderr
-func (log MiniLogger) Error(value interface{}) {
+func (log *MiniLogger) Error(value interface{}) {
 	log.dumpInterface(ERROR, "", value, STACK_SKIP)
 }
 
 // Specialized function entry/exit trace
-// TODO: make variadic and dump args
-func (log MiniLogger) Enter() {
-	// TODO: make variadic and dump args
-	log.dumpInterface(TRACE, log.tagEnter, nil, STACK_SKIP)
+// entry and print arguments (typed); use Arg() to also print parameter names
+func (log *MiniLogger) Enter(args ...interface{}) {
+	config := log.settings()
+	log.dumpInterface(TRACE, config.tagEnter, callValuesOf(args, config), STACK_SKIP)
 }
 
 // exit and print returned values (typed)
-func (log MiniLogger) Exit(values ...interface{}) {
-
-	sb := bytes.NewBufferString(log.tagExit)
-	if len(values) > 0 {
-		sb.WriteByte('(')
-		for index, value := range values {
-			// TODO: if type is `error`, highlight/colorize (bright red)
-			sb.WriteString(fmt.Sprintf("(%T):%+v", value, value))
-			if (index + 1) < len(values) {
-				sb.WriteString(", ")
-			}
+func (log *MiniLogger) Exit(values ...interface{}) {
+	config := log.settings()
+	log.dumpInterface(TRACE, config.tagExit, callValuesOf(values, config), STACK_SKIP)
+}
 
-		}
-		sb.WriteByte(')')
-	}
-	log.dumpInterface(TRACE, sb.String(), nil, STACK_SKIP)
-}
-
-// Note: currently, "dump" methods output directly to stdout (stderr)
-// compose log output using a bytebuffer for performance
-func (log MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, skip int) {
-
-	if lvl <= log.logLevel {
-		// retrieve all the info we might need
-		pc, fn, line, ok := runtime.Caller(skip)
-
-		// TODO: create a logging package that can indent based upon stack size
-		// Note: the "Callers()" method will not append() so allocate a large array
-		// var mystack []uintptr = make([]uintptr, 10)
-		// stacksize := runtime.Callers(0, mystack)
-		//fmt.Printf("stacksize=%v\n", stacksize)
-
-		// TODO: Provide means to order component output;
-		// for example, to add Timestamp component first (on each line) before Level
-		if ok {
-			// Setup "string builder" and initialize with log-level prefix
-			sb := bytes.NewBufferString(fmt.Sprintf("[%s] ", LevelNames[lvl]))
-
-			// Append UTC timestamp if TRACE (or DEBUG) enabled
-			if lvl == TRACE || lvl == DEBUG {
-				// UTC time shows fractions of a second
-				// TODO: add setting to show milli or micro seconds supported by "time" package
-				tmp := time.Now().UTC().String()
-				// create a (left) slice of the timestamp omitting the " +0000 UTC" portion
-				//ts = fmt.Sprintf("[%s] ", tmp[:strings.Index(tmp, "+")-1])
-				sb.WriteString(fmt.Sprintf("[%s] ", tmp[:strings.Index(tmp, "+")-1]))
-			}
-
-			// Append basic filename, line number, function name
-			basicFile := fn[strings.LastIndex(fn, "/")+1:]
-			function := runtime.FuncForPC(pc)
+func callValuesOf(values []interface{}, config *loggerConfig) interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	return callValues{values: values, maxLength: config.maxValueLength}
+}
+
+// compose log output (a record) and encode it in the logger's format
+func (log *MiniLogger) dumpInterface(lvl Level, tag string, value interface{}, skip int) {
+	if config := log.settings(); lvl <= config.level {
+		log.write(config, log.newRecord(config, lvl, tag, value, skip))
+	}
+}
+
+// skip is relative to the caller of newRecord()
+func (log *MiniLogger) newRecord(config *loggerConfig, lvl Level, tag string, value interface{}, skip int) logRecord {
+	// retrieve all the info we might need
+	pc, fn, line, ok := runtime.Caller(skip + 1)
+
+	context := log.core.contexts.lookup(config.indentEnabled)
+	record := logRecord{
+		level:     lvl,
+		time:      time.Now().UTC(),
+		line:      line,
+		goroutine: context.id,
+		tag:       tag,
+		value:     value,
+		fields:    mergeFields(log.fields, context.fields),
+	}
+
+	// Basic filename, line number, function name
+	// Note: the logger never exits the process; if the call stack
+	// cannot be retrieved, placeholders are output instead
+	record.file, record.function = "???", "???"
+	if ok {
+		record.pc = pc
+		record.file = fn[strings.LastIndex(fn, "/")+1:]
+		if function := runtime.FuncForPC(pc); function != nil {
 			// TODO: add logger flag to show full module paths (not just module.function)
-			basicModFnName := function.Name()[strings.LastIndex(function.Name(), "/")+1:]
-
-			sb.WriteString(fmt.Sprintf("%s(%d) %s()", basicFile, line, basicModFnName))
-
-			// Append (optional) tag
-			if tag != "" {
-				sb.WriteString(fmt.Sprintf(": %s", tag))
-			}
-
-			// Append (optional) value
-			if value != nil {
-				sb.WriteString(fmt.Sprintf(": %+v", value))
-			}
-			// TODO: use a general output writer (set to stdout, stderr, or filestream)
-			fmt.Println(sb.String())
-		} else {
-			os.Stderr.WriteString("Error: Unable to retrieve call stack. Exiting...")
-			os.Exit(-2)
+			record.function = function.Name()[strings.LastIndex(function.Name(), "/")+1:]
+		}
+	}
+
+	// Nesting level of the (Enter) call tree
+	if config.indentEnabled {
+		record.nested, record.indent = true, strings.Repeat(" ", int(config.indentSpaces))
+		record.depth = context.level(tag, config, stackDepth(skip+1), record.function)
+		log.core.contexts.update(context)
+	}
+	return record
+}
+
+func (log *MiniLogger) write(config *loggerConfig, record logRecord) {
+	if config.handler != nil {
+		forward(config.handler, record)
+		return
+	}
+	log.writeEach(config, record.level, func(colored bool) []byte {
+		if config.format == FORMAT_JSON {
+			return encodeJSON(record)
+		}
+		return encodeText(record, colored)
+	})
+}
+
+// Write to each of the level's writers; output is encoded (at most) once
+// with and once without color
+// Note: the logger never fails; write errors (of any sink) are ignored
+func (log *MiniLogger) writeEach(config *loggerConfig, level Level, encode func(colored bool) []byte) {
+	var encoded [2][]byte
+	for _, sink := range config.outputs[level] {
+		colored := config.format != FORMAT_JSON && UseColor(config.color, sink.writer)
+		index := 0
+		if colored {
+			index = 1
 		}
+		if encoded[index] == nil {
+			encoded[index] = encode(colored)
+		}
+		sink.Write(encoded[index])
 	}
 }
 
-func (log MiniLogger) DumpString(value string) {
-	fmt.Print(value)
+// Dumped text is output as is; unless the format is JSON (or records are
+// forwarded), where it becomes the value of an INFO record (so that the
+// output remains parsable)
+func (log *MiniLogger) dump(format func(colored bool) string) {
+	config := log.settings()
+	if config.format == FORMAT_JSON || config.handler != nil {
+		log.write(config, log.newRecord(config, INFO, "", strings.TrimSuffix(format(false), "\n"), STACK_SKIP))
+		return
+	}
+	log.writeEach(config, INFO, func(colored bool) []byte {
+		return []byte(GetRedactor().RedactString(format(colored)))
+	})
 }
 
-func (log MiniLogger) DumpStruct(structName string, field interface{}) error {
+// Note: "dump" methods output (regardless of log level) to the INFO writers
+func (log *MiniLogger) DumpString(value string) {
+	log.dump(func(bool) string { return value })
+}
 
-	formattedStruct, err := FormatStruct(structName, field)
-	if err != nil {
+func (log *MiniLogger) DumpStruct(structName string, field interface{}) error {
+
+	if _, err := formatStruct(structName, field, false); err != nil {
 		return err
 	}
-	// TODO: print to output stream
-	fmt.Print(formattedStruct)
+	log.dump(func(colored bool) string {
+		formattedStruct, _ := formatStruct(structName, field, colored)
+		return formattedStruct
+	})
 	return nil
 }
 
-func (log MiniLogger) DumpArgs() {
+func (log *MiniLogger) DumpArgs() {
 	args := os.Args
 	for i, a := range args {
-		// TODO: print to output stream
-		fmt.Printf("os.Arg[%d]: `%v`\n", i, a)
+		arg := fmt.Sprintf("os.Arg[%d]: `%v`\n", i, a)
+		log.dump(func(bool) string { return arg })
 	}
 }
 
-func (log MiniLogger) DumpSeparator(sep byte, repeat int) error {
+// Note: separators are (purely) decorative; they are not output as JSON
+// (or forwarded)
+func (log *MiniLogger) DumpSeparator(sep byte, repeat int) error {
 	if repeat <= 80 {
+		config := log.settings()
+		if config.format == FORMAT_JSON || config.handler != nil {
+			return nil
+		}
 		sb := bytes.NewBufferString("")
 		for i := 0; i < repeat; i++ {
 			sb.WriteByte(sep)
 		}
-		fmt.Println(sb.String())
+		sb.WriteByte('\n')
+		log.writeEach(config, INFO, func(bool) []byte { return sb.Bytes() })
 		return nil
 	} else {
 		return errors.New("invalid repeat length (>80)")
@@ -18,18 +18,739 @@
 package log
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
 // Test constructors
 func TestNewDefaultLogger(t *testing.T) {
-    logger := NewDefaultLogger()
-    assert.NotNil(t, logger)
+	logger := NewDefaultLogger()
+	assert.NotNil(t, logger)
 }
 
 func TestNewLogger(t *testing.T) {
-    logger := NewLogger(TRACE)
-    assert.NotNil(t, logger)
+	logger := NewLogger(TRACE)
+	assert.NotNil(t, logger)
+}
+
+// Every level is output to stderr unless routed elsewhere
+func TestDefaultOutput(t *testing.T) {
+	logger := NewDefaultLogger()
+	for level := range LevelNames {
+		assert.Equal(t, []io.Writer{os.Stderr}, logger.writers(level))
+	}
+}
+
+func TestLevelOutput(t *testing.T) {
+	var errors, all bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&all)
+	logger.SetLevelOutput(ERROR, &errors)
+
+	logger.Info("info message")
+	logger.Error("error message")
+	logger.Debug("filtered message")
+	assert.Contains(t, all.String(), "info message")
+	assert.NotContains(t, all.String(), "error message")
+	assert.NotContains(t, all.String(), "filtered message")
+	assert.Contains(t, errors.String(), "error message")
+	assert.True(t, strings.HasSuffix(errors.String(), "\n"))
+
+	// added writers receive output in addition to the existing ones
+	var warnings bytes.Buffer
+	logger.AddOutput(&warnings, WARNING, ERROR)
+	logger.Error("another error")
+	assert.Contains(t, errors.String(), "another error")
+	assert.Contains(t, warnings.String(), "another error")
+	assert.NotContains(t, all.String(), "another error")
+}
+
+// Dump methods are output to the INFO writers (not stdout)
+func TestDumpOutput(t *testing.T) {
+	var info bytes.Buffer
+	logger := NewLogger(ERROR)
+	logger.SetLevelOutput(INFO, &info)
+
+	logger.DumpString("banner\n")
+	assert.NoError(t, logger.DumpSeparator('=', 6))
+	assert.NoError(t, logger.DumpStruct("Point", struct{ X, Y int }{1, 2}))
+	assert.True(t, strings.HasPrefix(info.String(), "banner\n======\n"))
+	assert.Contains(t, info.String(), "Point")
+	assert.Error(t, logger.DumpSeparator('=', 81))
+}
+
+func TestTextFormat(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+
+	logger.Exit(0, "done")
+	line := output.String()
+	assert.True(t, strings.HasPrefix(line, "["+LevelNames[TRACE]+"] ["), line)
+	assert.Contains(t, line, "log.TestTextFormat(): EXIT((int):0, (string):done)")
+}
+
+func TestJSONFormat(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	assert.Error(t, logger.SetFormat("xml"))
+	assert.Equal(t, FORMAT_JSON, logger.GetFormat())
+
+	logger.Info(map[string]interface{}{"name": "<bom>", "count": 2})
+	logger.Error(errors.New("failed"))
+	logger.Exit(1, nil)
+	logger.DumpString("banner\n")
+	assert.NoError(t, logger.DumpSeparator('=', 6))
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records = append(records, record)
+	}
+	assert.Equal(t, 4, len(records))
+
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, "log_test.go", records[0]["file"])
+	assert.Equal(t, "log.TestJSONFormat", records[0]["function"])
+	assert.NotZero(t, records[0]["line"])
+	_, err := time.Parse(time.RFC3339Nano, records[0]["time"].(string))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "<bom>", "count": 2.0}, records[0]["value"])
+	assert.Contains(t, output.String(), `"<bom>"`)
+	assert.Nil(t, records[0]["tag"])
+
+	assert.Equal(t, "ERROR", records[1]["level"])
+	assert.Equal(t, "failed", records[1]["value"])
+
+	assert.Equal(t, "EXIT", records[2]["tag"])
+	assert.Equal(t, []interface{}{1.0, nil}, records[2]["value"])
+
+	assert.Equal(t, "banner", records[3]["value"])
+	assert.Equal(t, "log.TestJSONFormat", records[3]["function"])
+}
+
+func TestUseColor(t *testing.T) {
+	var buffer bytes.Buffer
+	t.Setenv(ENV_NO_COLOR, "")
+	t.Setenv(ENV_FORCE_COLOR, "")
+	assert.False(t, UseColor(COLOR_AUTO, &buffer))
+	assert.True(t, UseColor(COLOR_ON, &buffer))
+	assert.False(t, UseColor(COLOR_OFF, os.Stderr))
+
+	t.Setenv(ENV_FORCE_COLOR, "1")
+	assert.True(t, UseColor(COLOR_AUTO, &buffer))
+	assert.False(t, UseColor(COLOR_OFF, &buffer))
+	t.Setenv(ENV_FORCE_COLOR, "0")
+	assert.False(t, UseColor(COLOR_AUTO, &buffer))
+
+	// NO_COLOR takes precedence, but not over an explicit mode
+	t.Setenv(ENV_FORCE_COLOR, "1")
+	t.Setenv(ENV_NO_COLOR, "1")
+	assert.False(t, UseColor(COLOR_AUTO, &buffer))
+	assert.True(t, UseColor(COLOR_ON, &buffer))
+}
+
+func TestColorOutput(t *testing.T) {
+	t.Setenv(ENV_NO_COLOR, "")
+	t.Setenv(ENV_FORCE_COLOR, "")
+	var plain, colored bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&plain)
+	assert.Error(t, logger.SetColor("blue"))
+	assert.Equal(t, COLOR_AUTO, logger.GetColor())
+
+	logger.Trace("message")
+	assert.True(t, strings.HasPrefix(plain.String(), "[TRACE] "), plain.String())
+
+	assert.NoError(t, logger.SetColor(COLOR_ON))
+	logger.SetOutput(&colored)
+	logger.Exit(errors.New("failed"))
+	assert.True(t, strings.HasPrefix(colored.String(), "[\x1b[36mTRACE\x1b[0m] "), colored.String())
+	assert.Contains(t, colored.String(), "\x1b[91m(*errors.errorString):failed\x1b[0m")
+
+	// JSON is never colorized
+	colored.Reset()
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	logger.Trace("message")
+	assert.NotContains(t, colored.String(), "\x1b[")
+}
+
+func TestFormatColor(t *testing.T) {
+	defer SetFormatColor(COLOR_AUTO)
+	point := struct {
+		X    int
+		Name string
+	}{1, "origin"}
+
+	assert.Error(t, SetFormatColor("blue"))
+	assert.NoError(t, SetFormatColor(COLOR_OFF))
+	formatted, err := FormatStruct("point", point)
+	assert.NoError(t, err)
+	assert.NotContains(t, formatted, "\x1b[")
+	formatted, err = FormatInterfaceAsPrettyJson(point)
+	assert.NoError(t, err)
+	assert.NotContains(t, formatted, "\x1b[")
+
+	assert.NoError(t, SetFormatColor(COLOR_ON))
+	formatted, err = FormatStruct("point", point)
+	assert.NoError(t, err)
+	assert.Contains(t, formatted, "\x1b[32morigin\x1b[0m")
+	formatted, err = FormatInterfaceAsPrettyJson(point)
+	assert.NoError(t, err)
+	assert.Contains(t, formatted, "\x1b[")
+}
+
+func traceOuter(logger *MiniLogger, returnEarly bool) {
+	logger.Enter()
+	logger.Trace("outer")
+	traceInner(logger, returnEarly)
+	traceDeferred(logger)
+	traceInner(logger, false)
+	logger.Exit()
+}
+
+func traceDeferred(logger *MiniLogger) {
+	logger.Enter()
+	defer func() { logger.Exit() }()
+	logger.Trace("deferred")
+}
+
+func traceInner(logger *MiniLogger, returnEarly bool) {
+	logger.Enter()
+	if returnEarly {
+		// i.e., without calling Exit()
+		return
+	}
+	logger.Trace("inner")
+	logger.Exit()
+}
+
+func TestIndent(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	logger.SetIndentSpaces(9)
+	assert.Equal(t, uint(2), logger.GetIndentSpaces())
+	logger.SetIndentSpaces(4)
+	logger.EnableIndent(true)
+
+	for _, returnEarly := range []bool{false, true} {
+		output.Reset()
+		traceOuter(logger, returnEarly)
+
+		var indents []int
+		for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+			// i.e., after the "[TRACE] [timestamp] " prefix
+			line = line[strings.Index(line, "] [")+3:]
+			line = line[strings.Index(line, "] ")+2:]
+			indents = append(indents, len(line)-len(strings.TrimLeft(line, " ")))
+		}
+		if returnEarly {
+			assert.Equal(t, []int{0, 4, 4, 4, 8, 4, 4, 8, 4, 0}, indents, output.String())
+		} else {
+			assert.Equal(t, []int{0, 4, 4, 8, 4, 4, 8, 4, 4, 8, 4, 0}, indents, output.String())
+		}
+	}
+
+	// every Enter() was exited (or detected as returned)
+	logger.Trace("done")
+	assert.Zero(t, logger.core.contexts.count())
+
+	output.Reset()
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	traceOuter(logger, false)
+	assert.Contains(t, output.String(), `"depth":2`)
+}
+
+func TestEnterArgs(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	logger.SetMaxValueLength(8)
+	assert.Equal(t, 8, logger.GetMaxValueLength())
+
+	data := []byte("<?xml version=\"1.0\"?>")
+	logger.Enter(Arg("format", "json"), 42, Arg("data", data), "a long string value", nil)
+	assert.Contains(t, output.String(), "ENTER(format(string):json, (int):42, "+
+		"data([]uint8):len=21 hex=3c3f786d6c2076657273696f6e3d2231..., (string):a long s..., (<nil>):<nil>)")
+
+	output.Reset()
+	logger.Enter()
+	assert.True(t, strings.HasSuffix(output.String(), "(): ENTER\n"), output.String())
+
+	output.Reset()
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	logger.Enter(Arg("format", "json"), Arg("data", data[:4]), "a long string value", []int{1, 2})
+	var record struct {
+		Tag   string
+		Value []interface{}
+	}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "ENTER", record.Tag)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "format", "value": "json"},
+		map[string]interface{}{"name": "data", "value": map[string]interface{}{"length": 4.0, "hex": "3c3f786d"}},
+		"a long s...",
+		[]interface{}{1.0, 2.0},
+	}, record.Value)
+}
+
+// Run with the race detector, i.e., "go test -race ./log"
+func TestConcurrentWrites(t *testing.T) {
+	const goroutines, lines = 16, 100
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	logger.AddOutput(&output, ERROR)
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	// the same writer (of different levels) is locked by the same mutex
+	assert.Same(t, logger.settings().outputs[INFO][0], logger.settings().outputs[ERROR][1])
+
+	var wait sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wait.Add(1)
+		go func(g int) {
+			defer wait.Done()
+			for i := 0; i < lines; i++ {
+				logger.Info(fmt.Sprintf("goroutine %d, line %d", g, i))
+			}
+		}(g)
+	}
+	// (concurrent) configuration changes
+	wait.Add(1)
+	go func() {
+		defer wait.Done()
+		for i := 0; i < lines; i++ {
+			logger.SetColor(COLOR_OFF)
+			logger.SetIndentSpaces(uint(i % MAX_INDENT_SPACES))
+			logger.SetLevel(TRACE)
+		}
+	}()
+	wait.Wait()
+
+	records := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, records, goroutines*lines)
+	for _, record := range records {
+		assert.True(t, json.Valid([]byte(record)), record)
+	}
+}
+
+func TestGoroutineContext(t *testing.T) {
+	const goroutines = 8
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	logger.EnableIndent(true)
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+
+	var wait sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wait.Add(1)
+		go func(g int) {
+			defer wait.Done()
+			logger.SetContextField("worker", g)
+			traceOuter(logger, g%2 == 0)
+			logger.ClearContextFields()
+		}(g)
+	}
+	wait.Wait()
+
+	// each goroutine's records carry its own fields and call tree
+	workers := map[int64]float64{}
+	depths := map[int64][]int{}
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		var record struct {
+			Goroutine int64
+			Depth     int
+			Fields    map[string]interface{}
+		}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		worker, seen := workers[record.Goroutine]
+		if !seen {
+			worker = record.Fields["worker"].(float64)
+			workers[record.Goroutine] = worker
+		}
+		assert.Equal(t, worker, record.Fields["worker"])
+		depths[record.Goroutine] = append(depths[record.Goroutine], record.Depth)
+	}
+	assert.Len(t, workers, goroutines)
+	for id, worker := range workers {
+		if int(worker)%2 == 0 {
+			assert.Equal(t, []int{0, 1, 1, 1, 2, 1, 1, 2, 1, 0}, depths[id])
+		} else {
+			assert.Equal(t, []int{0, 1, 1, 2, 1, 1, 2, 1, 1, 2, 1, 0}, depths[id])
+		}
+	}
+	assert.Zero(t, logger.core.contexts.count())
+
+	output.Reset()
+	assert.NoError(t, logger.SetFormat(FORMAT_TEXT))
+	logger.SetContextField("file", "bom.json")
+	logger.Info("message")
+	logger.ClearContextFields()
+	assert.True(t, strings.HasSuffix(output.String(), ": message {file=bom.json}\n"), output.String())
+}
+
+// The (costly) goroutine lookup is skipped unless indenting or any fields are set
+func TestGoroutineLookup(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+
+	goroutine := func() int64 {
+		var record struct{ Goroutine int64 }
+		assert.NoError(t, json.Unmarshal(output.Bytes(), &record))
+		output.Reset()
+		return record.Goroutine
+	}
+	logger.Info("message")
+	assert.Zero(t, goroutine())
+	assert.Same(t, unknownContext, logger.core.contexts.lookup(false))
+
+	logger.SetContextField("file", "bom.json")
+	logger.Info("message")
+	assert.Equal(t, goroutineID(), goroutine())
+	logger.ClearContextFields()
+
+	logger.EnableIndent(true)
+	logger.Info("message")
+	assert.Equal(t, goroutineID(), goroutine())
+}
+
+// Contexts of goroutines that end without exiting (or clearing their
+// fields) are released (by a sweep)
+func TestGoroutineContextLeak(t *testing.T) {
+	logger := NewLogger(TRACE)
+	logger.SetOutput(io.Discard)
+	logger.EnableIndent(true)
+
+	const goroutines = 16
+	for g := 0; g < goroutines; g++ {
+		done := make(chan bool)
+		go func() {
+			defer close(done)
+			logger.SetContextField("worker", g)
+			logger.Enter()
+		}()
+		<-done
+	}
+	assert.Equal(t, goroutines, logger.core.contexts.count())
+	assert.True(t, logger.core.contexts.sweeping.Load())
+
+	// i.e., ended goroutines are released; live ones are kept
+	release := make(chan bool)
+	entered := make(chan bool)
+	go func() {
+		logger.Enter()
+		entered <- true
+		<-release
+		logger.Exit()
+		close(entered)
+	}()
+	<-entered
+	logger.core.contexts.sweep()
+	assert.Equal(t, 1, logger.core.contexts.count())
+	assert.Equal(t, int64(1), logger.core.contexts.stored.Load())
+	close(release)
+	<-entered
+	assert.Zero(t, logger.core.contexts.count())
+	assert.Zero(t, logger.core.contexts.stored.Load())
+}
+
+func TestWithFields(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(TRACE)
+	logger.SetOutput(&output)
+
+	child := logger.WithFields(map[string]interface{}{"document": "bom.json", "command": "validate"})
+	grandchild := child.With("component", "pkg:npm/acme@1.0").With("document", "other.json")
+	assert.Empty(t, logger.GetFields())
+	assert.Equal(t, []NamedValue{{"command", "validate"}, {"document", "bom.json"}}, child.GetFields())
+
+	grandchild.Info("message")
+	assert.True(t, strings.HasSuffix(output.String(),
+		": message {command=validate, document=other.json, component=pkg:npm/acme@1.0}\n"), output.String())
+
+	// children share their parent's configuration
+	output.Reset()
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+	grandchild.SetContextField("document", nil)
+	child.Info("message")
+	grandchild.ClearContextFields()
+	var record struct{ Fields map[string]interface{} }
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, map[string]interface{}{"command": "validate", "document": nil}, record.Fields)
+
+	ctx := NewContext(context.Background(), child)
+	fromContext, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Same(t, child, fromContext)
+	_, ok = FromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestSlogLevels(t *testing.T) {
+	for level := range LevelNames {
+		assert.Equal(t, level, FromSlogLevel(SlogLevel(level)))
+	}
+	assert.Equal(t, WARNING, FromSlogLevel(slog.LevelWarn+2))
+	assert.Equal(t, DEBUG, FromSlogLevel(slog.LevelDebug-4))
+}
+
+func TestSlogHandler(t *testing.T) {
+	var output bytes.Buffer
+	logger := NewLogger(INFO)
+	logger.SetOutput(&output)
+	assert.NoError(t, logger.SetFormat(FORMAT_JSON))
+
+	slogger := slog.New(NewSlogHandler(logger.With("command", "validate")))
+	slogger.Debug("filtered message")
+	assert.Empty(t, output.String())
+
+	slogger.With("document", "bom.json").WithGroup("component").
+		Warn("message", "ref", "pkg:npm/acme@1.0", slog.Group("hash", "alg", "SHA-256"))
+	var record struct {
+		Level    string
+		File     string
+		Function string
+		Value    string
+		Fields   map[string]interface{}
+	}
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &record))
+	assert.Equal(t, "WARN", record.Level)
+	assert.Equal(t, "log_test.go", record.File)
+	assert.Equal(t, "log.TestSlogHandler", record.Function)
+	assert.Equal(t, "message", record.Value)
+	assert.Equal(t, map[string]interface{}{
+		"command":            "validate",
+		"document":           "bom.json",
+		"component.ref":      "pkg:npm/acme@1.0",
+		"component.hash.alg": "SHA-256",
+	}, record.Fields)
+}
+
+func TestHandlerLogger(t *testing.T) {
+	var output bytes.Buffer
+	handler := slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug})
+	logger := NewHandlerLogger(handler)
+	logger.SetLevel(TRACE)
+	assert.Same(t, handler, logger.GetHandler())
+
+	logger.With("document", "bom.json").Info("message")
+	logger.Enter(Arg("format", "json"))
+	logger.Debug("filtered message")
+	assert.NoError(t, logger.DumpSeparator('=', 10))
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n") {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		delete(record, "time")
+		records = append(records, record)
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "INFO", "msg": "message", "document": "bom.json"},
+		{"level": "DEBUG+2", "msg": "ENTER", "value": "(format(string):json)"},
+	}, records)
+}
+
+type formatNode struct {
+	Name     string
+	Password string `log:",redact"`
+	Internal int    `log:"-"`
+	Count    int    `log:"count"`
+	Level    Level
+	Tags     []string
+	Labels   map[string]interface{}
+	Data     []byte
+	Parent   *formatNode
+	Children []*formatNode
+	Created  time.Time
+}
+
+func TestFormatStruct(t *testing.T) {
+	root := &formatNode{
+		Name:     "root",
+		Password: "secret",
+		Internal: 42,
+		Count:    2,
+		Level:    TRACE,
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]interface{}{"zeta": 1, "alpha": true},
+		Data:     []byte("<?xml"),
+		Created:  time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	root.Children = []*formatNode{{Name: "child", Parent: root}}
+
+	formatted, err := formatStruct("root", root, false)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(formatted, "  root (*log.formatNode) = {\n"), formatted)
+	for _, line := range []string{
+		"\t        Name (string)   : root\n",
+		"\t    Password (string)   : [REDACTED]\n",
+		"\t       count (int)      : 2\n",
+		"\t       Level (log.Level) : TRACE\n",
+		"\t        Tags ([]string) : [\n\t\t         [0] (string)   : a\n\t\t         [1] (string)   : b\n\t]\n",
+		"\t      Labels (map[string]interface {}) : {\n\t\t     [alpha] (bool)     : true\n\t\t      [zeta] (int)      : 1\n\t}\n",
+		"\t        Data ([]uint8)  : len=5 hex=3c3f786d6c\n",
+		"\t      Parent (*log.formatNode) : <nil>\n",
+		"\t\t\t      Parent (*log.formatNode) : <cycle>\n",
+		"\t     Created (time.Time) : 2022-06-01 00:00:00 +0000 UTC\n",
+	} {
+		assert.Contains(t, formatted, line)
+	}
+	assert.NotContains(t, formatted, "secret")
+	assert.NotContains(t, formatted, "Internal")
+
+	defer SetFormatDepth(DEFAULT_FORMAT_DEPTH)
+	SetFormatDepth(1)
+	assert.Equal(t, 1, GetFormatDepth())
+	formatted, err = formatStruct("root", root, false)
+	assert.NoError(t, err)
+	assert.Contains(t, formatted, "\t    Children ([]*log.formatNode) : [\n\t\t         [0] (*log.formatNode) : {...}\n\t]\n")
+
+	_, err = formatStruct("root", (*formatNode)(nil), false)
+	assert.Error(t, err)
+	_, err = formatStruct("root", 42, false)
+	assert.Error(t, err)
+}
+
+// i.e., (even) with unlimited depth, values that contain themselves
+func TestFormatCycles(t *testing.T) {
+	defer SetFormatDepth(DEFAULT_FORMAT_DEPTH)
+	SetFormatDepth(0)
+
+	items := []interface{}{"first", nil}
+	items[1] = items
+	formatted, err := formatStruct("list", struct{ Items []interface{} }{items}, false)
+	assert.NoError(t, err)
+	assert.Contains(t, formatted, "\t\t         [1] ([]interface {}) : <cycle>\n")
+	formatted, err = FormatMapAs("list", map[string]interface{}{"items": items}, FORMAT_JSON)
+	assert.NoError(t, err)
+	assert.Contains(t, formatted, `"<cycle>"`)
+
+	// a struct and (a pointer to) its first field share their address
+	type inner struct{ Name string }
+	type outer struct {
+		Inner inner
+		First *inner
+	}
+	value := &outer{Inner: inner{"inner"}}
+	value.First = &value.Inner
+	formatted, err = formatStruct("outer", value, false)
+	assert.NoError(t, err)
+	assert.NotContains(t, formatted, "<cycle>")
+	assert.Equal(t, 2, strings.Count(formatted, ": inner\n"), formatted)
+
+	// i.e., the depth may be set while (other goroutines are) formatting
+	var wait sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wait.Add(1)
+		go func(g int) {
+			defer wait.Done()
+			SetFormatDepth(g)
+			FormatStruct("outer", value)
+		}(g)
+	}
+	wait.Wait()
+}
+
+func TestFormatMap(t *testing.T) {
+	defer SetFormatColor(COLOR_AUTO)
+	assert.NoError(t, SetFormatColor(COLOR_OFF))
+	field := map[string]interface{}{
+		"name":     "bom.json",
+		"errors":   2,
+		"versions": []string{"1.4", "1.5"},
+		"counts":   map[int]string{10: "ten", 2: "two"},
+		"level":    TRACE,
+		"empty":    nil,
+	}
+
+	// i.e., the same for every run (regardless of map iteration order)
+	formatted, err := FormatMap("document", field)
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, _ := FormatMap("document", field)
+		assert.Equal(t, formatted, again)
+	}
+	assert.Equal(t, "  document (map[string]interface {}) = {\n"+
+		"\t    [counts] (map[int]string) : {\n"+
+		"\t\t         [2] (string)   : two\n"+
+		"\t\t        [10] (string)   : ten\n"+
+		"\t}\n"+
+		"\t     [empty] (interface {}) : <nil>\n"+
+		"\t    [errors] (int)      : 2\n"+
+		"\t     [level] (log.Level) : TRACE\n"+
+		"\t      [name] (string)   : bom.json\n"+
+		"\t  [versions] ([]string) : [\n"+
+		"\t\t         [0] (string)   : 1.4\n"+
+		"\t\t         [1] (string)   : 1.5\n"+
+		"\t]\n"+
+		"  }\n", formatted)
+
+	formatted, err = FormatMapAs("document", field, FORMAT_JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "counts": {
+    "2": "two",
+    "10": "ten"
+  },
+  "empty": null,
+  "errors": 2,
+  "level": "TRACE",
+  "name": "bom.json",
+  "versions": [
+    "1.4",
+    "1.5"
+  ]
+}
+`, formatted)
+
+	formatted, err = FormatMapAs("document", &field, FORMAT_YAML)
+	assert.NoError(t, err)
+	assert.Equal(t, `counts:
+    "2": two
+    "10": ten
+empty: null
+errors: 2
+level: TRACE
+name: bom.json
+versions:
+    - "1.4"
+    - "1.5"
+`, formatted)
+
+	// struct fields keep their order (and are redacted)
+	formatted, err = FormatMapAs("document", map[string]interface{}{"point": struct {
+		Y, X  int
+		Token string
+	}{1, 2, "secret"}}, FORMAT_JSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"point\": {\n    \"Y\": 1,\n    \"X\": 2,\n    \"Token\": \"[REDACTED]\"\n  }\n}\n", formatted)
+
+	formatted, err = FormatMap("empty", map[string]int{})
+	assert.NoError(t, err)
+	assert.Equal(t, "  empty (map[string]int) = {\n\t<empty>\n  }\n", formatted)
+
+	_, err = FormatMap("slice", []string{"a"})
+	assert.Error(t, err)
+	_, err = FormatMapAs("document", field, "xml")
+	assert.Error(t, err)
 }
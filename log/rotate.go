@@ -0,0 +1,238 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// A log file (sink) that, once it reaches its size limit, is rotated, i.e.,
+// renamed to "<path>.1" (shifting older backups to "<path>.2", and so on);
+// at most maxBackups old files are kept and, if compressed, gzipped
+// (e.g., "<path>.1.gz") in the background, i.e., without blocking writes.
+// It is safe for concurrent use; rotation happens (only) between writes. If the file cannot be rotated, output continues
+// in the current file (i.e., beyond its size limit) and the error is
+// reported (once) to stderr.
+type RotatingFile struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64 // in bytes; 0 (or less) never rotates
+	maxBackups int
+	compress   bool
+	file       *os.File // nil if closed or (after a failed rotation) not reopened (yet)
+	size       int64    // of the (current) file
+	closed     bool
+	failing    bool       // i.e., an error was reported (and not resolved since)
+	warnings   io.Writer  // where (rotation) errors are reported
+	compressed chan error // the result of compressing the newest backup (nil, if none is pending)
+}
+
+// Rotated log file size limits (e.g., of `--log-max-size`) are given in megabytes
+const MEGABYTE = 1024 * 1024
+
+// NewRotatingFile opens (or creates) the log file; output is appended
+func NewRotatingFile(path string, maxSize int64, maxBackups int, compress bool) (*RotatingFile, error) {
+	if maxBackups < 0 {
+		return nil, fmt.Errorf("invalid number of log backups: %d", maxBackups)
+	}
+	rotating := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		compress:   compress,
+		warnings:   os.Stderr,
+	}
+	if err := rotating.open(); err != nil {
+		return nil, err
+	}
+	return rotating, nil
+}
+
+func (rotating *RotatingFile) open() error {
+	file, err := os.OpenFile(rotating.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rotating.file, rotating.size = file, info.Size()
+	return nil
+}
+
+// Write rotates the file first if the data would exceed its size limit;
+// so, (unless larger than the limit itself) records are never split
+func (rotating *RotatingFile) Write(data []byte) (int, error) {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.closed {
+		return 0, os.ErrClosed
+	}
+	if rotating.file != nil && rotating.maxSize > 0 && rotating.size > 0 && rotating.size+int64(len(data)) > rotating.maxSize {
+		rotating.report(rotating.rotate())
+	}
+	if rotating.file == nil {
+		// i.e., neither the new nor the current file could be (re)opened
+		err := rotating.open()
+		rotating.report(err)
+		if err != nil {
+			return 0, err
+		}
+	}
+	written, err := rotating.file.Write(data)
+	rotating.size += int64(written)
+	return written, err
+}
+
+// Close closes the (current) file; later writes fail
+func (rotating *RotatingFile) Close() error {
+	rotating.mutex.Lock()
+	defer rotating.mutex.Unlock()
+
+	if rotating.closed {
+		return nil
+	}
+	rotating.closed = true
+	err := rotating.awaitCompression()
+	if rotating.file == nil {
+		return err
+	}
+	if closeErr := rotating.file.Close(); closeErr != nil {
+		err = closeErr
+	}
+	rotating.file = nil
+	return err
+}
+
+// Errors are reported once, i.e., until resolved (by a rotation or reopen)
+// Note: called with the mutex locked
+func (rotating *RotatingFile) report(err error) {
+	if err == nil {
+		rotating.failing = false
+	} else if !rotating.failing {
+		rotating.failing = true
+		fmt.Fprintf(rotating.warnings, "[%s] unable to rotate log file `%s`: %v\n", WARNING, rotating.path, err)
+	}
+}
+
+// The name of the n-th (1 is the newest) backup
+func (rotating *RotatingFile) backup(n int) string {
+	name := fmt.Sprintf("%s.%d", rotating.path, n)
+	if rotating.compress {
+		name += ".gz"
+	}
+	return name
+}
+
+// The file is closed (before it is renamed, as required on Windows) and
+// then (re)opened, i.e., as the new file or, if it could not be rotated,
+// as the current file (again)
+// Note: called with the mutex locked
+func (rotating *RotatingFile) rotate() error {
+	// i.e., the newest backup is compressed before backups are shifted
+	compressErr := rotating.awaitCompression()
+	err := rotating.file.Close()
+	rotating.file = nil
+	if err == nil {
+		err = rotating.shift()
+	}
+	if openErr := rotating.open(); err == nil {
+		err = openErr
+	}
+	if err == nil {
+		err = compressErr
+	}
+	return err
+}
+
+// Wait for the (pending) compression of the newest backup, if any
+// Note: called with the mutex locked
+func (rotating *RotatingFile) awaitCompression() error {
+	if rotating.compressed == nil {
+		return nil
+	}
+	err := <-rotating.compressed
+	rotating.compressed = nil
+	return err
+}
+
+// Move the (closed) file to the first backup, shifting the others
+func (rotating *RotatingFile) shift() error {
+	if rotating.maxBackups == 0 {
+		if err := os.Remove(rotating.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	// shift the backups (the oldest is overwritten, i.e., dropped)
+	for n := rotating.maxBackups - 1; n > 0; n-- {
+		if err := os.Rename(rotating.backup(n), rotating.backup(n+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	uncompressed := fmt.Sprintf("%s.%d", rotating.path, 1)
+	if err := os.Rename(rotating.path, uncompressed); err != nil {
+		return err
+	}
+	if rotating.compress {
+		// Note: compressed without holding the mutex (i.e., while writing
+		// continues); if it fails, the backup is kept uncompressed (until
+		// the next rotation) and the error is reported (then)
+		compressed := make(chan error, 1)
+		go func(target string) {
+			compressed <- gzipFile(uncompressed, target)
+		}(rotating.backup(1))
+		rotating.compressed = compressed
+	}
+	return nil
+}
+
+// Compress the file (which is then removed) into target
+func gzipFile(path string, target string) (err error) {
+	input, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	output, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(output)
+	if _, err = io.Copy(writer, input); err == nil {
+		err = writer.Close()
+	}
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return err
+	}
+	return os.Remove(path)
+}
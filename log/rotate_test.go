@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Read (and, if gzipped, decompress) the file's lines
+func readLines(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+		reader = gzipReader
+	}
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestRotatingFile(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "trace.log")
+		rotating, err := NewRotatingFile(path, 256, 2, compress)
+		assert.NoError(t, err)
+
+		// e.g., "[INFO] rotate_test.go(...) ...: goroutine 1, line 2"
+		logger := NewLogger(INFO)
+		logger.SetOutput(rotating)
+		var wait sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wait.Add(1)
+			go func(g int) {
+				defer wait.Done()
+				for i := 0; i < 20; i++ {
+					logger.Info(fmt.Sprintf("goroutine %d, line %d", g, i))
+				}
+			}(g)
+		}
+		wait.Wait()
+		assert.NoError(t, rotating.Close())
+
+		matches, _ := filepath.Glob(path + "*")
+		assert.ElementsMatch(t, []string{path, rotating.backup(1), rotating.backup(2)}, matches)
+		for _, file := range matches {
+			lines := readLines(t, file)
+			assert.NotEmpty(t, lines, file)
+			// records are never split (across files)
+			for _, line := range lines {
+				assert.Regexp(t, `^\[INFO\] .*: goroutine \d+, line \d+$`, line)
+			}
+			if !compress || file == path {
+				info, err := os.Stat(file)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, info.Size(), int64(256), file)
+			}
+		}
+
+		_, err = rotating.Write([]byte("closed\n"))
+		assert.ErrorIs(t, err, os.ErrClosed)
+	}
+}
+
+func TestRotatingFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.log")
+	assert.NoError(t, os.WriteFile(path, []byte("first\n"), 0644))
+	rotating, err := NewRotatingFile(path, 0, 0, false)
+	assert.NoError(t, err)
+	_, err = rotating.Write([]byte("second\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, rotating.Close())
+	assert.Equal(t, []string{"first", "second"}, readLines(t, path))
+
+	_, err = NewRotatingFile(path, 0, -1, false)
+	assert.Error(t, err)
+}
+
+// Output continues (in the current file) if it cannot be rotated or reopened
+func TestRotatingFileFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.log")
+	rotating, err := NewRotatingFile(path, 8, 1, false)
+	assert.NoError(t, err)
+	var warnings strings.Builder
+	rotating.warnings = &warnings
+
+	// i.e., the backup cannot be replaced (by a rename)
+	assert.NoError(t, os.MkdirAll(filepath.Join(rotating.backup(1), "busy"), 0755))
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err = rotating.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, readLines(t, path))
+	assert.Equal(t, 1, strings.Count(warnings.String(), "unable to rotate log file"), warnings.String())
+
+	// once resolved, the file is rotated (and errors are reported again)
+	assert.NoError(t, os.RemoveAll(rotating.backup(1)))
+	_, err = rotating.Write([]byte("fourth\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fourth"}, readLines(t, path))
+	assert.Equal(t, []string{"first", "second", "third"}, readLines(t, rotating.backup(1)))
+
+	// i.e., as if neither the new nor the current file could be reopened
+	warnings.Reset()
+	rotating.file.Close()
+	rotating.file = nil
+	assert.NoError(t, os.Rename(path, path+".moved"))
+	assert.NoError(t, os.Mkdir(path, 0755))
+	_, err = rotating.Write([]byte("lost\n"))
+	assert.Error(t, err)
+	assert.Contains(t, warnings.String(), "unable to rotate log file")
+	assert.NoError(t, os.Remove(path))
+	_, err = rotating.Write([]byte("fifth\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, rotating.Close())
+	assert.Equal(t, []string{"fifth"}, readLines(t, path))
+}
+
+// Backups are compressed in the background; if that fails, the backup is kept
+// (uncompressed, until the next rotation) and the error is reported then
+func TestRotatingFileCompressionFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.log")
+	rotating, err := NewRotatingFile(path, 8, 1, true)
+	assert.NoError(t, err)
+	var warnings strings.Builder
+	rotating.warnings = &warnings
+
+	// i.e., the compressed backup cannot be created
+	assert.NoError(t, os.MkdirAll(filepath.Join(rotating.backup(1), "busy"), 0755))
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err = rotating.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.Empty(t, warnings.String())
+	_, err = rotating.Write([]byte("third\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(warnings.String(), "unable to rotate log file"), warnings.String())
+	assert.Error(t, rotating.Close())
+	assert.Equal(t, []string{"third"}, readLines(t, path))
+	assert.Equal(t, []string{"second"}, readLines(t, path+".1"))
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// A writer (sink) that log records are output to; writes are serialized so
+// that lines (written by concurrent goroutines) are never interleaved
+type sink struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (sink *sink) Write(data []byte) (int, error) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return sink.writer.Write(data)
+}
+
+// Each writer is wrapped by (exactly) one sink, regardless of the levels
+// (or how often) it is output to; so, it is locked by only one mutex
+type sinkRegistry struct {
+	mutex sync.Mutex
+	sinks map[io.Writer]*sink
+}
+
+func newSinkRegistry() *sinkRegistry {
+	return &sinkRegistry{sinks: map[io.Writer]*sink{}}
+}
+
+func (registry *sinkRegistry) sink(writer io.Writer) *sink {
+	if wrapped, ok := writer.(*sink); ok {
+		return wrapped
+	}
+	// Note: writers that cannot be map keys (e.g., of slice types) are not shared
+	if writer == nil || !reflect.TypeOf(writer).Comparable() {
+		return &sink{writer: writer}
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	wrapped, ok := registry.sinks[writer]
+	if !ok {
+		wrapped = &sink{writer: writer}
+		registry.sinks[writer] = wrapped
+	}
+	return wrapped
+}
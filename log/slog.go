@@ -0,0 +1,218 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// TRACE is less verbose than DEBUG (see Level); so, as a slog level, it
+// falls between slog.LevelDebug and slog.LevelInfo
+const SLOG_LEVEL_TRACE = slog.Level(-2)
+
+// SlogLevel maps a level to its slog level
+func SlogLevel(level Level) slog.Level {
+	switch {
+	case level <= ERROR:
+		return slog.LevelError
+	case level == WARNING:
+		return slog.LevelWarn
+	case level == INFO:
+		return slog.LevelInfo
+	case level == TRACE:
+		return SLOG_LEVEL_TRACE
+	}
+	return slog.LevelDebug
+}
+
+// FromSlogLevel maps a slog level to the (closest, more severe) level,
+// e.g., slog.LevelWarn+2 to WARNING
+func FromSlogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ERROR
+	case level >= slog.LevelWarn:
+		return WARNING
+	case level >= slog.LevelInfo:
+		return INFO
+	case level >= SLOG_LEVEL_TRACE:
+		return TRACE
+	}
+	return DEBUG
+}
+
+// A slog.Handler that outputs (slog) records as the logger's own, i.e., per
+// its level, format and outputs; attributes become (record) fields, named
+// by their (dot-separated) groups, e.g., "request.id"
+// Note: the logger must not (also) forward to this handler (see SetHandler())
+type SlogHandler struct {
+	logger *MiniLogger
+	attrs  []NamedValue // added by WithAttrs()
+	group  string       // prefix of attribute names, e.g., "request."
+}
+
+// NewSlogHandler returns a handler for the logger, e.g., to pass to slog.New()
+func NewSlogHandler(logger *MiniLogger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+func (handler *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return FromSlogLevel(level) <= handler.logger.GetLevel()
+}
+
+// Handle outputs the record; if the context carries a (child) logger of the
+// handler's logger (see NewContext()), its fields are added
+// Note: slog records are not indented (i.e., part of the Enter() call tree)
+func (handler *SlogHandler) Handle(ctx context.Context, slogRecord slog.Record) error {
+	logger := handler.logger
+	if carried, ok := FromContext(ctx); ok && carried.core == logger.core {
+		logger = carried
+	}
+	config := logger.settings()
+	level := FromSlogLevel(slogRecord.Level)
+	if level > config.level {
+		return nil
+	}
+
+	var attrs []NamedValue
+	slogRecord.Attrs(func(attr slog.Attr) bool {
+		attrs = appendAttr(attrs, handler.group, attr)
+		return true
+	})
+	context := logger.core.contexts.lookup(false)
+	record := logRecord{
+		level:     level,
+		time:      slogRecord.Time.UTC(),
+		goroutine: context.id,
+		fields:    mergeFields(mergeFields(mergeFields(logger.fields, context.fields), handler.attrs), attrs),
+	}
+	if slogRecord.Time.IsZero() {
+		record.time = time.Now().UTC()
+	}
+	if slogRecord.Message != "" {
+		record.value = slogRecord.Message
+	}
+
+	record.file, record.function = "???", "???"
+	if slogRecord.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{slogRecord.PC}).Next()
+		record.pc, record.line = slogRecord.PC, frame.Line
+		record.file = frame.File[strings.LastIndex(frame.File, "/")+1:]
+		record.function = frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+	}
+	logger.write(config, record)
+	return nil
+}
+
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var added []NamedValue
+	for _, attr := range attrs {
+		added = appendAttr(added, handler.group, attr)
+	}
+	child := *handler
+	child.attrs = mergeFields(handler.attrs, added)
+	return &child
+}
+
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return handler
+	}
+	child := *handler
+	child.group = handler.group + name + "."
+	return &child
+}
+
+// Groups are flattened, e.g., into "request.id"; empty attributes are ignored
+func appendAttr(fields []NamedValue, prefix string, attr slog.Attr) []NamedValue {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			fields = appendAttr(fields, prefix, member)
+		}
+		return fields
+	}
+	return append(fields, NamedValue{Name: prefix + attr.Key, Value: attr.Value.Any()})
+}
+
+// NewHandlerLogger returns a logger that forwards its records to the handler
+// (e.g., of another library) instead of writing them to its own outputs
+func NewHandlerLogger(handler slog.Handler) *MiniLogger {
+	newLogger := NewDefaultLogger()
+	newLogger.SetHandler(handler)
+	return newLogger
+}
+
+// SetHandler forwards (all) records to the handler; nil restores output to
+// the logger's own writers
+// Note: records are (still) filtered by the logger's level
+func (log *MiniLogger) SetHandler(handler slog.Handler) {
+	log.configure(func(config *loggerConfig) {
+		config.handler = handler
+	})
+}
+
+func (log *MiniLogger) GetHandler() slog.Handler {
+	return log.settings().handler
+}
+
+// The message is the tag (e.g., "ENTER"), if any, or the value; fields
+// (and, when tracked, the depth) become attributes
+// Note: (as for the logger's own outputs) secrets are redacted
+// Note: the logger never fails; handler errors are ignored
+func forward(handler slog.Handler, record logRecord) {
+	ctx := context.Background()
+	level := SlogLevel(record.level)
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	redactor := GetRedactor()
+	message, value := record.tag, record.value
+	if message == "" && value != nil {
+		message, value = redactor.redactText(fmt.Sprintf("%+v", value)), nil
+	}
+	slogRecord := slog.NewRecord(record.time, level, message, record.pc)
+	if value != nil {
+		slogRecord.AddAttrs(slog.String("value", redactor.redactText(fmt.Sprintf("%+v", value))))
+	}
+	if record.nested {
+		slogRecord.AddAttrs(slog.Int("depth", record.depth))
+	}
+	for _, field := range record.fields {
+		if redactor.IsSecretName(field.Name) {
+			slogRecord.AddAttrs(slog.String(field.Name, REDACTED))
+		} else if text, isString := field.Value.(string); isString {
+			slogRecord.AddAttrs(slog.String(field.Name, redactor.RedactString(text)))
+		} else {
+			slogRecord.AddAttrs(slog.Any(field.Name, field.Value))
+		}
+	}
+	handler.Handle(ctx, slogRecord)
+}
@@ -0,0 +1,432 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table formats (besides FORMAT_TEXT and FORMAT_JSON)
+const (
+	FORMAT_MARKDOWN = "markdown"
+	FORMAT_CSV      = "csv"
+	FORMAT_TSV      = "tsv"
+)
+
+var TableFormats = []string{FORMAT_TEXT, FORMAT_MARKDOWN, FORMAT_CSV, FORMAT_TSV, FORMAT_JSON}
+
+// Columns of text tables are (at least) this wide, even if cut short to fit
+const MIN_COLUMN_WIDTH = 6
+
+// The (optional) terminal width, e.g., where it cannot be detected
+const ENV_COLUMNS = "COLUMNS"
+
+type TableOptions struct {
+	Columns  []string // names of the columns (in order) to output; if none, all
+	SortBy   []string // names of the columns to sort rows by; e.g., "-version" sorts descending
+	MaxWidth int      // of text tables (see TerminalWidth()); 0 (or less) is unlimited
+}
+
+// FormatTable formats the rows (a slice of structs, or of pointers to structs)
+// as a table, whose columns are the (exported) struct fields; as for
+// FormatStruct(), `log` tags rename, hide or redact columns
+func FormatTable(rows interface{}, format string, options TableOptions) (string, error) {
+	var buffer bytes.Buffer
+	if err := WriteTable(&buffer, rows, format, options); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// WriteTable writes the rows as a table (see FormatTable()) to the writer
+func WriteTable(writer io.Writer, rows interface{}, format string, options TableOptions) error {
+	table, err := newTable(rows, options)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FORMAT_TEXT:
+		return table.writeText(writer, options.MaxWidth)
+	case FORMAT_MARKDOWN:
+		return table.writeMarkdown(writer)
+	case FORMAT_CSV, FORMAT_TSV:
+		csvWriter := csv.NewWriter(writer)
+		if format == FORMAT_TSV {
+			csvWriter.Comma = '\t'
+		}
+		csvWriter.Write(table.header())
+		for _, row := range table.rows {
+			csvWriter.Write(table.cells(row))
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	case FORMAT_JSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(table.objects())
+	}
+	return fmt.Errorf("unsupported table format: `%s` (expected one of: %s)", format, strings.Join(TableFormats, ", "))
+}
+
+// TerminalWidth returns the width (in columns) of the terminal the writer
+// outputs to, if any (or as set by the COLUMNS environment variable); otherwise, 0
+func TerminalWidth(writer io.Writer) int {
+	if columns, err := strconv.Atoi(os.Getenv(ENV_COLUMNS)); err == nil && columns > 0 {
+		return columns
+	}
+	if file, ok := writer.(*os.File); ok && isTerminal(file) {
+		return terminalWidth(file)
+	}
+	return 0
+}
+
+type tableColumn struct {
+	name     string
+	index    int // of the struct field
+	redacted bool
+}
+
+type table struct {
+	columns   []tableColumn
+	rows      []reflect.Value // (non-nil) structs
+	formatter *structFormatter
+}
+
+func newTable(rows interface{}, options TableOptions) (*table, error) {
+	value := reflect.ValueOf(rows)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return nil, fmt.Errorf("invalid `Table`; expected a slice of structs; actual Type: (%v)", reflect.TypeOf(rows))
+	}
+	rowType := value.Type().Elem()
+	if rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid `Table`; expected a slice of structs; actual Type: (%v)", reflect.TypeOf(rows))
+	}
+
+	table := &table{formatter: newStructFormatter(false)}
+	var all []tableColumn
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		name, hidden, redacted := parseStructTag(field)
+		if field.IsExported() && !hidden {
+			redacted = redacted || table.formatter.redactor.IsSecretName(field.Name) || table.formatter.redactor.IsSecretName(name)
+			all = append(all, tableColumn{name: name, index: i, redacted: redacted})
+		}
+	}
+	table.columns = all
+	if len(options.Columns) > 0 {
+		table.columns = nil
+		for _, name := range options.Columns {
+			column, err := findColumn(all, name)
+			if err != nil {
+				return nil, err
+			}
+			table.columns = append(table.columns, column)
+		}
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		row := value.Index(i)
+		if row.Kind() == reflect.Ptr {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		table.rows = append(table.rows, row)
+	}
+	return table, table.sort(all, options.SortBy)
+}
+
+// Column names are matched regardless of case
+func findColumn(columns []tableColumn, name string) (tableColumn, error) {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		if strings.EqualFold(column.name, name) {
+			return column, nil
+		}
+		names[i] = column.name
+	}
+	return tableColumn{}, fmt.Errorf("unknown column: `%s` (expected one of: %s)", name, strings.Join(names, ", "))
+}
+
+// Sort (stably) by each of the columns in turn; numbers by value, all else as text
+// Note: rows may be sorted by columns that are not output
+func (table *table) sort(columns []tableColumn, sortBy []string) error {
+	type sortKey struct {
+		column     tableColumn
+		descending bool
+	}
+	var keys []sortKey
+	for _, name := range sortBy {
+		descending := strings.HasPrefix(name, "-")
+		column, err := findColumn(columns, strings.TrimPrefix(name, "-"))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, sortKey{column, descending})
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(table.rows, func(i, j int) bool {
+		for _, key := range keys {
+			order := table.compare(table.rows[i].Field(key.column.index), table.rows[j].Field(key.column.index))
+			if order != 0 {
+				return (order < 0) != key.descending
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+func (table *table) compare(a reflect.Value, b reflect.Value) int {
+	for a.Kind() == reflect.Ptr || a.Kind() == reflect.Interface {
+		if a.IsNil() || b.IsNil() || a.Elem().Kind() != b.Elem().Kind() {
+			break
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(a.Float(), b.Float())
+	case reflect.Bool:
+		return compareOrdered(strconv.FormatBool(a.Bool()), strconv.FormatBool(b.Bool()))
+	}
+	return compareOrdered(table.format(a), table.format(b))
+}
+
+func compareOrdered[T int64 | uint64 | float64 | string](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (table *table) header() []string {
+	header := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		header[i] = column.name
+	}
+	return header
+}
+
+func (table *table) cells(row reflect.Value) []string {
+	cells := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		if column.redacted {
+			cells[i] = REDACTED
+		} else {
+			cells[i] = table.format(row.Field(column.index))
+		}
+	}
+	return cells
+}
+
+// Cells are (single-line) text; e.g., slices are listed as "a, b"
+func (table *table) format(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return ""
+		}
+		return table.format(value.Elem())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		elements := make([]string, value.Len())
+		for i := range elements {
+			elements[i] = table.format(value.Index(i))
+		}
+		return strings.Join(elements, ", ")
+	case reflect.Struct:
+		// e.g., time.Time
+		if stringer, ok := asStringer(value); ok && !hasExportedFields(value.Type()) {
+			return table.formatter.redactor.RedactString(stringer.String())
+		}
+		fallthrough
+	case reflect.Map:
+		// as JSON (rather than Go syntax), e.g., {"name":"value"}
+		data, _ := marshalJSON(table.formatter.tree(value, 0))
+		return string(data)
+	}
+	text := table.formatter.format(value, 0)
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(text)
+}
+
+// Each row as a JSON object (of typed values)
+func (table *table) objects() []orderedMap {
+	objects := make([]orderedMap, len(table.rows))
+	for i, row := range table.rows {
+		object := make(orderedMap, len(table.columns))
+		for j, column := range table.columns {
+			object[j] = NamedValue{Name: column.name}
+			if column.redacted {
+				object[j].Value = REDACTED
+			} else {
+				object[j].Value = table.formatter.tree(row.Field(column.index), 1)
+			}
+		}
+		objects[i] = object
+	}
+	return objects
+}
+
+// e.g.,
+//
+//	FORMAT     VERSION
+//	CycloneDX  1.6
+//
+// the widest columns are cut short (to fit maxWidth, if > 0)
+func (table *table) writeText(writer io.Writer, maxWidth int) error {
+	header := table.header()
+	for i := range header {
+		header[i] = strings.ToUpper(header[i])
+	}
+	lines := [][]string{header}
+	for _, row := range table.rows {
+		lines = append(lines, table.cells(row))
+	}
+
+	widths := make([]int, len(table.columns))
+	for _, line := range lines {
+		for i, cell := range line {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	fitWidths(widths, maxWidth)
+
+	var sb strings.Builder
+	for _, line := range lines {
+		for i, cell := range line {
+			cell = fitWidth(cell, widths[i])
+			if i < len(line)-1 {
+				cell += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2)
+			}
+			sb.WriteString(cell)
+		}
+		sb.WriteByte('\n')
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// Narrow the widest columns (but not below MIN_COLUMN_WIDTH) until all
+// (separated by 2 spaces) fit
+func fitWidths(widths []int, maxWidth int) {
+	if maxWidth <= 0 {
+		return
+	}
+	total := 2 * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+	for total > maxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= MIN_COLUMN_WIDTH {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// e.g., "http://cyclonedx.org/sch..."
+func fitWidth(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width-3]) + "..."
+}
+
+// e.g.,
+//
+//	| Format    | Version |
+//	| --------- | ------- |
+//	| CycloneDX | 1.6     |
+func (table *table) writeMarkdown(writer io.Writer) error {
+	escape := strings.NewReplacer("|", `\|`)
+	lines := [][]string{table.header()}
+	for _, row := range table.rows {
+		lines = append(lines, table.cells(row))
+	}
+
+	widths := make([]int, len(table.columns))
+	for _, line := range lines {
+		for i := range line {
+			line[i] = escape.Replace(line[i])
+			if width := utf8.RuneCountInString(line[i]); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		// Note: Markdown requires (at least) 3 dashes
+		if width < 3 {
+			widths[i] = 3
+		}
+		separator[i] = strings.Repeat("-", widths[i])
+	}
+	lines = append(lines[:1], append([][]string{separator}, lines[1:]...)...)
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteByte('|')
+		for i, cell := range line {
+			sb.WriteString(" " + cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + " |")
+		}
+		sb.WriteByte('\n')
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
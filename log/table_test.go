@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tableRow struct {
+	Format  string
+	Version float64
+	Url     string `log:"URL"`
+	Token   string // i.e., always redacted (by name)
+	Ignored string `log:"-"`
+}
+
+var tableRows = []*tableRow{
+	{"SPDX", 2.3, "https://spdx.org/schema.json", "abc123", "x"},
+	{"CycloneDX", 1.5, "http://cyclonedx.org/schema/bom-1.5.schema.json", "", "y"},
+	nil,
+	{"CycloneDX", 1.10, "http://cyclonedx.org/schema/bom-1.10.schema.json", "", "z"},
+}
+
+func TestFormatTable(t *testing.T) {
+	tests := []struct {
+		format   string
+		options  TableOptions
+		expected string
+	}{
+		{FORMAT_TEXT, TableOptions{Columns: []string{"format", "version"}},
+			"FORMAT     VERSION\n" +
+				"SPDX       2.3\n" +
+				"CycloneDX  1.5\n" +
+				"CycloneDX  1.1\n"},
+		// i.e., numerically (not as text) and stable
+		{FORMAT_TEXT, TableOptions{Columns: []string{"Format", "Version"}, SortBy: []string{"format", "-version"}},
+			"FORMAT     VERSION\n" +
+				"CycloneDX  1.5\n" +
+				"CycloneDX  1.1\n" +
+				"SPDX       2.3\n"},
+		{FORMAT_TEXT, TableOptions{Columns: []string{"url", "token"}, MaxWidth: 30},
+			"URL                 TOKEN\n" +
+				"https://spdx.or...  [REDACTED]\n" +
+				"http://cycloned...  [REDACTED]\n" +
+				"http://cycloned...  [REDACTED]\n"},
+		{FORMAT_MARKDOWN, TableOptions{Columns: []string{"format", "version"}},
+			"| Format    | Version |\n" +
+				"| --------- | ------- |\n" +
+				"| SPDX      | 2.3     |\n" +
+				"| CycloneDX | 1.5     |\n" +
+				"| CycloneDX | 1.1     |\n"},
+		{FORMAT_CSV, TableOptions{SortBy: []string{"version"}},
+			"Format,Version,URL,Token\n" +
+				"CycloneDX,1.1,http://cyclonedx.org/schema/bom-1.10.schema.json,[REDACTED]\n" +
+				"CycloneDX,1.5,http://cyclonedx.org/schema/bom-1.5.schema.json,[REDACTED]\n" +
+				"SPDX,2.3,https://spdx.org/schema.json,[REDACTED]\n"},
+		{FORMAT_TSV, TableOptions{Columns: []string{"version", "format"}},
+			"Version\tFormat\n" +
+				"2.3\tSPDX\n" +
+				"1.5\tCycloneDX\n" +
+				"1.1\tCycloneDX\n"},
+		{FORMAT_JSON, TableOptions{Columns: []string{"format", "token"}, SortBy: []string{"-format"}},
+			`[
+  {
+    "Format": "SPDX",
+    "Token": "[REDACTED]"
+  },
+  {
+    "Format": "CycloneDX",
+    "Token": "[REDACTED]"
+  },
+  {
+    "Format": "CycloneDX",
+    "Token": "[REDACTED]"
+  }
+]
+`},
+	}
+	for _, test := range tests {
+		formatted, err := FormatTable(tableRows, test.format, test.options)
+		assert.NoError(t, err, test.format)
+		assert.Equal(t, test.expected, formatted, test.format)
+	}
+
+	_, err := FormatTable(tableRows, "xml", TableOptions{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unsupported table format")
+	}
+	_, err = FormatTable(tableRows, FORMAT_TEXT, TableOptions{Columns: []string{"ignored"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown column: `ignored`")
+	}
+	_, err = FormatTable(tableRows, FORMAT_TEXT, TableOptions{SortBy: []string{"-bogus"}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown column: `bogus`")
+	}
+	_, err = FormatTable(tableRow{}, FORMAT_TEXT, TableOptions{})
+	assert.Error(t, err)
+}
@@ -68,7 +68,12 @@ func main() {
 	Logger.Enter()
 	printWelcome()
 
-	// Use Cobra convention and execute top-level command
-	cmd.Execute()
-	Logger.Exit()
+	// Use Cobra convention and execute top-level command;
+	// map any error to its (documented) process exit code
+	err := cmd.Execute()
+	exitCode := cmd.ExitCode(err)
+	Logger.Exit(exitCode)
+	if exitCode != cmd.EXIT_SUCCESS {
+		os.Exit(exitCode)
+	}
 }
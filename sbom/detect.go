@@ -0,0 +1,355 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/mrutkows/go-skeleton/schema"
+)
+
+// Document formats (i.e., format family and serialization)
+const (
+	FORMAT_CYCLONEDX_JSON = "cyclonedx-json"
+	FORMAT_CYCLONEDX_XML  = "cyclonedx-xml"
+	FORMAT_SPDX_JSON      = "spdx-json"
+	FORMAT_SPDX_TV        = "spdx-tv"
+	FORMAT_SPDX_YAML      = "spdx-yaml"
+	FORMAT_SPDX_RDF       = "spdx-rdf"
+)
+
+var Formats = []string{
+	FORMAT_CYCLONEDX_JSON,
+	FORMAT_CYCLONEDX_XML,
+	FORMAT_SPDX_JSON,
+	FORMAT_SPDX_TV,
+	FORMAT_SPDX_YAML,
+	FORMAT_SPDX_RDF,
+}
+
+// Confidence values reported by detectors
+const (
+	CONFIDENCE_NONE   float64 = 0.0
+	CONFIDENCE_LOW    float64 = 0.25 // shape matches (e.g., JSON object), but no format markers
+	CONFIDENCE_MEDIUM float64 = 0.5  // format markers found, but no declared version
+	CONFIDENCE_HIGH   float64 = 1.0  // format and version explicitly declared
+)
+
+// Only the first bytes of the input are inspected by (line-oriented) detectors
+const DETECT_HEADER_SIZE = 64 * 1024
+
+var (
+	ErrUnknownFormat   = errors.New("unable to detect SBOM format")
+	ErrAmbiguousFormat = errors.New("ambiguous SBOM format")
+)
+
+// The result of inspecting an input document
+type Detection struct {
+	Format     string  // one of the FORMAT_xxx values
+	Version    string  // spec. version (e.g., "1.5", "2.3"); empty if not declared
+	Confidence float64 // 0.0 (none) through 1.0 (high)
+}
+
+func (detection Detection) String() string {
+	return fmt.Sprintf("%s (version: `%s`, confidence: %.2f)", detection.Format, detection.Version, detection.Confidence)
+}
+
+// Family returns the schema format family (i.e., "CycloneDX" or "SPDX")
+func (detection Detection) Family() string {
+	return FormatFamily(detection.Format)
+}
+
+func FormatFamily(format string) string {
+	if strings.HasPrefix(format, "cyclonedx") {
+		return schema.FORMAT_CYCLONEDX
+	}
+	if strings.HasPrefix(format, "spdx") {
+		return schema.FORMAT_SPDX
+	}
+	return ""
+}
+
+// A Detector inspects (the head of) an input document and reports
+// the format it recognizes along with its confidence in that result.
+type Detector interface {
+	// The formats this detector is able to recognize
+	Formats() []string
+	Detect(buffer []byte) Detection
+}
+
+var detectors = []Detector{
+	jsonDetector{},
+	xmlDetector{},
+	tagValueDetector{},
+	yamlDetector{},
+}
+
+// RegisterDetector adds a custom detector; it takes part in all later calls to Detect
+func RegisterDetector(detector Detector) {
+	detectors = append(detectors, detector)
+}
+
+// Detect runs every registered detector and returns the most confident
+// result. If no detector recognizes the input, or the two best results
+// disagree with equal confidence, an error is returned.
+func Detect(buffer []byte) (Detection, error) {
+	var best, runnerUp Detection
+	for _, detector := range detectors {
+		detection := detector.Detect(buffer)
+		if detection.Confidence > best.Confidence {
+			runnerUp, best = best, detection
+		} else if detection.Confidence > runnerUp.Confidence {
+			runnerUp = detection
+		}
+	}
+
+	if best.Confidence < CONFIDENCE_MEDIUM {
+		return best, ErrUnknownFormat
+	}
+	if runnerUp.Confidence == best.Confidence && runnerUp.Format != best.Format {
+		return best, fmt.Errorf("%w: %s or %s", ErrAmbiguousFormat, best.Format, runnerUp.Format)
+	}
+	return best, nil
+}
+
+// DetectAs is used when the caller explicitly names the format (e.g., `--input-format`);
+// detection is still used to determine the declared version, if any.
+func DetectAs(buffer []byte, format string) (Detection, error) {
+	for _, detector := range detectors {
+		for _, supported := range detector.Formats() {
+			if supported == format {
+				detection := detector.Detect(buffer)
+				if detection.Format != format {
+					detection.Version = ""
+				}
+				return Detection{Format: format, Version: detection.Version, Confidence: CONFIDENCE_HIGH}, nil
+			}
+		}
+	}
+	return Detection{}, fmt.Errorf("unsupported input format: `%s` (expected one of: %s)", format, strings.Join(Formats, ", "))
+}
+
+// Skip any leading whitespace and UTF-8 byte order mark (BOM)
+func trimHeader(buffer []byte) []byte {
+	buffer = bytes.TrimPrefix(buffer, []byte("\xef\xbb\xbf"))
+	return bytes.TrimLeft(buffer, " \t\r\n")
+}
+
+// Limit line-oriented detectors to the head of the document
+func header(buffer []byte) []byte {
+	buffer = trimHeader(buffer)
+	if len(buffer) > DETECT_HEADER_SIZE {
+		return buffer[:DETECT_HEADER_SIZE]
+	}
+	return buffer
+}
+
+// JSON: CycloneDX declares "bomFormat" (and "specVersion"), SPDX declares "spdxVersion"
+type jsonDetector struct{}
+
+func (detector jsonDetector) Formats() []string {
+	return []string{FORMAT_CYCLONEDX_JSON, FORMAT_SPDX_JSON}
+}
+
+func (detector jsonDetector) Detect(buffer []byte) (detection Detection) {
+	buffer = trimHeader(buffer)
+	if len(buffer) == 0 || buffer[0] != '{' {
+		return
+	}
+
+	// i.e., stop reading (e.g., large "components" arrays) once the format
+	// and its version are known
+	identified := func(keys map[string]string) bool {
+		return keys["bomFormat"] != "" && keys["specVersion"] != "" || keys["spdxVersion"] != ""
+	}
+	keys := topLevelJsonStrings(bytes.NewReader(buffer), identified, "bomFormat", "specVersion", "spdxVersion", "$schema")
+	switch {
+	case keys["bomFormat"] == schema.FORMAT_CYCLONEDX:
+		detection = Detection{FORMAT_CYCLONEDX_JSON, keys["specVersion"], CONFIDENCE_MEDIUM}
+	case keys["spdxVersion"] != "":
+		detection = Detection{FORMAT_SPDX_JSON, strings.TrimPrefix(keys["spdxVersion"], schema.SPDX_VERSION_PREFIX), CONFIDENCE_MEDIUM}
+	case strings.Contains(keys["$schema"], "cyclonedx"):
+		detection = Detection{FORMAT_CYCLONEDX_JSON, "", CONFIDENCE_MEDIUM}
+	default:
+		return Detection{Confidence: CONFIDENCE_LOW}
+	}
+	if detection.Version != "" {
+		detection.Confidence = CONFIDENCE_HIGH
+	}
+	return
+}
+
+// Stream the top-level object and collect the named string properties
+// until done (or all are found); nested values are scanned, but never
+// unmarshalled.
+func topLevelJsonStrings(reader io.Reader, done func(map[string]string) bool, names ...string) map[string]string {
+	values := make(map[string]string)
+	decoder := json.NewDecoder(reader)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return values
+	}
+
+	for !done(values) && decoder.More() && len(values) < len(names) {
+		token, err := decoder.Token()
+		if err != nil {
+			return values
+		}
+		key, _ := token.(string)
+
+		var raw json.RawMessage
+		if err = decoder.Decode(&raw); err != nil {
+			return values
+		}
+		for _, name := range names {
+			if key == name {
+				var value string
+				if json.Unmarshal(raw, &value) == nil {
+					values[key] = value
+				}
+			}
+		}
+	}
+	return values
+}
+
+// XML: CycloneDX uses a versioned default namespace on the root `<bom>` element;
+// SPDX RDF/XML uses an `<rdf:RDF>` root with `<spdx:specVersion>` in the document.
+type xmlDetector struct{}
+
+const (
+	CYCLONEDX_XML_NAMESPACE_PREFIX = "http://cyclonedx.org/schema/bom/"
+	SPDX_RDF_NAMESPACE             = "http://spdx.org/rdf/terms#"
+	RDF_NAMESPACE                  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+)
+
+func (detector xmlDetector) Formats() []string {
+	return []string{FORMAT_CYCLONEDX_XML, FORMAT_SPDX_RDF}
+}
+
+// Note: RDF/XML may declare its version anywhere; the entire buffer is streamed
+func (detector xmlDetector) Detect(buffer []byte) (detection Detection) {
+	buffer = trimHeader(buffer)
+	if len(buffer) == 0 || buffer[0] != '<' {
+		return
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(buffer))
+	var root *xml.StartElement
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if root == nil {
+			root = &element
+			if element.Name.Local == "bom" && strings.HasPrefix(element.Name.Space, CYCLONEDX_XML_NAMESPACE_PREFIX) {
+				version := strings.TrimPrefix(element.Name.Space, CYCLONEDX_XML_NAMESPACE_PREFIX)
+				return Detection{FORMAT_CYCLONEDX_XML, version, CONFIDENCE_HIGH}
+			}
+			if element.Name.Local == "RDF" && element.Name.Space == RDF_NAMESPACE {
+				detection = Detection{Format: FORMAT_SPDX_RDF, Confidence: CONFIDENCE_LOW}
+			}
+			continue
+		}
+
+		if element.Name.Space == SPDX_RDF_NAMESPACE {
+			if detection.Format == FORMAT_SPDX_RDF && detection.Confidence < CONFIDENCE_MEDIUM {
+				detection.Confidence = CONFIDENCE_MEDIUM
+			}
+			if element.Name.Local == "specVersion" {
+				var version string
+				if decoder.DecodeElement(&version, &element) == nil && detection.Format == FORMAT_SPDX_RDF {
+					detection.Version = strings.TrimPrefix(strings.TrimSpace(version), schema.SPDX_VERSION_PREFIX)
+					detection.Confidence = CONFIDENCE_HIGH
+					return
+				}
+			}
+		}
+	}
+
+	if root != nil && detection.Format == "" {
+		// well-formed XML, but not a known SBOM root element
+		detection.Confidence = CONFIDENCE_LOW
+	}
+	return
+}
+
+// SPDX tag-value: "SPDXVersion: SPDX-2.2" (tags are case-sensitive)
+type tagValueDetector struct{}
+
+var tagValueVersionRegex = regexp.MustCompile(`^SPDXVersion:\s*SPDX-(\S+)\s*$`)
+var tagValueTagRegex = regexp.MustCompile(`^(DataLicense|SPDXID|DocumentName|DocumentNamespace|Creator|Created|PackageName):\s*`)
+
+func (detector tagValueDetector) Formats() []string {
+	return []string{FORMAT_SPDX_TV}
+}
+
+func (detector tagValueDetector) Detect(buffer []byte) (detection Detection) {
+	scanner := bufio.NewScanner(bytes.NewReader(header(buffer)))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), DETECT_HEADER_SIZE)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if matches := tagValueVersionRegex.FindStringSubmatch(line); matches != nil {
+			return Detection{FORMAT_SPDX_TV, matches[1], CONFIDENCE_HIGH}
+		}
+		if tagValueTagRegex.MatchString(line) {
+			detection = Detection{Format: FORMAT_SPDX_TV, Confidence: CONFIDENCE_MEDIUM}
+		}
+	}
+	return
+}
+
+// SPDX YAML: a top-level (i.e., unindented) "spdxVersion:" key
+type yamlDetector struct{}
+
+var yamlVersionRegex = regexp.MustCompile(`^spdxVersion:\s*["']?SPDX-([^"'\s]+)["']?\s*$`)
+var yamlKeyRegex = regexp.MustCompile(`^(SPDXID|dataLicense|documentNamespace|creationInfo):`)
+
+func (detector yamlDetector) Formats() []string {
+	return []string{FORMAT_SPDX_YAML}
+}
+
+func (detector yamlDetector) Detect(buffer []byte) (detection Detection) {
+	reader := bufio.NewReader(bytes.NewReader(header(buffer)))
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if matches := yamlVersionRegex.FindStringSubmatch(line); matches != nil {
+			return Detection{FORMAT_SPDX_YAML, matches[1], CONFIDENCE_HIGH}
+		}
+		if yamlKeyRegex.MatchString(line) {
+			detection = Detection{Format: FORMAT_SPDX_YAML, Confidence: CONFIDENCE_MEDIUM}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
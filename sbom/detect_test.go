@@ -0,0 +1,96 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sbom
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		input   string
+		format  string
+		version string
+	}{
+		{"\xef\xbb\xbf {\"bomFormat\": \"CycloneDX\", \"specVersion\": \"1.5\"}", FORMAT_CYCLONEDX_JSON, "1.5"},
+		{`{"components": [{"name": "x"}], "specVersion": "1.4", "bomFormat": "CycloneDX"}`, FORMAT_CYCLONEDX_JSON, "1.4"},
+		{`{"$schema": "http://cyclonedx.org/schema/bom-1.6.schema.json"}`, FORMAT_CYCLONEDX_JSON, ""},
+		{`{"SPDXID": "SPDXRef-DOCUMENT", "spdxVersion": "SPDX-2.3"}`, FORMAT_SPDX_JSON, "2.3"},
+		{`<?xml version="1.0"?><bom xmlns="http://cyclonedx.org/schema/bom/1.3" version="1"/>`, FORMAT_CYCLONEDX_XML, "1.3"},
+		{"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\" xmlns:spdx=\"http://spdx.org/rdf/terms#\">\n" +
+			"<spdx:SpdxDocument><spdx:specVersion>SPDX-2.2</spdx:specVersion></spdx:SpdxDocument></rdf:RDF>", FORMAT_SPDX_RDF, "2.2"},
+		{"SPDXVersion: SPDX-2.2\nDataLicense: CC0-1.0\n", FORMAT_SPDX_TV, "2.2"},
+		{"## comment\nDataLicense: CC0-1.0\nDocumentName: example\n", FORMAT_SPDX_TV, ""},
+		{"---\nSPDXID: SPDXRef-DOCUMENT\nspdxVersion: \"SPDX-2.3\"\n", FORMAT_SPDX_YAML, "2.3"},
+	}
+
+	for _, test := range tests {
+		detection, err := Detect([]byte(test.input))
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.format, detection.Format, test.input)
+		assert.Equal(t, test.version, detection.Version, test.input)
+	}
+}
+
+func TestDetectUnknown(t *testing.T) {
+	for _, input := range []string{"", "{}", `{"name": "x"}`, "<html></html>", "hello world"} {
+		_, err := Detect([]byte(input))
+		assert.True(t, errors.Is(err, ErrUnknownFormat), input)
+	}
+}
+
+func TestDetectAmbiguous(t *testing.T) {
+	// "SPDXID:" is both a tag-value tag and a YAML key
+	_, err := Detect([]byte("SPDXID: SPDXRef-DOCUMENT\n"))
+	assert.True(t, errors.Is(err, ErrAmbiguousFormat))
+
+	detection, err := DetectAs([]byte("SPDXID: SPDXRef-DOCUMENT\n"), FORMAT_SPDX_YAML)
+	assert.NoError(t, err)
+	assert.Equal(t, FORMAT_SPDX_YAML, detection.Format)
+
+	_, err = DetectAs([]byte("{}"), "unknown")
+	assert.Error(t, err)
+}
+
+// Fails the test if read, i.e., (where) the rest of a document must not be
+type unreadable struct{ t *testing.T }
+
+func (reader unreadable) Read([]byte) (int, error) {
+	reader.t.Error("read beyond the format (and version) keys")
+	return 0, io.EOF
+}
+
+// JSON detection stops reading once the format (and version) is known;
+// otherwise, keys are found after (large) nested values
+func TestDetectJSONStops(t *testing.T) {
+	identified := func(keys map[string]string) bool { return keys["bomFormat"] != "" && keys["specVersion"] != "" }
+	head := `{"bomFormat": "CycloneDX", "specVersion": "1.5", "components": [`
+	keys := topLevelJsonStrings(io.MultiReader(strings.NewReader(head), unreadable{t}), identified, "bomFormat", "specVersion")
+	assert.Equal(t, map[string]string{"bomFormat": "CycloneDX", "specVersion": "1.5"}, keys)
+
+	components := strings.Repeat(`{"type": "library", "name": "x", "version": "1.0"},`, 100000)
+	input := `{"components": [` + components + `{}], "bomFormat": "CycloneDX", "specVersion": "1.6"}`
+	detection, err := Detect([]byte(input))
+	assert.NoError(t, err)
+	assert.Equal(t, Detection{FORMAT_CYCLONEDX_JSON, "1.6", CONFIDENCE_HIGH}, detection)
+}
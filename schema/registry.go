@@ -0,0 +1,173 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Note: all schemas are embedded into the binary so that validation
+// never needs to fetch a (remote) `$schema` or `$ref` location at runtime
+//
+//go:embed resources
+var resources embed.FS
+
+const (
+	FORMAT_CYCLONEDX = "CycloneDX"
+	FORMAT_SPDX      = "SPDX"
+)
+
+// SPDX documents declare versions as "SPDX-x.y"; the registry keys on "x.y"
+const SPDX_VERSION_PREFIX = "SPDX-"
+
+// An (embedded) schema for a specific format and version
+type Schema struct {
+	Format  string // e.g., "CycloneDX", "SPDX"
+	Version string // e.g., "1.5", "2.3"
+	File    string // path to embedded resource
+	Url     string // official (published) location
+}
+
+// Official schemas keyed by format and version
+var Schemas = map[string]map[string]Schema{
+	FORMAT_CYCLONEDX: {
+		"1.2": {FORMAT_CYCLONEDX, "1.2", "resources/cyclonedx/bom-1.2.schema.json", "http://cyclonedx.org/schema/bom-1.2.schema.json"},
+		"1.3": {FORMAT_CYCLONEDX, "1.3", "resources/cyclonedx/bom-1.3.schema.json", "http://cyclonedx.org/schema/bom-1.3.schema.json"},
+		"1.4": {FORMAT_CYCLONEDX, "1.4", "resources/cyclonedx/bom-1.4.schema.json", "http://cyclonedx.org/schema/bom-1.4.schema.json"},
+		"1.5": {FORMAT_CYCLONEDX, "1.5", "resources/cyclonedx/bom-1.5.schema.json", "http://cyclonedx.org/schema/bom-1.5.schema.json"},
+		"1.6": {FORMAT_CYCLONEDX, "1.6", "resources/cyclonedx/bom-1.6.schema.json", "http://cyclonedx.org/schema/bom-1.6.schema.json"},
+	},
+	FORMAT_SPDX: {
+		"2.2": {FORMAT_SPDX, "2.2", "resources/spdx/spdx-2.2.schema.json", "https://raw.githubusercontent.com/spdx/spdx-spec/v2.2.2/schemas/spdx-schema.json"},
+		"2.3": {FORMAT_SPDX, "2.3", "resources/spdx/spdx-2.3.schema.json", "https://raw.githubusercontent.com/spdx/spdx-spec/v2.3/schemas/spdx-schema.json"},
+	},
+}
+
+// Official XML schemas (XSD) keyed by format and version (SPDX defines none)
+var XMLSchemas = map[string]map[string]Schema{
+	FORMAT_CYCLONEDX: {
+		"1.2": {FORMAT_CYCLONEDX, "1.2", "resources/cyclonedx/bom-1.2.xsd", "http://cyclonedx.org/schema/bom-1.2.xsd"},
+		"1.3": {FORMAT_CYCLONEDX, "1.3", "resources/cyclonedx/bom-1.3.xsd", "http://cyclonedx.org/schema/bom-1.3.xsd"},
+		"1.4": {FORMAT_CYCLONEDX, "1.4", "resources/cyclonedx/bom-1.4.xsd", "http://cyclonedx.org/schema/bom-1.4.xsd"},
+		"1.5": {FORMAT_CYCLONEDX, "1.5", "resources/cyclonedx/bom-1.5.xsd", "http://cyclonedx.org/schema/bom-1.5.xsd"},
+		"1.6": {FORMAT_CYCLONEDX, "1.6", "resources/cyclonedx/bom-1.6.xsd", "http://cyclonedx.org/schema/bom-1.6.xsd"},
+	},
+}
+
+// Schemas referenced (i.e., via `$ref`) by the top-level schemas above.
+// Each declares an `$id` that relative references are resolved against.
+var SubSchemas = []string{
+	"resources/cyclonedx/spdx.schema.json",
+	"resources/cyclonedx/jsf-0.82.schema.json",
+}
+
+// XML schemas imported (i.e., via `xs:import`) by the top-level XML schemas
+var XMLSubSchemas = []string{
+	"resources/cyclonedx/spdx.xsd",
+}
+
+// Lookup returns the embedded schema for the given format and version.
+// SPDX versions may be given with or without the "SPDX-" prefix.
+func Lookup(format string, version string) (Schema, error) {
+	return lookup(Schemas, format, version)
+}
+
+// LookupXML returns the embedded XML schema for the given format and version
+func LookupXML(format string, version string) (Schema, error) {
+	return lookup(XMLSchemas, format, version)
+}
+
+func lookup(schemas map[string]map[string]Schema, format string, version string) (Schema, error) {
+	versions, ok := schemas[format]
+	if !ok {
+		return Schema{}, fmt.Errorf("unsupported format: `%s`", format)
+	}
+	schema, ok := versions[strings.TrimPrefix(version, SPDX_VERSION_PREFIX)]
+	if !ok {
+		return Schema{}, fmt.Errorf("unsupported %s version: `%s`", format, version)
+	}
+	return schema, nil
+}
+
+// Bytes returns the raw (embedded) schema document
+func (schema Schema) Bytes() ([]byte, error) {
+	return resources.ReadFile(schema.File)
+}
+
+// Compile the embedded schema along with all known sub-schemas
+func (schema Schema) Compile() (*gojsonschema.Schema, error) {
+	buffer, err := schema.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return Compile(buffer)
+}
+
+// CompileFile compiles a custom (local) schema file; references to
+// any of the known sub-schemas are still resolved from the registry.
+func CompileFile(filename string) (*gojsonschema.Schema, error) {
+	buffer, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema file: %w", err)
+	}
+	return Compile(buffer)
+}
+
+// Compile a raw schema document after registering all (embedded) sub-schemas
+func Compile(buffer []byte) (*gojsonschema.Schema, error) {
+	schemaLoader := gojsonschema.NewSchemaLoader()
+	for _, subSchema := range SubSchemas {
+		subBuffer, err := resources.ReadFile(subSchema)
+		if err != nil {
+			return nil, err
+		}
+		if err = schemaLoader.AddSchemas(gojsonschema.NewBytesLoader(subBuffer)); err != nil {
+			return nil, fmt.Errorf("unable to load sub-schema `%s`: %w", subSchema, err)
+		}
+	}
+	return schemaLoader.Compile(gojsonschema.NewBytesLoader(buffer))
+}
+
+// Compile the embedded XML schema; imports are resolved from the embedded resources
+func (schema Schema) CompileXML() (*XMLSchema, error) {
+	return CompileXSD(resources.ReadFile, schema.File)
+}
+
+// CompileXMLFile compiles a custom (local) XML schema file; imports are read
+// relative to the file, falling back to the (embedded) known sub-schemas.
+func CompileXMLFile(filename string) (*XMLSchema, error) {
+	read := func(location string) ([]byte, error) {
+		buffer, err := os.ReadFile(filepath.FromSlash(location))
+		if err != nil && location != filepath.ToSlash(filename) {
+			for _, subSchema := range XMLSubSchemas {
+				if path.Base(subSchema) == path.Base(location) {
+					return resources.ReadFile(subSchema)
+				}
+			}
+		}
+		return buffer, err
+	}
+	return CompileXSD(read, filepath.ToSlash(filename))
+}
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Every registered schema must compile offline (i.e., all `$ref` resolved)
+func TestCompileAll(t *testing.T) {
+	for format, versions := range Schemas {
+		for version, schema := range versions {
+			_, err := schema.Compile()
+			assert.NoError(t, err, "%s %s", format, version)
+		}
+	}
+}
+
+// Every registered XSD (and the XSDs it imports) must compile offline
+func TestCompileAllXML(t *testing.T) {
+	for format, versions := range XMLSchemas {
+		for version, schema := range versions {
+			_, err := schema.CompileXML()
+			assert.NoError(t, err, "%s %s", format, version)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	schema, err := Lookup(FORMAT_SPDX, "SPDX-2.3")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.3", schema.Version)
+
+	_, err = Lookup(FORMAT_CYCLONEDX, "0.9")
+	assert.Error(t, err)
+
+	_, err = Lookup("unknown", "1.0")
+	assert.Error(t, err)
+
+	schema, err = LookupXML(FORMAT_CYCLONEDX, "1.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "resources/cyclonedx/bom-1.4.xsd", schema.File)
+
+	_, err = LookupXML(FORMAT_SPDX, "2.3")
+	assert.Error(t, err)
+}
+
+func TestValidateCycloneDX(t *testing.T) {
+	schema, err := Lookup(FORMAT_CYCLONEDX, "1.5")
+	assert.NoError(t, err)
+	jsonSchema, err := schema.Compile()
+	assert.NoError(t, err)
+
+	valid := `{"bomFormat":"CycloneDX","specVersion":"1.5","version":1,
+		"components":[{"type":"library","name":"acme","licenses":[{"license":{"id":"Apache-2.0"}}]}]}`
+	result, err := jsonSchema.Validate(gojsonschema.NewStringLoader(valid))
+	assert.NoError(t, err)
+	assert.True(t, result.Valid())
+
+	invalid := `{"bomFormat":"CycloneDX","specVersion":"1.5","components":[{"type":"unknown"}]}`
+	result, err = jsonSchema.Validate(gojsonschema.NewStringLoader(invalid))
+	assert.NoError(t, err)
+	assert.False(t, result.Valid())
+}
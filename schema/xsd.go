@@ -0,0 +1,1347 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// A W3C XML Schema (XSD) 1.0 validator for the subset of XSD used by the
+// (official) CycloneDX schemas: global and local element declarations,
+// named and anonymous simple and complex types, sequence and choice content
+// models, element and attribute wildcards, simple content extensions,
+// restriction facets, unions and `xs:unique` identity constraints.
+// Compiling a schema that uses any other construct fails, rather than
+// silently validating less than the schema requires.
+//
+// The supported constructs, and their (supported) attributes, are listed by
+// xsdAttributes; in particular, the following are NOT supported:
+//   - element and attribute references (`ref`), `xs:group` and `xs:attributeGroup`
+//   - substitution groups, abstract and nillable elements and `fixed` values
+//   - `xs:all`, `xs:complexContent` and `xs:list`
+//   - the `totalDigits` and `fractionDigits` facets
+//   - `xs:key` and `xs:keyref` (and `xs:unique` other than `.//*` and `@attribute`)
+//   - `xs:include` and `xs:redefine` (only `xs:import`)
+//
+// Default values (`default`) and derivation controls (`final`) are accepted,
+// as they do not change whether a document is valid.
+
+const (
+	XSD_NAMESPACE = "http://www.w3.org/2001/XMLSchema"
+	XSI_NAMESPACE = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+const UNBOUNDED = -1
+
+// A single schema violation found in an XML document
+type XMLError struct {
+	Path    string // XPath-like location (e.g., "/bom/components/component[2]/@type")
+	Keyword string // XSD construct or facet that failed (e.g., "enumeration")
+	Message string // human-readable description
+	Value   string // the offending value (if any)
+}
+
+func (err XMLError) String() string {
+	return fmt.Sprintf("%s: %s (%s)", err.Path, err.Message, err.Keyword)
+}
+
+// A compiled XML schema, including the schemas it (transitively) imports
+type XMLSchema struct {
+	elements     map[xml.Name]*xsdElement
+	complexTypes map[xml.Name]*xsdComplexType
+	simpleTypes  map[xml.Name]*xsdSimpleType
+}
+
+type xsdElement struct {
+	name    xml.Name
+	typ     xsdType
+	uniques []xsdUnique
+}
+
+// A reference to a named type or an anonymous (inline) type; after linking,
+// at most one of simple and complex is set (neither for `xs:anyType`).
+type xsdType struct {
+	name    xml.Name
+	simple  *xsdSimpleType
+	complex *xsdComplexType
+}
+
+// A particle of a content model: an element, a wildcard or a group of particles
+type xsdParticle struct {
+	kind      string // "element", "any", "sequence" or "choice"
+	min, max  int    // occurrence (max is UNBOUNDED for "unbounded")
+	element   *xsdElement
+	particles []*xsdParticle
+	wildcard  xsdWildcard
+}
+
+type xsdWildcard struct {
+	namespace       string // e.g., "##any", "##other" or a list of namespaces
+	processContents string // "strict", "lax" or "skip"
+	targetNamespace string
+}
+
+type xsdComplexType struct {
+	name         xml.Name
+	content      *xsdParticle             // nil for empty (or simple) content
+	elements     map[xml.Name]*xsdElement // the element particles of the content model
+	wildcards    []xsdWildcard            // the wildcard particles of the content model
+	simple       *xsdType                 // base type of simple content (if any)
+	attributes   []*xsdAttribute
+	anyAttribute *xsdWildcard
+	mixed        bool
+}
+
+type xsdAttribute struct {
+	name     string
+	typ      xsdType
+	required bool
+}
+
+// Only the `.//*` selector and `@attribute` fields are supported
+type xsdUnique struct {
+	name      string
+	attribute string
+}
+
+type xsdSimpleType struct {
+	name         xml.Name
+	base         *xsdSimpleType
+	members      []*xsdSimpleType // union
+	whiteSpace   string           // "preserve", "replace" or "collapse"; empty if inherited
+	lexical      *regexp.Regexp   // lexical space of built-in types
+	numeric      bool
+	enumeration  []string
+	patterns     []*regexp.Regexp
+	minLength    int
+	maxLength    int
+	minInclusive *big.Rat
+	maxInclusive *big.Rat
+	minExclusive *big.Rat
+	maxExclusive *big.Rat
+}
+
+// ------------------------------------------------------------------------
+// Built-in types
+// ------------------------------------------------------------------------
+
+const (
+	WHITESPACE_PRESERVE = "preserve"
+	WHITESPACE_REPLACE  = "replace"
+	WHITESPACE_COLLAPSE = "collapse"
+)
+
+var (
+	decimalRegex  = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	integerRegex  = regexp.MustCompile(`^[+-]?\d+$`)
+	booleanRegex  = regexp.MustCompile(`^(true|false|1|0)$`)
+	dateTimeRegex = regexp.MustCompile(`^-?\d{4,}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])T(([01]\d|2[0-3]):[0-5]\d:[0-5]\d(\.\d+)?|24:00:00(\.0+)?)(Z|[+-]((0\d|1[0-3]):[0-5]\d|14:00))?$`)
+)
+
+func builtinTypes() map[xml.Name]*xsdSimpleType {
+	builtin := func(name string, base *xsdSimpleType, whiteSpace string, lexical *regexp.Regexp) *xsdSimpleType {
+		return &xsdSimpleType{
+			name:       xml.Name{Space: XSD_NAMESPACE, Local: name},
+			base:       base,
+			whiteSpace: whiteSpace,
+			lexical:    lexical,
+			minLength:  -1,
+			maxLength:  -1,
+		}
+	}
+
+	anySimpleType := builtin("anySimpleType", nil, WHITESPACE_PRESERVE, nil)
+	str := builtin("string", anySimpleType, WHITESPACE_PRESERVE, nil)
+	normalizedString := builtin("normalizedString", str, WHITESPACE_REPLACE, nil)
+	token := builtin("token", normalizedString, WHITESPACE_COLLAPSE, nil)
+	decimal := builtin("decimal", anySimpleType, WHITESPACE_COLLAPSE, decimalRegex)
+	decimal.numeric = true
+	integer := builtin("integer", decimal, "", integerRegex)
+	nonNegativeInteger := builtin("nonNegativeInteger", integer, "", nil)
+	nonNegativeInteger.minInclusive = big.NewRat(0, 1)
+	positiveInteger := builtin("positiveInteger", nonNegativeInteger, "", nil)
+	positiveInteger.minInclusive = big.NewRat(1, 1)
+
+	types := make(map[xml.Name]*xsdSimpleType)
+	for _, simpleType := range []*xsdSimpleType{
+		anySimpleType, str, normalizedString, token, decimal, integer, nonNegativeInteger, positiveInteger,
+		builtin("anyURI", anySimpleType, WHITESPACE_COLLAPSE, nil),
+		builtin("boolean", anySimpleType, WHITESPACE_COLLAPSE, booleanRegex),
+		builtin("dateTime", anySimpleType, WHITESPACE_COLLAPSE, dateTimeRegex),
+	} {
+		types[simpleType.name] = simpleType
+	}
+	return types
+}
+
+var anyTypeName = xml.Name{Space: XSD_NAMESPACE, Local: "anyType"}
+
+// ------------------------------------------------------------------------
+// Compiler
+// ------------------------------------------------------------------------
+
+// A (parsed) schema document node; attribute values are kept as is, as
+// their QName prefixes are resolved against the node's namespace scope.
+type xsdNode struct {
+	name       xml.Name
+	attrs      map[string]string
+	namespaces map[string]string
+	parent     *xsdNode
+	children   []*xsdNode
+}
+
+func (node *xsdNode) attr(name string, fallback string) string {
+	if value, ok := node.attrs[name]; ok {
+		return value
+	}
+	return fallback
+}
+
+// Resolve a QName (e.g., "bom:component") against the in-scope namespaces
+func (node *xsdNode) qname(value string) (xml.Name, error) {
+	prefix, local := "", strings.TrimSpace(value)
+	if i := strings.Index(local, ":"); i >= 0 {
+		prefix, local = local[:i], local[i+1:]
+	}
+	for scope := node; scope != nil; scope = scope.parent {
+		if namespace, ok := scope.namespaces[prefix]; ok {
+			return xml.Name{Space: namespace, Local: local}, nil
+		}
+	}
+	if prefix == "" {
+		return xml.Name{Local: local}, nil
+	}
+	return xml.Name{}, fmt.Errorf("undeclared namespace prefix in `%s`", value)
+}
+
+func parseXSD(data []byte) (*xsdNode, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root, current *xsdNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xsdNode{
+				name:       token.Name,
+				attrs:      make(map[string]string),
+				namespaces: make(map[string]string),
+				parent:     current,
+			}
+			for _, attr := range token.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					node.namespaces[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					node.namespaces[""] = attr.Value
+				case attr.Name.Space == "":
+					node.attrs[attr.Name.Local] = attr.Value
+				}
+			}
+			if current == nil {
+				root = node
+			} else {
+				current.children = append(current.children, node)
+			}
+			current = node
+		case xml.EndElement:
+			current = current.parent
+		}
+	}
+	if root == nil || root.name != (xml.Name{Space: XSD_NAMESPACE, Local: "schema"}) {
+		return nil, fmt.Errorf("not an XML schema (expected root element `xs:schema`)")
+	}
+	return root, nil
+}
+
+type xsdCompiler struct {
+	schema *XMLSchema
+	read   func(location string) ([]byte, error)
+	loaded map[string]bool // target namespaces
+	links  []func() error  // resolve named type references once all documents are loaded
+}
+
+// CompileXSD compiles the schema document at the given location; imported
+// schemas are read (via the same function) relative to the importing document.
+func CompileXSD(read func(location string) ([]byte, error), location string) (*XMLSchema, error) {
+	compiler := &xsdCompiler{
+		schema: &XMLSchema{
+			elements:     make(map[xml.Name]*xsdElement),
+			complexTypes: make(map[xml.Name]*xsdComplexType),
+			simpleTypes:  builtinTypes(),
+		},
+		read:   read,
+		loaded: make(map[string]bool),
+	}
+	if err := compiler.load(location); err != nil {
+		return nil, err
+	}
+	for _, link := range compiler.links {
+		if err := link(); err != nil {
+			return nil, err
+		}
+	}
+	return compiler.schema, nil
+}
+
+func (compiler *xsdCompiler) load(location string) error {
+	data, err := compiler.read(location)
+	if err != nil {
+		return err
+	}
+	root, err := parseXSD(data)
+	if err != nil {
+		return fmt.Errorf("unable to parse schema `%s`: %w", location, err)
+	}
+	if err = compiler.compileSchema(root, path.Dir(location)); err != nil {
+		return fmt.Errorf("unable to compile schema `%s`: %w", location, err)
+	}
+	return nil
+}
+
+type xsdScope struct {
+	targetNamespace string
+	qualified       bool // elementFormDefault="qualified"
+}
+
+// The supported constructs and, per construct, its supported attributes
+// and (if restricted) their supported values
+var xsdAttributes = map[string]map[string][]string{
+	"schema":        {"id": nil, "version": nil, "targetNamespace": nil, "elementFormDefault": nil, "attributeFormDefault": {"unqualified"}},
+	"import":        {"id": nil, "namespace": nil, "schemaLocation": nil},
+	"element":       {"id": nil, "name": nil, "type": nil, "minOccurs": nil, "maxOccurs": nil, "form": nil, "default": nil, "nillable": {"false"}, "abstract": {"false"}},
+	"attribute":     {"id": nil, "name": nil, "type": nil, "use": nil, "form": {"unqualified"}, "default": nil},
+	"any":           {"id": nil, "namespace": nil, "processContents": {"strict", "lax", "skip"}, "minOccurs": nil, "maxOccurs": nil},
+	"anyAttribute":  {"id": nil, "namespace": nil, "processContents": {"strict", "lax", "skip"}},
+	"sequence":      {"id": nil, "minOccurs": nil, "maxOccurs": nil},
+	"choice":        {"id": nil, "minOccurs": nil, "maxOccurs": nil},
+	"complexType":   {"id": nil, "name": nil, "mixed": nil, "abstract": {"false"}},
+	"simpleContent": {"id": nil},
+	"extension":     {"id": nil, "base": nil},
+	"simpleType":    {"id": nil, "name": nil, "final": nil},
+	"restriction":   {"id": nil, "base": nil},
+	"union":         {"id": nil, "memberTypes": nil},
+	"enumeration":   {"id": nil, "value": nil},
+	"pattern":       {"id": nil, "value": nil},
+	"length":        {"id": nil, "value": nil, "fixed": nil},
+	"minLength":     {"id": nil, "value": nil, "fixed": nil},
+	"maxLength":     {"id": nil, "value": nil, "fixed": nil},
+	"minInclusive":  {"id": nil, "value": nil, "fixed": nil},
+	"maxInclusive":  {"id": nil, "value": nil, "fixed": nil},
+	"minExclusive":  {"id": nil, "value": nil, "fixed": nil},
+	"maxExclusive":  {"id": nil, "value": nil, "fixed": nil},
+	"whiteSpace":    {"id": nil, "value": {"preserve", "replace", "collapse"}, "fixed": nil},
+	"unique":        {"id": nil, "name": nil},
+	"selector":      {"id": nil, "xpath": nil},
+	"field":         {"id": nil, "xpath": nil},
+}
+
+// Fail on (XSD) attributes (e.g., `substitutionGroup`) the validator would
+// otherwise ignore; annotations (i.e., documentation) are not checked
+// Note: unsupported constructs fail as they are compiled
+func checkAttributes(node *xsdNode) error {
+	if node.name.Local == "annotation" {
+		return nil
+	}
+	if supported, ok := xsdAttributes[node.name.Local]; ok {
+		for attribute, value := range node.attrs {
+			values, ok := supported[attribute]
+			if !ok {
+				return unsupported(node, attribute)
+			} else if values != nil && !contains(values, value) {
+				return fmt.Errorf("unsupported XSD attribute value: `%s=\"%s\"` (on `xs:%s`)", attribute, value, node.name.Local)
+			}
+		}
+	}
+	for _, child := range node.children {
+		if err := checkAttributes(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (compiler *xsdCompiler) compileSchema(root *xsdNode, dir string) error {
+	if err := checkAttributes(root); err != nil {
+		return err
+	}
+	scope := xsdScope{
+		targetNamespace: root.attr("targetNamespace", ""),
+		qualified:       root.attr("elementFormDefault", "unqualified") == "qualified",
+	}
+	compiler.loaded[scope.targetNamespace] = true
+
+	for _, node := range root.children {
+		switch node.name.Local {
+		case "annotation":
+		case "import":
+			namespace := node.attr("namespace", "")
+			if compiler.loaded[namespace] {
+				continue
+			}
+			location, ok := node.attrs["schemaLocation"]
+			if !ok {
+				return fmt.Errorf("import of namespace `%s` without a schemaLocation", namespace)
+			}
+			if err := compiler.load(path.Join(dir, location)); err != nil {
+				return err
+			}
+		case "simpleType":
+			simpleType, err := compiler.simpleType(node, scope)
+			if err != nil {
+				return err
+			}
+			compiler.schema.simpleTypes[simpleType.name] = simpleType
+		case "complexType":
+			complexType, err := compiler.complexType(node, scope)
+			if err != nil {
+				return err
+			}
+			compiler.schema.complexTypes[complexType.name] = complexType
+		case "element":
+			// global elements are always qualified (by the target namespace)
+			element, err := compiler.element(node, xsdScope{scope.targetNamespace, true})
+			if err != nil {
+				return err
+			}
+			compiler.schema.elements[element.name] = element
+		default:
+			return unsupported(node, "")
+		}
+	}
+	return nil
+}
+
+func unsupported(node *xsdNode, attribute string) error {
+	if attribute != "" {
+		return fmt.Errorf("unsupported XSD attribute: `%s` (on `xs:%s`)", attribute, node.name.Local)
+	}
+	return fmt.Errorf("unsupported XSD construct: `xs:%s`", node.name.Local)
+}
+
+func name(node *xsdNode, scope xsdScope) xml.Name {
+	return xml.Name{Space: scope.targetNamespace, Local: node.attr("name", "")}
+}
+
+func occurrence(node *xsdNode) (min int, max int, err error) {
+	if min, err = strconv.Atoi(node.attr("minOccurs", "1")); err != nil {
+		return
+	}
+	if value := node.attr("maxOccurs", "1"); value == "unbounded" {
+		max = UNBOUNDED
+	} else {
+		max, err = strconv.Atoi(value)
+	}
+	return
+}
+
+// Resolve the type referenced by the `type` (or `base`) attribute after all
+// documents are loaded; named simple and complex types share a symbol space.
+func (compiler *xsdCompiler) typeRef(node *xsdNode, attribute string, typ *xsdType) error {
+	qname, err := node.qname(node.attrs[attribute])
+	if err != nil {
+		return err
+	}
+	typ.name = qname
+	compiler.links = append(compiler.links, func() error {
+		if complexType, ok := compiler.schema.complexTypes[qname]; ok {
+			typ.complex = complexType
+		} else if simpleType, ok := compiler.schema.simpleTypes[qname]; ok {
+			typ.simple = simpleType
+		} else if qname != anyTypeName {
+			return fmt.Errorf("unresolved type: `%s`", formatName(qname))
+		}
+		return nil
+	})
+	return nil
+}
+
+func (compiler *xsdCompiler) simpleTypeRef(node *xsdNode, value string, set func(*xsdSimpleType)) error {
+	qname, err := node.qname(value)
+	if err != nil {
+		return err
+	}
+	compiler.links = append(compiler.links, func() error {
+		simpleType, ok := compiler.schema.simpleTypes[qname]
+		if !ok {
+			return fmt.Errorf("unresolved simple type: `%s`", formatName(qname))
+		}
+		set(simpleType)
+		return nil
+	})
+	return nil
+}
+
+func (compiler *xsdCompiler) element(node *xsdNode, scope xsdScope) (*xsdElement, error) {
+	if _, ok := node.attrs["ref"]; ok {
+		return nil, unsupported(node, "ref")
+	}
+	element := &xsdElement{name: xml.Name{Local: node.attr("name", "")}}
+	if form := node.attr("form", ""); form == "qualified" || (form == "" && scope.qualified) {
+		element.name.Space = scope.targetNamespace
+	}
+
+	if _, ok := node.attrs["type"]; ok {
+		if err := compiler.typeRef(node, "type", &element.typ); err != nil {
+			return nil, err
+		}
+	}
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "complexType":
+			complexType, err := compiler.complexType(child, scope)
+			if err != nil {
+				return nil, err
+			}
+			element.typ.complex = complexType
+		case "simpleType":
+			simpleType, err := compiler.simpleType(child, scope)
+			if err != nil {
+				return nil, err
+			}
+			element.typ.simple = simpleType
+		case "unique":
+			unique, err := compileUnique(child)
+			if err != nil {
+				return nil, err
+			}
+			element.uniques = append(element.uniques, unique)
+		default:
+			return nil, unsupported(child, "")
+		}
+	}
+	return element, nil
+}
+
+func compileUnique(node *xsdNode) (unique xsdUnique, err error) {
+	unique.name = node.attr("name", "")
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "selector":
+			if xpath := child.attr("xpath", ""); xpath != ".//*" {
+				return unique, fmt.Errorf("unsupported xs:unique selector: `%s`", xpath)
+			}
+		case "field":
+			xpath := child.attr("xpath", "")
+			if !strings.HasPrefix(xpath, "@") || unique.attribute != "" {
+				return unique, fmt.Errorf("unsupported xs:unique field: `%s`", xpath)
+			}
+			unique.attribute = xpath[1:]
+		default:
+			return unique, unsupported(child, "")
+		}
+	}
+	return
+}
+
+func (compiler *xsdCompiler) complexType(node *xsdNode, scope xsdScope) (*xsdComplexType, error) {
+	complexType := &xsdComplexType{
+		name:     name(node, scope),
+		elements: make(map[xml.Name]*xsdElement),
+		mixed:    node.attr("mixed", "false") == "true",
+	}
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "sequence", "choice":
+			particle, err := compiler.particle(child, scope, complexType)
+			if err != nil {
+				return nil, err
+			}
+			complexType.content = particle
+		case "attribute", "anyAttribute":
+			if err := compiler.attribute(child, scope, complexType); err != nil {
+				return nil, err
+			}
+		case "simpleContent":
+			if err := compiler.simpleContent(child, scope, complexType); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, unsupported(child, "")
+		}
+	}
+	return complexType, nil
+}
+
+func (compiler *xsdCompiler) simpleContent(node *xsdNode, scope xsdScope, complexType *xsdComplexType) error {
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "extension":
+			complexType.simple = new(xsdType)
+			if err := compiler.typeRef(child, "base", complexType.simple); err != nil {
+				return err
+			}
+			for _, attribute := range child.children {
+				switch attribute.name.Local {
+				case "annotation":
+				case "attribute", "anyAttribute":
+					if err := compiler.attribute(attribute, scope, complexType); err != nil {
+						return err
+					}
+				default:
+					return unsupported(attribute, "")
+				}
+			}
+		default:
+			return unsupported(child, "")
+		}
+	}
+	return nil
+}
+
+func (compiler *xsdCompiler) attribute(node *xsdNode, scope xsdScope, complexType *xsdComplexType) error {
+	if node.name.Local == "anyAttribute" {
+		complexType.anyAttribute = &xsdWildcard{
+			namespace:       node.attr("namespace", "##any"),
+			processContents: node.attr("processContents", "strict"),
+			targetNamespace: scope.targetNamespace,
+		}
+		return nil
+	}
+
+	if _, ok := node.attrs["ref"]; ok {
+		return unsupported(node, "ref")
+	}
+	use := node.attr("use", "optional")
+	if use == "prohibited" {
+		return nil
+	}
+	attribute := &xsdAttribute{name: node.attr("name", ""), required: use == "required"}
+	if _, ok := node.attrs["type"]; ok {
+		if err := compiler.typeRef(node, "type", &attribute.typ); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "simpleType":
+			simpleType, err := compiler.simpleType(child, scope)
+			if err != nil {
+				return err
+			}
+			attribute.typ.simple = simpleType
+		default:
+			return unsupported(child, "")
+		}
+	}
+	complexType.attributes = append(complexType.attributes, attribute)
+	return nil
+}
+
+func (compiler *xsdCompiler) particle(node *xsdNode, scope xsdScope, complexType *xsdComplexType) (*xsdParticle, error) {
+	min, max, err := occurrence(node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid occurrence on `xs:%s`: %w", node.name.Local, err)
+	}
+	particle := &xsdParticle{kind: node.name.Local, min: min, max: max}
+
+	switch particle.kind {
+	case "element":
+		if particle.element, err = compiler.element(node, scope); err != nil {
+			return nil, err
+		}
+		if _, found := complexType.elements[particle.element.name]; !found {
+			complexType.elements[particle.element.name] = particle.element
+		}
+	case "any":
+		particle.wildcard = xsdWildcard{
+			namespace:       node.attr("namespace", "##any"),
+			processContents: node.attr("processContents", "strict"),
+			targetNamespace: scope.targetNamespace,
+		}
+		complexType.wildcards = append(complexType.wildcards, particle.wildcard)
+	case "sequence", "choice":
+		for _, child := range node.children {
+			if child.name.Local == "annotation" {
+				continue
+			}
+			subParticle, err := compiler.particle(child, scope, complexType)
+			if err != nil {
+				return nil, err
+			}
+			particle.particles = append(particle.particles, subParticle)
+		}
+	default:
+		return nil, unsupported(node, "")
+	}
+	return particle, nil
+}
+
+func (compiler *xsdCompiler) simpleType(node *xsdNode, scope xsdScope) (*xsdSimpleType, error) {
+	simpleType := &xsdSimpleType{name: name(node, scope), minLength: -1, maxLength: -1}
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "restriction":
+			if err := compiler.restriction(child, scope, simpleType); err != nil {
+				return nil, err
+			}
+		case "union":
+			if err := compiler.union(child, scope, simpleType); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, unsupported(child, "")
+		}
+	}
+	return simpleType, nil
+}
+
+func (compiler *xsdCompiler) union(node *xsdNode, scope xsdScope, simpleType *xsdSimpleType) error {
+	memberTypes := strings.Fields(node.attr("memberTypes", ""))
+	simpleType.members = make([]*xsdSimpleType, len(memberTypes))
+	for i, member := range memberTypes {
+		i := i
+		if err := compiler.simpleTypeRef(node, member, func(member *xsdSimpleType) { simpleType.members[i] = member }); err != nil {
+			return err
+		}
+	}
+	for _, child := range node.children {
+		switch child.name.Local {
+		case "annotation":
+		case "simpleType":
+			member, err := compiler.simpleType(child, scope)
+			if err != nil {
+				return err
+			}
+			simpleType.members = append(simpleType.members, member)
+		default:
+			return unsupported(child, "")
+		}
+	}
+	return nil
+}
+
+func (compiler *xsdCompiler) restriction(node *xsdNode, scope xsdScope, simpleType *xsdSimpleType) (err error) {
+	if base, ok := node.attrs["base"]; ok {
+		if err = compiler.simpleTypeRef(node, base, func(base *xsdSimpleType) { simpleType.base = base }); err != nil {
+			return
+		}
+	}
+
+	for _, facet := range node.children {
+		value := facet.attr("value", "")
+		switch facet.name.Local {
+		case "annotation":
+		case "simpleType":
+			simpleType.base, err = compiler.simpleType(facet, scope)
+		case "enumeration":
+			simpleType.enumeration = append(simpleType.enumeration, value)
+		case "pattern":
+			var pattern *regexp.Regexp
+			if pattern, err = compilePattern(value); err == nil {
+				simpleType.patterns = append(simpleType.patterns, pattern)
+			}
+		case "length":
+			if simpleType.minLength, err = strconv.Atoi(value); err == nil {
+				simpleType.maxLength = simpleType.minLength
+			}
+		case "minLength":
+			simpleType.minLength, err = strconv.Atoi(value)
+		case "maxLength":
+			simpleType.maxLength, err = strconv.Atoi(value)
+		case "minInclusive":
+			simpleType.minInclusive, err = parseDecimal(value)
+		case "maxInclusive":
+			simpleType.maxInclusive, err = parseDecimal(value)
+		case "minExclusive":
+			simpleType.minExclusive, err = parseDecimal(value)
+		case "maxExclusive":
+			simpleType.maxExclusive, err = parseDecimal(value)
+		case "whiteSpace":
+			simpleType.whiteSpace = value
+		default:
+			return unsupported(facet, "")
+		}
+		if err != nil {
+			return fmt.Errorf("invalid `xs:%s` facet value `%s`: %w", facet.name.Local, value, err)
+		}
+	}
+	return nil
+}
+
+// XSD patterns are implicitly anchored and, outside of character classes,
+// do not treat `^` and `$` as anchors; otherwise, the (CycloneDX) patterns
+// are valid RE2 syntax.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	var builder strings.Builder
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\' && i+1 < len(pattern):
+			builder.WriteString(pattern[i : i+2])
+			i++
+		case c == '[':
+			inClass = true
+			builder.WriteByte(c)
+		case c == ']':
+			inClass = false
+			builder.WriteByte(c)
+		case (c == '^' || c == '$') && !inClass:
+			builder.WriteByte('\\')
+			builder.WriteByte(c)
+		default:
+			builder.WriteByte(c)
+		}
+	}
+	return regexp.Compile("^(?:" + builder.String() + ")$")
+}
+
+func parseDecimal(value string) (*big.Rat, error) {
+	if !decimalRegex.MatchString(value) {
+		return nil, fmt.Errorf("not a decimal: `%s`", value)
+	}
+	value = strings.TrimPrefix(value, "+")
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+	if strings.HasPrefix(value, ".") {
+		value = "0" + value
+	}
+	rat, ok := new(big.Rat).SetString(sign + strings.TrimSuffix(value, "."))
+	if !ok {
+		return nil, fmt.Errorf("not a decimal: `%s`", value)
+	}
+	return rat, nil
+}
+
+func formatName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return fmt.Sprintf("{%s}%s", name.Space, name.Local)
+}
+
+// ------------------------------------------------------------------------
+// Simple type (i.e., text and attribute value) validation
+// ------------------------------------------------------------------------
+
+func (simpleType *xsdSimpleType) label() string {
+	if simpleType.name.Local == "" {
+		return "(anonymous type)"
+	}
+	if simpleType.name.Space == XSD_NAMESPACE {
+		return "xs:" + simpleType.name.Local
+	}
+	return simpleType.name.Local
+}
+
+func (simpleType *xsdSimpleType) whiteSpaceFacet() string {
+	for current := simpleType; current != nil; current = current.base {
+		if current.whiteSpace != "" {
+			return current.whiteSpace
+		}
+	}
+	return WHITESPACE_PRESERVE
+}
+
+func (simpleType *xsdSimpleType) isNumeric() bool {
+	for current := simpleType; current != nil; current = current.base {
+		if current.numeric {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeWhiteSpace(value string, whiteSpace string) string {
+	if whiteSpace == WHITESPACE_PRESERVE {
+		return value
+	}
+	value = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+	if whiteSpace == WHITESPACE_COLLAPSE {
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	return value
+}
+
+// A value that is not valid for its (simple) type
+type xsdViolation struct {
+	keyword string
+	message string
+}
+
+func violation(keyword string, format string, a ...interface{}) *xsdViolation {
+	return &xsdViolation{keyword, fmt.Sprintf(format, a...)}
+}
+
+// Enumerations longer than this are not listed in violation messages
+const MAX_LISTED_ENUMERATION = 10
+
+func (simpleType *xsdSimpleType) check(value string) *xsdViolation {
+	if len(simpleType.members) > 0 {
+		for _, member := range simpleType.members {
+			if member.check(value) == nil {
+				return nil
+			}
+		}
+		return violation("union", "value is not valid for any member type of `%s`", simpleType.label())
+	}
+
+	value = normalizeWhiteSpace(value, simpleType.whiteSpaceFacet())
+	if simpleType.base != nil {
+		if err := simpleType.base.check(value); err != nil {
+			return err
+		}
+	}
+	if simpleType.lexical != nil && !simpleType.lexical.MatchString(value) {
+		return violation("type", "value is not a valid `%s`", simpleType.label())
+	}
+
+	if len(simpleType.enumeration) > 0 && !contains(simpleType.enumeration, value) {
+		if len(simpleType.enumeration) > MAX_LISTED_ENUMERATION {
+			return violation("enumeration", "value is not one of the %d values allowed by `%s`", len(simpleType.enumeration), simpleType.label())
+		}
+		return violation("enumeration", "value must be one of: %s", strings.Join(simpleType.enumeration, ", "))
+	}
+	if len(simpleType.patterns) > 0 && !matchesAny(simpleType.patterns, value) {
+		return violation("pattern", "value does not match the pattern of `%s`", simpleType.label())
+	}
+
+	if length := utf8.RuneCountInString(value); simpleType.minLength >= 0 && length < simpleType.minLength {
+		return violation("minLength", "value must be at least %d characters long", simpleType.minLength)
+	} else if simpleType.maxLength >= 0 && length > simpleType.maxLength {
+		return violation("maxLength", "value must be at most %d characters long", simpleType.maxLength)
+	}
+
+	if simpleType.isNumeric() {
+		return simpleType.checkRange(value)
+	}
+	return nil
+}
+
+func (simpleType *xsdSimpleType) checkRange(value string) *xsdViolation {
+	number, err := parseDecimal(value)
+	if err != nil {
+		return violation("type", "value is not a valid `%s`", simpleType.label())
+	}
+	switch {
+	case simpleType.minInclusive != nil && number.Cmp(simpleType.minInclusive) < 0:
+		return violation("minInclusive", "value must be greater than or equal to %s", simpleType.minInclusive.RatString())
+	case simpleType.maxInclusive != nil && number.Cmp(simpleType.maxInclusive) > 0:
+		return violation("maxInclusive", "value must be less than or equal to %s", simpleType.maxInclusive.RatString())
+	case simpleType.minExclusive != nil && number.Cmp(simpleType.minExclusive) <= 0:
+		return violation("minExclusive", "value must be greater than %s", simpleType.minExclusive.RatString())
+	case simpleType.maxExclusive != nil && number.Cmp(simpleType.maxExclusive) >= 0:
+		return violation("maxExclusive", "value must be less than %s", simpleType.maxExclusive.RatString())
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ------------------------------------------------------------------------
+// Document validation
+// ------------------------------------------------------------------------
+
+// An element of the (instance) document being validated
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     strings.Builder
+	children []*xmlNode
+	path     string
+}
+
+func parseXMLDocument(reader io.Reader) (*xmlNode, error) {
+	decoder := xml.NewDecoder(reader)
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			node := &xmlNode{name: token.Name, attrs: token.Attr}
+			if len(stack) == 0 {
+				root = node
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, node)
+			}
+			stack = append(stack, node)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(token)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	root.setPaths("/" + root.name.Local)
+	return root, nil
+}
+
+// Children are indexed (e.g., "component[2]") only if their name repeats
+func (node *xmlNode) setPaths(path string) {
+	node.path = path
+	counts := make(map[xml.Name]int)
+	for _, child := range node.children {
+		counts[child.name]++
+	}
+	indexes := make(map[xml.Name]int)
+	for _, child := range node.children {
+		childPath := path + "/" + child.name.Local
+		if counts[child.name] > 1 {
+			indexes[child.name]++
+			childPath = fmt.Sprintf("%s[%d]", childPath, indexes[child.name])
+		}
+		child.setPaths(childPath)
+	}
+}
+
+type xsdValidator struct {
+	schema *XMLSchema
+	errors []XMLError
+}
+
+func (validator *xsdValidator) report(path string, keyword string, value string, format string, a ...interface{}) {
+	validator.errors = append(validator.errors, XMLError{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, a...),
+		Value:   value,
+	})
+}
+
+// Validate parses the XML document and validates it against the schema.
+// Schema violations are returned as errors; a document that is not
+// (well-formed) XML fails with an error instead.
+func (schema *XMLSchema) Validate(reader io.Reader) ([]XMLError, error) {
+	root, err := parseXMLDocument(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	validator := &xsdValidator{schema: schema}
+	element, ok := schema.elements[root.name]
+	if !ok {
+		validator.report(root.path, "element", "", "no declaration found for root element `%s`", formatName(root.name))
+	} else {
+		validator.validateElement(root, element)
+	}
+	return validator.errors, nil
+}
+
+func (validator *xsdValidator) validateElement(node *xmlNode, element *xsdElement) {
+	switch typ := element.typ; {
+	case typ.complex != nil:
+		validator.validateComplex(node, typ.complex)
+	case typ.simple != nil:
+		validator.validateAttributes(node, nil)
+		if len(node.children) > 0 {
+			validator.report(node.children[0].path, "content", "", "element `%s` has a simple type; child elements are not allowed", node.name.Local)
+		}
+		validator.validateText(node.path, node.text.String(), typ.simple)
+	}
+	for _, unique := range element.uniques {
+		validator.validateUnique(node, unique)
+	}
+}
+
+func (validator *xsdValidator) validateText(path string, value string, simpleType *xsdSimpleType) {
+	if err := simpleType.check(value); err != nil {
+		validator.report(path, err.keyword, value, "%s", err.message)
+	}
+}
+
+func (validator *xsdValidator) validateComplex(node *xmlNode, complexType *xsdComplexType) {
+	validator.validateAttributes(node, complexType)
+
+	if complexType.simple != nil {
+		if len(node.children) > 0 {
+			validator.report(node.children[0].path, "content", "", "element `%s` has simple content; child elements are not allowed", node.name.Local)
+		}
+		if complexType.simple.simple != nil {
+			validator.validateText(node.path, node.text.String(), complexType.simple.simple)
+		}
+		return
+	}
+
+	if !complexType.mixed && strings.TrimSpace(node.text.String()) != "" {
+		validator.report(node.path, "content", strings.TrimSpace(node.text.String()), "text is not allowed in element `%s` (element-only content)", node.name.Local)
+	}
+	validator.validateContent(node, complexType)
+
+	for _, child := range node.children {
+		if element, ok := complexType.elements[child.name]; ok {
+			validator.validateElement(child, element)
+		} else {
+			validator.validateWildcard(child, complexType)
+		}
+	}
+}
+
+// An element matched by a wildcard is validated if it is (globally) declared;
+// a strict wildcard requires the declaration, a lax wildcard does not.
+func (validator *xsdValidator) validateWildcard(node *xmlNode, complexType *xsdComplexType) {
+	for _, wildcard := range complexType.wildcards {
+		if !wildcard.allows(node.name.Space) {
+			continue
+		}
+		if element, ok := validator.schema.elements[node.name]; ok && wildcard.processContents != "skip" {
+			validator.validateElement(node, element)
+		} else if !ok && wildcard.processContents == "strict" {
+			validator.report(node.path, "any", "", "no declaration found for element `%s`", formatName(node.name))
+		}
+		return
+	}
+}
+
+func (validator *xsdValidator) validateAttributes(node *xmlNode, complexType *xsdComplexType) {
+	found := make(map[string]bool)
+	for _, attr := range node.attrs {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == XSI_NAMESPACE {
+			continue
+		}
+		path := node.path + "/@" + attr.Name.Local
+
+		var declaration *xsdAttribute
+		if complexType != nil && attr.Name.Space == "" {
+			for _, attribute := range complexType.attributes {
+				if attribute.name == attr.Name.Local {
+					declaration = attribute
+				}
+			}
+		}
+		switch {
+		case declaration != nil:
+			found[declaration.name] = true
+			if declaration.typ.simple != nil {
+				validator.validateText(path, attr.Value, declaration.typ.simple)
+			}
+		case complexType != nil && complexType.anyAttribute != nil && complexType.anyAttribute.allows(attr.Name.Space):
+		default:
+			validator.report(path, "attribute", attr.Value, "attribute `%s` is not allowed", formatName(attr.Name))
+		}
+	}
+
+	if complexType != nil {
+		for _, attribute := range complexType.attributes {
+			if attribute.required && !found[attribute.name] {
+				validator.report(node.path, "required", "", "missing required attribute `%s`", attribute.name)
+			}
+		}
+	}
+}
+
+func (wildcard xsdWildcard) allows(namespace string) bool {
+	for _, constraint := range strings.Fields(wildcard.namespace) {
+		switch constraint {
+		case "##any":
+			return true
+		case "##other":
+			if namespace != "" && namespace != wildcard.targetNamespace {
+				return true
+			}
+		case "##targetNamespace":
+			if namespace == wildcard.targetNamespace {
+				return true
+			}
+		case "##local":
+			if namespace == "" {
+				return true
+			}
+		default:
+			if namespace == constraint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Match the child elements against the content model; on failure, report
+// the first child that cannot be matched (or the missing element).
+func (validator *xsdValidator) validateContent(node *xmlNode, complexType *xsdComplexType) {
+	if complexType.content == nil {
+		if len(node.children) > 0 {
+			validator.report(node.children[0].path, "content", "", "unexpected element `%s`; element `%s` must be empty",
+				node.children[0].name.Local, node.name.Local)
+		}
+		return
+	}
+
+	matcher := &xsdMatcher{
+		validator: validator,
+		children:  node.children,
+		expected:  make(map[int][]string),
+	}
+	end := matcher.match(complexType.content, positions{0: true})
+	if end[len(node.children)] {
+		return
+	}
+
+	position := matcher.furthest
+	expected := strings.Join(matcher.expected[position], ", ")
+	if position < len(node.children) {
+		child := node.children[position]
+		if expected == "" {
+			validator.report(child.path, "content", "", "unexpected element `%s`", child.name.Local)
+		} else {
+			validator.report(child.path, "content", "", "unexpected element `%s`; expected one of: %s", child.name.Local, expected)
+		}
+	} else {
+		validator.report(node.path, "content", "", "missing required element; expected one of: %s", expected)
+	}
+}
+
+// A set of positions (i.e., indexes of child elements)
+type positions map[int]bool
+
+// A (non-deterministic) content model matcher: each particle is matched from
+// every position reached so far, which results in the set of positions reached.
+type xsdMatcher struct {
+	validator *xsdValidator
+	children  []*xmlNode
+	furthest  int              // the first child no particle has (yet) matched
+	expected  map[int][]string // the elements expected at each (failed) position
+}
+
+func (matcher *xsdMatcher) expect(position int, name string) {
+	if !contains(matcher.expected[position], name) {
+		matcher.expected[position] = append(matcher.expected[position], name)
+	}
+}
+
+func (matcher *xsdMatcher) consume(position int, result positions) {
+	result[position+1] = true
+	if position+1 > matcher.furthest {
+		matcher.furthest = position + 1
+	}
+}
+
+func (matcher *xsdMatcher) match(particle *xsdParticle, from positions) positions {
+	result, seen := positions{}, positions{}
+	current := from
+	for count := 0; len(current) > 0; count++ {
+		if count >= particle.min {
+			// positions already reached (at least min times) are not repeated
+			fresh := positions{}
+			for position := range current {
+				if !seen[position] {
+					seen[position], result[position], fresh[position] = true, true, true
+				}
+			}
+			if current = fresh; len(current) == 0 {
+				break
+			}
+		}
+		if particle.max != UNBOUNDED && count == particle.max {
+			break
+		}
+		current = matcher.matchOnce(particle, current)
+	}
+	return result
+}
+
+func (matcher *xsdMatcher) matchOnce(particle *xsdParticle, from positions) positions {
+	result := positions{}
+	switch particle.kind {
+	case "element":
+		for position := range from {
+			if position < len(matcher.children) && matcher.children[position].name == particle.element.name {
+				matcher.consume(position, result)
+			} else {
+				matcher.expect(position, particle.element.name.Local)
+			}
+		}
+	case "any":
+		for position := range from {
+			if position < len(matcher.children) && particle.wildcard.allows(matcher.children[position].name.Space) {
+				matcher.consume(position, result)
+			}
+		}
+	case "sequence":
+		result = from
+		for _, subParticle := range particle.particles {
+			result = matcher.match(subParticle, result)
+		}
+	case "choice":
+		for _, subParticle := range particle.particles {
+			for position := range matcher.match(subParticle, from) {
+				result[position] = true
+			}
+		}
+	}
+	return result
+}
+
+func (validator *xsdValidator) validateUnique(node *xmlNode, unique xsdUnique) {
+	values := make(map[string]bool)
+	var walk func(*xmlNode)
+	walk = func(current *xmlNode) {
+		for _, child := range current.children {
+			for _, attr := range child.attrs {
+				if attr.Name.Space == "" && attr.Name.Local == unique.attribute {
+					if values[attr.Value] {
+						validator.report(child.path+"/@"+unique.attribute, "unique", attr.Value,
+							"duplicate value `%s` for unique constraint `%s`", attr.Value, unique.name)
+					}
+					values[attr.Value] = true
+				}
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+}
@@ -0,0 +1,152 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testXSD = `<?xml version="1.0"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:t="urn:test"
+    targetNamespace="urn:test" elementFormDefault="qualified">
+  <xs:simpleType name="kind">
+    <xs:restriction base="xs:string">
+      <xs:enumeration value="library"/>
+      <xs:enumeration value="application"/>
+    </xs:restriction>
+  </xs:simpleType>
+  <xs:complexType name="item">
+    <xs:sequence>
+      <xs:element name="name" type="xs:normalizedString"/>
+      <xs:element name="version" type="xs:string" minOccurs="0"/>
+      <xs:element name="size" type="xs:nonNegativeInteger" minOccurs="0"/>
+      <xs:any namespace="##other" processContents="lax" minOccurs="0" maxOccurs="unbounded"/>
+    </xs:sequence>
+    <xs:attribute name="kind" type="t:kind" use="required"/>
+    <xs:attribute name="id" type="xs:string"/>
+  </xs:complexType>
+  <xs:element name="items">
+    <xs:complexType>
+      <xs:choice minOccurs="0" maxOccurs="unbounded">
+        <xs:element name="item" type="t:item"/>
+      </xs:choice>
+      <xs:attribute name="version" type="xs:positiveInteger" default="1"/>
+    </xs:complexType>
+    <xs:unique name="id">
+      <xs:selector xpath=".//*"/>
+      <xs:field xpath="@id"/>
+    </xs:unique>
+  </xs:element>
+</xs:schema>`
+
+func compileTestXSD(t *testing.T) *XMLSchema {
+	xmlSchema, err := CompileXSD(func(location string) ([]byte, error) {
+		return []byte(testXSD), nil
+	}, "test.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return xmlSchema
+}
+
+func TestXSDValidate(t *testing.T) {
+	xmlSchema := compileTestXSD(t)
+
+	tests := []struct {
+		document string
+		errors   []XMLError
+	}{
+		{`<items xmlns="urn:test" version="2">
+		    <item kind="library" id="a"><name>acme</name><version>1.0</version></item>
+		    <item kind="application" id="b"><name>tool</name><x:extra xmlns:x="urn:other"/></item>
+		  </items>`, nil},
+		{`<items xmlns="urn:test"><item kind="unknown"><name>acme</name></item></items>`, []XMLError{
+			{"/items/item/@kind", "enumeration", "value must be one of: library, application", "unknown"},
+		}},
+		{`<items xmlns="urn:test"><item><name>acme</name></item></items>`, []XMLError{
+			{"/items/item", "required", "missing required attribute `kind`", ""},
+		}},
+		{`<items xmlns="urn:test"><item kind="library"><version>1.0</version></item></items>`, []XMLError{
+			{"/items/item/version", "content", "unexpected element `version`; expected one of: name", ""},
+		}},
+		{`<items xmlns="urn:test"><item kind="library"><name>acme</name><size>-1</size></item></items>`, []XMLError{
+			{"/items/item/size", "minInclusive", "value must be greater than or equal to 0", "-1"},
+		}},
+		{`<items xmlns="urn:test" version="0"/>`, []XMLError{
+			{"/items/@version", "minInclusive", "value must be greater than or equal to 1", "0"},
+		}},
+		{`<items xmlns="urn:test">
+		    <item kind="library" id="a"><name>acme</name></item>
+		    <item kind="library" id="a"><name>acme</name></item>
+		  </items>`, []XMLError{
+			{"/items/item[2]/@id", "unique", "", "a"},
+		}},
+	}
+	for _, test := range tests {
+		xmlErrors, err := xmlSchema.Validate(strings.NewReader(test.document))
+		assert.NoError(t, err)
+		assert.Equal(t, len(test.errors), len(xmlErrors), "%s: %v", test.document, xmlErrors)
+		for i := 0; i < len(test.errors) && i < len(xmlErrors); i++ {
+			assert.Equal(t, test.errors[i].Path, xmlErrors[i].Path)
+			assert.Equal(t, test.errors[i].Keyword, xmlErrors[i].Keyword)
+			if test.errors[i].Message != "" {
+				assert.Equal(t, test.errors[i].Message, xmlErrors[i].Message)
+			}
+			assert.Equal(t, test.errors[i].Value, xmlErrors[i].Value)
+		}
+	}
+}
+
+func TestXSDValidateMalformed(t *testing.T) {
+	xmlSchema := compileTestXSD(t)
+	_, err := xmlSchema.Validate(strings.NewReader(`<items xmlns="urn:test"><item>`))
+	assert.Error(t, err)
+}
+
+// Constructs outside the supported subset must fail to compile
+func TestXSDUnsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		message string
+	}{
+		{"group", `<xs:group name="g"><xs:sequence/></xs:group>`, "xs:group"},
+		{"substitution group", `<xs:element name="a" type="xs:string" substitutionGroup="b"/>`, "substitutionGroup"},
+		{"fixed element", `<xs:element name="a" type="xs:string" fixed="x"/>`, "fixed"},
+		{"nillable element", `<xs:element name="a" type="xs:string" nillable="true"/>`, "nillable"},
+		{"fixed attribute", `<xs:complexType name="t"><xs:attribute name="a" type="xs:string" fixed="x"/></xs:complexType>`, "fixed"},
+		{"process contents", `<xs:complexType name="t"><xs:sequence><xs:any processContents="bogus"/></xs:sequence></xs:complexType>`, "processContents"},
+		{"facet", `<xs:simpleType name="t"><xs:restriction base="xs:decimal"><xs:totalDigits value="2"/></xs:restriction></xs:simpleType>`, "totalDigits"},
+		{"complex content", `<xs:complexType name="t"><xs:complexContent><xs:extension base="u"/></xs:complexContent></xs:complexType>`, "complexContent"},
+		{"attribute form", `<xs:complexType name="t"><xs:attribute name="a" type="xs:string" form="qualified"/></xs:complexType>`, "form"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			unsupported := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">` + test.schema + `</xs:schema>`
+			_, err := CompileXSD(func(location string) ([]byte, error) {
+				return []byte(unsupported), nil
+			}, "unsupported.xsd")
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), test.message)
+			}
+		})
+	}
+}
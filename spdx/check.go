@@ -0,0 +1,232 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Semantic rules (i.e., those a JSON schema cannot express)
+const (
+	RULE_DOCUMENT_SPDXID    = "document-spdxid"
+	RULE_DATA_LICENSE       = "data-license"
+	RULE_SPDXID             = "spdxid"
+	RULE_ELEMENT_REFERENCE  = "element-reference"
+	RULE_LICENSE_REFERENCE  = "license-reference"
+	RULE_FILES_ANALYZED     = "files-analyzed"
+	RULE_EXTERNAL_REFERENCE = "external-document-reference"
+)
+
+// A semantic problem found in a document
+type Issue struct {
+	Pointer string // JSON pointer (RFC 6901) to the offending value
+	Rule    string
+	Message string
+}
+
+func (issue Issue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", issue.Pointer, issue.Message, issue.Rule)
+}
+
+type checker struct {
+	document     *Document
+	elements     map[string]bool
+	externalRefs map[string]bool
+	licenseRefs  map[string]bool
+	issues       []Issue
+}
+
+// Check verifies the document's internal consistency; for example, that
+// SPDXIDs are unique and that relationships reference declared elements
+func (document *Document) Check() []Issue {
+	checker := &checker{
+		document:     document,
+		elements:     map[string]bool{document.SPDXID: true},
+		externalRefs: map[string]bool{},
+		licenseRefs:  map[string]bool{},
+	}
+	checker.checkDocument()
+	checker.checkElements()
+	checker.checkReferences()
+	checker.checkLicenses()
+	return checker.issues
+}
+
+func (checker *checker) report(pointer string, rule string, format string, a ...interface{}) {
+	checker.issues = append(checker.issues, Issue{
+		Pointer: pointer,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (checker *checker) checkDocument() {
+	document := checker.document
+	if document.SPDXID != SPDXID_DOCUMENT {
+		checker.report("/SPDXID", RULE_DOCUMENT_SPDXID, "document SPDXID must be `%s`; found: `%s`", SPDXID_DOCUMENT, document.SPDXID)
+	}
+	if document.DataLicense != DEFAULT_DATA_LICENSE {
+		checker.report("/dataLicense", RULE_DATA_LICENSE, "data license must be `%s`; found: `%s`", DEFAULT_DATA_LICENSE, document.DataLicense)
+	}
+	for i, ref := range document.ExternalDocumentRefs {
+		if checker.externalRefs[ref.ExternalDocumentId] {
+			checker.report(fmt.Sprintf("/externalDocumentRefs/%d/externalDocumentId", i), RULE_EXTERNAL_REFERENCE,
+				"duplicate external document reference: `%s`", ref.ExternalDocumentId)
+		}
+		checker.externalRefs[ref.ExternalDocumentId] = true
+	}
+}
+
+// Register the SPDXIDs of all packages, files and snippets
+func (checker *checker) checkElements() {
+	declare := func(pointer string, spdxId string) {
+		if !strings.HasPrefix(spdxId, SPDXID_PREFIX) {
+			checker.report(pointer, RULE_SPDXID, "SPDXID must start with `%s`; found: `%s`", SPDXID_PREFIX, spdxId)
+		} else if checker.elements[spdxId] {
+			checker.report(pointer, RULE_SPDXID, "duplicate SPDXID: `%s`", spdxId)
+		}
+		checker.elements[spdxId] = true
+	}
+
+	for i, pkg := range checker.document.Packages {
+		declare(fmt.Sprintf("/packages/%d/SPDXID", i), pkg.SPDXID)
+		if pkg.FilesAnalyzed != nil && !*pkg.FilesAnalyzed {
+			if len(pkg.HasFiles) > 0 {
+				checker.report(fmt.Sprintf("/packages/%d/hasFiles", i), RULE_FILES_ANALYZED,
+					"package `%s` has files although `filesAnalyzed` is false", pkg.SPDXID)
+			}
+			if pkg.PackageVerificationCode != nil {
+				checker.report(fmt.Sprintf("/packages/%d/packageVerificationCode", i), RULE_FILES_ANALYZED,
+					"package `%s` has a verification code although `filesAnalyzed` is false", pkg.SPDXID)
+			}
+		}
+	}
+	for i, file := range checker.document.Files {
+		declare(fmt.Sprintf("/files/%d/SPDXID", i), file.SPDXID)
+	}
+	for i, snippet := range checker.document.Snippets {
+		declare(fmt.Sprintf("/snippets/%d/SPDXID", i), snippet.SPDXID)
+	}
+}
+
+// Verify that every element reference resolves to a declared element or,
+// for "DocumentRef-x:SPDXRef-y" references, to a declared external document
+func (checker *checker) checkReferences() {
+	document := checker.document
+	for i, spdxId := range document.DocumentDescribes {
+		checker.checkReference(fmt.Sprintf("/documentDescribes/%d", i), spdxId, false)
+	}
+	for i, pkg := range document.Packages {
+		for j, spdxId := range pkg.HasFiles {
+			if !checker.isLocalFile(spdxId) {
+				checker.report(fmt.Sprintf("/packages/%d/hasFiles/%d", i, j), RULE_ELEMENT_REFERENCE,
+					"package `%s` references an undeclared file: `%s`", pkg.SPDXID, spdxId)
+			}
+		}
+	}
+	for i, snippet := range document.Snippets {
+		if !checker.isLocalFile(snippet.SnippetFromFile) {
+			checker.report(fmt.Sprintf("/snippets/%d/snippetFromFile", i), RULE_ELEMENT_REFERENCE,
+				"snippet `%s` references an undeclared file: `%s`", snippet.SPDXID, snippet.SnippetFromFile)
+		}
+	}
+	for i, relationship := range document.Relationships {
+		pointer := fmt.Sprintf("/relationships/%d", i)
+		checker.checkReference(pointer+"/spdxElementId", relationship.SPDXElementID, false)
+		checker.checkReference(pointer+"/relatedSpdxElement", relationship.RelatedSPDXElement, true)
+	}
+}
+
+func (checker *checker) isLocalFile(spdxId string) bool {
+	return checker.document.File(spdxId) != nil
+}
+
+func (checker *checker) checkReference(pointer string, spdxId string, allowSpecial bool) {
+	if allowSpecial && (spdxId == NONE || spdxId == NOASSERTION) {
+		return
+	}
+	if documentRef, _, found := cut(spdxId, ":"); found && strings.HasPrefix(documentRef, DOCUMENT_REF_PREFIX) {
+		if !checker.externalRefs[documentRef] {
+			checker.report(pointer, RULE_EXTERNAL_REFERENCE, "undeclared external document reference: `%s`", documentRef)
+		}
+		return
+	}
+	if !checker.elements[spdxId] {
+		checker.report(pointer, RULE_ELEMENT_REFERENCE, "reference to an undeclared element: `%s`", spdxId)
+	}
+}
+
+// Verify that every "LicenseRef-" used in a license field is declared
+// (once) by the document's extracted licensing information
+func (checker *checker) checkLicenses() {
+	document := checker.document
+	for i, info := range document.HasExtractedLicensingInfos {
+		if checker.licenseRefs[info.LicenseId] {
+			checker.report(fmt.Sprintf("/hasExtractedLicensingInfos/%d/licenseId", i), RULE_LICENSE_REFERENCE,
+				"duplicate license identifier: `%s`", info.LicenseId)
+		}
+		checker.licenseRefs[info.LicenseId] = true
+	}
+
+	for i, pkg := range document.Packages {
+		pointer := fmt.Sprintf("/packages/%d", i)
+		checker.checkLicense(pointer+"/licenseConcluded", pkg.LicenseConcluded)
+		checker.checkLicense(pointer+"/licenseDeclared", pkg.LicenseDeclared)
+		for j, license := range pkg.LicenseInfoFromFiles {
+			checker.checkLicense(fmt.Sprintf("%s/licenseInfoFromFiles/%d", pointer, j), license)
+		}
+	}
+	for i, file := range document.Files {
+		pointer := fmt.Sprintf("/files/%d", i)
+		checker.checkLicense(pointer+"/licenseConcluded", file.LicenseConcluded)
+		for j, license := range file.LicenseInfoInFiles {
+			checker.checkLicense(fmt.Sprintf("%s/licenseInfoInFiles/%d", pointer, j), license)
+		}
+	}
+	for i, snippet := range document.Snippets {
+		pointer := fmt.Sprintf("/snippets/%d", i)
+		checker.checkLicense(pointer+"/licenseConcluded", snippet.LicenseConcluded)
+		for j, license := range snippet.LicenseInfoInSnippets {
+			checker.checkLicense(fmt.Sprintf("%s/licenseInfoInSnippets/%d", pointer, j), license)
+		}
+	}
+}
+
+func (checker *checker) checkLicense(pointer string, expression string) {
+	for _, id := range LicenseRefs(expression) {
+		if !checker.licenseRefs[id] {
+			checker.report(pointer, RULE_LICENSE_REFERENCE, "undeclared license reference: `%s`", id)
+		}
+	}
+}
+
+// LicenseRefs returns the (local) "LicenseRef-" identifiers used within a
+// license expression; references to other documents (i.e., prefixed by
+// "DocumentRef-x:") are not returned
+func LicenseRefs(expression string) (ids []string) {
+	fields := strings.FieldsFunc(expression, func(r rune) bool {
+		return r == ' ' || r == '(' || r == ')' || r == '\t' || r == '\n'
+	})
+	for _, field := range fields {
+		if strings.HasPrefix(field, LICENSE_REF_PREFIX) {
+			ids = append(ids, field)
+		}
+	}
+	return
+}
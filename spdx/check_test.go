@@ -0,0 +1,77 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTagValueExamples(t *testing.T) {
+	for _, filename := range []string{
+		"testdata/SPDXTagExample-v2.2.spdx",
+		"testdata/SPDXTagExample-v2.3.spdx",
+		"testdata/hello.spdx",
+		"testdata/external-document-refs.spdx",
+	} {
+		assert.Empty(t, parseTagValueFile(t, filename).Check(), filename)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	analyzed := false
+	document := &Document{
+		SPDXVersion: "SPDX-2.3",
+		DataLicense: "CC-BY-4.0",
+		SPDXID:      SPDXID_DOCUMENT,
+		Packages: []*Package{
+			{SPDXID: "SPDXRef-A", LicenseDeclared: "MIT OR (LicenseRef-1 AND LicenseRef-missing)"},
+			{SPDXID: "SPDXRef-A", FilesAnalyzed: &analyzed, HasFiles: []string{"SPDXRef-F"}},
+		},
+		Files: []*File{{SPDXID: "F"}},
+		Relationships: []*Relationship{
+			{SPDXElementID: SPDXID_DOCUMENT, RelationshipType: RELATIONSHIP_DESCRIBES, RelatedSPDXElement: "SPDXRef-A"},
+			{SPDXElementID: "SPDXRef-A", RelationshipType: RELATIONSHIP_DEPENDS, RelatedSPDXElement: "DocumentRef-x:SPDXRef-B"},
+			{SPDXElementID: "SPDXRef-A", RelationshipType: RELATIONSHIP_CONTAINS, RelatedSPDXElement: NOASSERTION},
+			{SPDXElementID: "SPDXRef-Z", RelationshipType: RELATIONSHIP_CONTAINS, RelatedSPDXElement: "SPDXRef-A"},
+		},
+		HasExtractedLicensingInfos: []*ExtractedLicensingInfo{{LicenseId: "LicenseRef-1"}},
+	}
+
+	var issues []string
+	for _, issue := range document.Check() {
+		issues = append(issues, issue.Pointer+" "+issue.Rule)
+	}
+	assert.Equal(t, []string{
+		"/dataLicense data-license",
+		"/packages/1/SPDXID spdxid",
+		"/packages/1/hasFiles files-analyzed",
+		"/files/0/SPDXID spdxid",
+		"/packages/1/hasFiles/0 element-reference",
+		"/relationships/1/relatedSpdxElement external-document-reference",
+		"/relationships/3/spdxElementId element-reference",
+		"/packages/0/licenseDeclared license-reference",
+	}, issues)
+}
+
+func TestLicenseRefs(t *testing.T) {
+	assert.Equal(t, []string{"LicenseRef-a", "LicenseRef-b"},
+		LicenseRefs("(MIT AND LicenseRef-a) OR LicenseRef-b WITH x OR DocumentRef-d:LicenseRef-c"))
+	assert.Empty(t, LicenseRefs(NOASSERTION))
+}
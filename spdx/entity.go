@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Entity types used by creators, suppliers, originators and annotators
+const (
+	ENTITY_PERSON       = "Person"
+	ENTITY_ORGANIZATION = "Organization"
+	ENTITY_TOOL         = "Tool"
+)
+
+// An entity value of the form "<Type>: <Name> (<Email>)", where
+// the email is optional (and never present for tools)
+type Entity struct {
+	Type  string
+	Name  string
+	Email string
+}
+
+// ParseEntity parses an entity value (e.g., "Person: Jane Doe (jane@example.com)");
+// it returns false for values without a type (e.g., NOASSERTION)
+func ParseEntity(value string) (entity Entity, ok bool) {
+	entityType, rest, found := cut(value, ":")
+	if !found {
+		return
+	}
+	entity.Type = strings.TrimSpace(entityType)
+	if entity.Type != ENTITY_PERSON && entity.Type != ENTITY_ORGANIZATION && entity.Type != ENTITY_TOOL {
+		return Entity{}, false
+	}
+
+	entity.Name = strings.TrimSpace(rest)
+	if entity.Type != ENTITY_TOOL && strings.HasSuffix(entity.Name, ")") {
+		if open := strings.LastIndex(entity.Name, "("); open >= 0 {
+			entity.Email = strings.TrimSpace(entity.Name[open+1 : len(entity.Name)-1])
+			entity.Name = strings.TrimSpace(entity.Name[:open])
+		}
+	}
+	return entity, true
+}
+
+func (entity Entity) String() string {
+	if entity.Email != "" {
+		return fmt.Sprintf("%s: %s (%s)", entity.Type, entity.Name, entity.Email)
+	}
+	return fmt.Sprintf("%s: %s", entity.Type, entity.Name)
+}
@@ -0,0 +1,309 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParseJSON reads an SPDX 2.2 or 2.3 JSON document
+func ParseJSON(reader io.Reader) (*Document, error) {
+	document := new(Document)
+	decoder := json.NewDecoder(reader)
+	if err := decoder.Decode(document); err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// WriteJSON writes the document as (indented) SPDX JSON
+func (document *Document) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document)
+}
+
+// ------------------------------------------------------------------------
+// Overflow ("Extra") properties
+// ------------------------------------------------------------------------
+
+// The JSON property names declared (by struct tags) for each model type
+var knownProperties sync.Map // reflect.Type -> map[string]bool
+
+func propertyNames(t reflect.Type) map[string]bool {
+	if names, ok := knownProperties.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	knownProperties.Store(t, names)
+	return names
+}
+
+// Decode the known properties into value (which must be a pointer to a
+// struct without JSON methods) and collect all others into extra
+func unmarshalExtra(data []byte, value interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, value); err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return err
+	}
+	known := propertyNames(reflect.TypeOf(value).Elem())
+	for name, property := range properties {
+		if !known[name] {
+			if *extra == nil {
+				*extra = map[string]json.RawMessage{}
+			}
+			(*extra)[name] = property
+		}
+	}
+	return nil
+}
+
+// Encode the known properties of value, followed by the extra
+// properties (sorted by name so that output is deterministic)
+func marshalExtra(value interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := marshalUnescaped(value)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := propertyNames(reflect.TypeOf(value))
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		// known properties always take precedence
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	buffer.Write(data[:len(data)-1]) // i.e., without the closing "}"
+	for _, name := range names {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		key, _ := marshalUnescaped(name)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(extra[name])
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// Like json.Marshal(), but without escaping "<", ">" and "&" (as "\u003c",
+// etc.); otherwise, (re-)written documents would differ from those read
+func marshalUnescaped(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// Each model type is converted to a method-less ("plain") type of the same
+// structure so that the standard encoding can be reused without recursion.
+
+func (document *Document) UnmarshalJSON(data []byte) error {
+	type plain Document
+	return unmarshalExtra(data, (*plain)(document), &document.Extra)
+}
+
+func (document Document) MarshalJSON() ([]byte, error) {
+	type plain Document
+	return marshalExtra(plain(document), document.Extra)
+}
+
+func (info *CreationInfo) UnmarshalJSON(data []byte) error {
+	type plain CreationInfo
+	return unmarshalExtra(data, (*plain)(info), &info.Extra)
+}
+
+func (info CreationInfo) MarshalJSON() ([]byte, error) {
+	type plain CreationInfo
+	return marshalExtra(plain(info), info.Extra)
+}
+
+func (ref *ExternalDocumentRef) UnmarshalJSON(data []byte) error {
+	type plain ExternalDocumentRef
+	return unmarshalExtra(data, (*plain)(ref), &ref.Extra)
+}
+
+func (ref ExternalDocumentRef) MarshalJSON() ([]byte, error) {
+	type plain ExternalDocumentRef
+	return marshalExtra(plain(ref), ref.Extra)
+}
+
+func (checksum *Checksum) UnmarshalJSON(data []byte) error {
+	type plain Checksum
+	return unmarshalExtra(data, (*plain)(checksum), &checksum.Extra)
+}
+
+func (checksum Checksum) MarshalJSON() ([]byte, error) {
+	type plain Checksum
+	return marshalExtra(plain(checksum), checksum.Extra)
+}
+
+func (pkg *Package) UnmarshalJSON(data []byte) error {
+	type plain Package
+	return unmarshalExtra(data, (*plain)(pkg), &pkg.Extra)
+}
+
+func (pkg Package) MarshalJSON() ([]byte, error) {
+	type plain Package
+	return marshalExtra(plain(pkg), pkg.Extra)
+}
+
+func (code *PackageVerificationCode) UnmarshalJSON(data []byte) error {
+	type plain PackageVerificationCode
+	return unmarshalExtra(data, (*plain)(code), &code.Extra)
+}
+
+func (code PackageVerificationCode) MarshalJSON() ([]byte, error) {
+	type plain PackageVerificationCode
+	return marshalExtra(plain(code), code.Extra)
+}
+
+func (ref *ExternalRef) UnmarshalJSON(data []byte) error {
+	type plain ExternalRef
+	return unmarshalExtra(data, (*plain)(ref), &ref.Extra)
+}
+
+func (ref ExternalRef) MarshalJSON() ([]byte, error) {
+	type plain ExternalRef
+	return marshalExtra(plain(ref), ref.Extra)
+}
+
+func (file *File) UnmarshalJSON(data []byte) error {
+	type plain File
+	return unmarshalExtra(data, (*plain)(file), &file.Extra)
+}
+
+func (file File) MarshalJSON() ([]byte, error) {
+	type plain File
+	return marshalExtra(plain(file), file.Extra)
+}
+
+func (artifact *ArtifactOf) UnmarshalJSON(data []byte) error {
+	type plain ArtifactOf
+	return unmarshalExtra(data, (*plain)(artifact), &artifact.Extra)
+}
+
+func (artifact ArtifactOf) MarshalJSON() ([]byte, error) {
+	type plain ArtifactOf
+	return marshalExtra(plain(artifact), artifact.Extra)
+}
+
+func (snippet *Snippet) UnmarshalJSON(data []byte) error {
+	type plain Snippet
+	return unmarshalExtra(data, (*plain)(snippet), &snippet.Extra)
+}
+
+func (snippet Snippet) MarshalJSON() ([]byte, error) {
+	type plain Snippet
+	return marshalExtra(plain(snippet), snippet.Extra)
+}
+
+func (snippetRange *SnippetRange) UnmarshalJSON(data []byte) error {
+	type plain SnippetRange
+	return unmarshalExtra(data, (*plain)(snippetRange), &snippetRange.Extra)
+}
+
+func (snippetRange SnippetRange) MarshalJSON() ([]byte, error) {
+	type plain SnippetRange
+	return marshalExtra(plain(snippetRange), snippetRange.Extra)
+}
+
+func (pointer *SnippetPointer) UnmarshalJSON(data []byte) error {
+	type plain SnippetPointer
+	return unmarshalExtra(data, (*plain)(pointer), &pointer.Extra)
+}
+
+func (pointer SnippetPointer) MarshalJSON() ([]byte, error) {
+	type plain SnippetPointer
+	return marshalExtra(plain(pointer), pointer.Extra)
+}
+
+func (relationship *Relationship) UnmarshalJSON(data []byte) error {
+	type plain Relationship
+	return unmarshalExtra(data, (*plain)(relationship), &relationship.Extra)
+}
+
+func (relationship Relationship) MarshalJSON() ([]byte, error) {
+	type plain Relationship
+	return marshalExtra(plain(relationship), relationship.Extra)
+}
+
+func (info *ExtractedLicensingInfo) UnmarshalJSON(data []byte) error {
+	type plain ExtractedLicensingInfo
+	return unmarshalExtra(data, (*plain)(info), &info.Extra)
+}
+
+func (info ExtractedLicensingInfo) MarshalJSON() ([]byte, error) {
+	type plain ExtractedLicensingInfo
+	return marshalExtra(plain(info), info.Extra)
+}
+
+func (crossRef *CrossRef) UnmarshalJSON(data []byte) error {
+	type plain CrossRef
+	return unmarshalExtra(data, (*plain)(crossRef), &crossRef.Extra)
+}
+
+func (crossRef CrossRef) MarshalJSON() ([]byte, error) {
+	type plain CrossRef
+	return marshalExtra(plain(crossRef), crossRef.Extra)
+}
+
+func (annotation *Annotation) UnmarshalJSON(data []byte) error {
+	type plain Annotation
+	return unmarshalExtra(data, (*plain)(annotation), &annotation.Extra)
+}
+
+func (annotation Annotation) MarshalJSON() ([]byte, error) {
+	type plain Annotation
+	return marshalExtra(plain(annotation), annotation.Extra)
+}
+
+func (review *Review) UnmarshalJSON(data []byte) error {
+	type plain Review
+	return unmarshalExtra(data, (*plain)(review), &review.Extra)
+}
+
+func (review Review) MarshalJSON() ([]byte, error) {
+	type plain Review
+	return marshalExtra(plain(review), review.Extra)
+}
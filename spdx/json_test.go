@@ -0,0 +1,159 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Decoding, then re-encoding, a document must not lose (or alter) any property
+func assertRoundTrip(t *testing.T, input []byte) *Document {
+	document, err := ParseJSON(bytes.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	if err = document.WriteJSON(&output); err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, actual interface{}
+	assert.NoError(t, json.Unmarshal(input, &expected))
+	assert.NoError(t, json.Unmarshal(output.Bytes(), &actual))
+	assert.Equal(t, expected, actual)
+	return document
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, filename := range []string{
+		"testdata/SPDXJSONExample-v2.2.spdx.json",
+		"testdata/SPDXJSONExample-v2.3.spdx.json",
+	} {
+		input, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		document := assertRoundTrip(t, input)
+		assert.Empty(t, document.Extra, filename)
+		assert.Empty(t, document.Check(), filename)
+	}
+}
+
+func TestJSONUnknownProperties(t *testing.T) {
+	input := `{
+		"spdxVersion": "SPDX-2.3", "dataLicense": "CC0-1.0", "SPDXID": "SPDXRef-DOCUMENT",
+		"name": "future", "documentNamespace": "https://example.com/future",
+		"creationInfo": {"creators": ["Tool: future-1.0"], "created": "2023-01-01T00:00:00Z", "x-build": {"id": 42}},
+		"x-vendor": ["a", {"b": 1.50}],
+		"packages": [{"SPDXID": "SPDXRef-A", "name": "a", "downloadLocation": "NONE", "x-large": 12345678901234567890}]
+	}`
+	document := assertRoundTrip(t, []byte(input))
+
+	assert.Equal(t, `["a", {"b": 1.50}]`, string(document.Extra["x-vendor"]))
+	assert.Equal(t, `{"id": 42}`, string(document.CreationInfo.Extra["x-build"]))
+	// numbers are kept verbatim (i.e., without float64 rounding)
+	assert.Equal(t, "12345678901234567890", string(document.Packages[0].Extra["x-large"]))
+
+	// known properties are never duplicated (or overridden) by "Extra"
+	document.Packages[0].Extra["name"] = json.RawMessage(`"b"`)
+	data, err := json.Marshal(document.Packages[0])
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), `"name"`))
+	assert.Contains(t, string(data), `"name":"a"`)
+}
+
+// "<", ">" and "&" are written as is (i.e., not escaped as "\u003c", etc.)
+func TestJSONRoundTripUnescaped(t *testing.T) {
+	input := `{
+  "spdxVersion": "SPDX-2.3",
+  "dataLicense": "CC0-1.0",
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "name": "a <b> & c",
+  "documentNamespace": "https://example.com/unescaped?a=1&b=2",
+  "creationInfo": {
+    "creators": [
+      "Tool: <future> & more"
+    ],
+    "created": "2023-01-01T00:00:00Z",
+    "x-note": "<b>bold</b> & \"quoted\""
+  },
+  "x-vendor": {
+    "<key>": "a & b"
+  }
+}
+`
+	document := assertRoundTrip(t, []byte(input))
+	var output bytes.Buffer
+	assert.NoError(t, document.WriteJSON(&output))
+	assert.Equal(t, input, output.String())
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	for _, test := range []struct{ yaml, json string }{
+		{"testdata/SPDXYAMLExample-2.2.spdx.yaml", "testdata/SPDXJSONExample-v2.2.spdx.json"},
+		{"testdata/SPDXYAMLExample-2.3.spdx.yaml", "testdata/SPDXJSONExample-v2.3.spdx.json"},
+	} {
+		file, err := os.Open(test.yaml)
+		if err != nil {
+			t.Fatal(err)
+		}
+		document, err := ParseYAML(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, document.Check(), test.yaml)
+
+		var output bytes.Buffer
+		assert.NoError(t, document.WriteYAML(&output))
+		reparsed, err := ParseYAML(&output)
+		assert.NoError(t, err)
+		assert.Equal(t, document, reparsed, test.yaml)
+	}
+}
+
+func TestYAMLToJSON(t *testing.T) {
+	input := "name: example\ncreated: 2010-01-29T18:30:22Z\nversion: \"1.0\"\ncount: 3\nratio: 0.5\nlive: true\nnone: ~\nlist:\n  - a\n"
+	data, err := YAMLToJSON([]byte(input))
+	assert.NoError(t, err)
+	// property order is preserved and timestamps remain strings
+	assert.Equal(t, `{"name":"example","created":"2010-01-29T18:30:22Z","version":"1.0","count":3,"ratio":0.5,"live":true,"none":null,"list":["a"]}`, string(data))
+
+	_, err = YAMLToJSON([]byte("? [a, b]\n: c\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteYAMLQuoting(t *testing.T) {
+	document := &Document{SPDXVersion: "SPDX-2.3", Name: "true", Packages: []*Package{{SPDXID: "SPDXRef-A", VersionInfo: "1.0"}}}
+	var output bytes.Buffer
+	assert.NoError(t, document.WriteYAML(&output))
+	assert.Contains(t, output.String(), `name: "true"`)
+	assert.Contains(t, output.String(), `versionInfo: "1.0"`)
+
+	reparsed, err := ParseYAML(&output)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", reparsed.Packages[0].VersionInfo)
+}
@@ -0,0 +1,262 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import "encoding/json"
+
+// Typed SPDX 2.x document model (versions 2.2 and 2.3)
+// Note: field names (and JSON property names) follow the SPDX JSON schema;
+// properties unknown to this model (e.g., from newer tools) are kept in
+// each type's "Extra" map so that documents round-trip without loss.
+
+// Special values allowed in place of many SPDX fields
+const (
+	NOASSERTION = "NOASSERTION"
+	NONE        = "NONE"
+)
+
+const (
+	SPDXID_DOCUMENT        = "SPDXRef-DOCUMENT"
+	SPDXID_PREFIX          = "SPDXRef-"
+	DOCUMENT_REF_PREFIX    = "DocumentRef-"
+	LICENSE_REF_PREFIX     = "LicenseRef-"
+	DEFAULT_DATA_LICENSE   = "CC0-1.0"
+	RELATIONSHIP_DESCRIBES = "DESCRIBES"
+	RELATIONSHIP_CONTAINS  = "CONTAINS"
+	RELATIONSHIP_DEPENDS   = "DEPENDS_ON"
+)
+
+type Document struct {
+	SPDXVersion                string                     `json:"spdxVersion"`
+	DataLicense                string                     `json:"dataLicense"`
+	SPDXID                     string                     `json:"SPDXID"`
+	Name                       string                     `json:"name"`
+	DocumentNamespace          string                     `json:"documentNamespace"`
+	ExternalDocumentRefs       []ExternalDocumentRef      `json:"externalDocumentRefs,omitempty"`
+	Comment                    string                     `json:"comment,omitempty"`
+	CreationInfo               CreationInfo               `json:"creationInfo"`
+	DocumentDescribes          []string                   `json:"documentDescribes,omitempty"`
+	Packages                   []*Package                 `json:"packages,omitempty"`
+	Files                      []*File                    `json:"files,omitempty"`
+	Snippets                   []*Snippet                 `json:"snippets,omitempty"`
+	Relationships              []*Relationship            `json:"relationships,omitempty"`
+	HasExtractedLicensingInfos []*ExtractedLicensingInfo  `json:"hasExtractedLicensingInfos,omitempty"`
+	Annotations                []*Annotation              `json:"annotations,omitempty"`
+	Reviews                    []*Review                  `json:"revieweds,omitempty"`
+	Extra                      map[string]json.RawMessage `json:"-"`
+}
+
+type CreationInfo struct {
+	LicenseListVersion string                     `json:"licenseListVersion,omitempty"`
+	Creators           []string                   `json:"creators"`
+	Created            string                     `json:"created"`
+	Comment            string                     `json:"comment,omitempty"`
+	Extra              map[string]json.RawMessage `json:"-"`
+}
+
+type ExternalDocumentRef struct {
+	ExternalDocumentId string                     `json:"externalDocumentId"`
+	SpdxDocument       string                     `json:"spdxDocument"`
+	Checksum           Checksum                   `json:"checksum"`
+	Extra              map[string]json.RawMessage `json:"-"`
+}
+
+type Checksum struct {
+	Algorithm     string                     `json:"algorithm"`
+	ChecksumValue string                     `json:"checksumValue"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type Package struct {
+	SPDXID                  string                     `json:"SPDXID"`
+	Name                    string                     `json:"name"`
+	VersionInfo             string                     `json:"versionInfo,omitempty"`
+	PackageFileName         string                     `json:"packageFileName,omitempty"`
+	Supplier                string                     `json:"supplier,omitempty"`
+	Originator              string                     `json:"originator,omitempty"`
+	DownloadLocation        string                     `json:"downloadLocation"`
+	FilesAnalyzed           *bool                      `json:"filesAnalyzed,omitempty"`
+	PackageVerificationCode *PackageVerificationCode   `json:"packageVerificationCode,omitempty"`
+	Checksums               []Checksum                 `json:"checksums,omitempty"`
+	Homepage                string                     `json:"homepage,omitempty"`
+	SourceInfo              string                     `json:"sourceInfo,omitempty"`
+	LicenseConcluded        string                     `json:"licenseConcluded,omitempty"`
+	LicenseInfoFromFiles    []string                   `json:"licenseInfoFromFiles,omitempty"`
+	LicenseDeclared         string                     `json:"licenseDeclared,omitempty"`
+	LicenseComments         string                     `json:"licenseComments,omitempty"`
+	CopyrightText           string                     `json:"copyrightText,omitempty"`
+	Summary                 string                     `json:"summary,omitempty"`
+	Description             string                     `json:"description,omitempty"`
+	Comment                 string                     `json:"comment,omitempty"`
+	ExternalRefs            []*ExternalRef             `json:"externalRefs,omitempty"`
+	AttributionTexts        []string                   `json:"attributionTexts,omitempty"`
+	PrimaryPackagePurpose   string                     `json:"primaryPackagePurpose,omitempty"` // 2.3
+	ReleaseDate             string                     `json:"releaseDate,omitempty"`           // 2.3
+	BuiltDate               string                     `json:"builtDate,omitempty"`             // 2.3
+	ValidUntilDate          string                     `json:"validUntilDate,omitempty"`        // 2.3
+	HasFiles                []string                   `json:"hasFiles,omitempty"`
+	Annotations             []*Annotation              `json:"annotations,omitempty"`
+	Extra                   map[string]json.RawMessage `json:"-"`
+}
+
+type PackageVerificationCode struct {
+	PackageVerificationCodeValue         string                     `json:"packageVerificationCodeValue"`
+	PackageVerificationCodeExcludedFiles []string                   `json:"packageVerificationCodeExcludedFiles,omitempty"`
+	Extra                                map[string]json.RawMessage `json:"-"`
+}
+
+type ExternalRef struct {
+	ReferenceCategory string                     `json:"referenceCategory"`
+	ReferenceType     string                     `json:"referenceType"`
+	ReferenceLocator  string                     `json:"referenceLocator"`
+	Comment           string                     `json:"comment,omitempty"`
+	Extra             map[string]json.RawMessage `json:"-"`
+}
+
+type File struct {
+	SPDXID             string                     `json:"SPDXID"`
+	FileName           string                     `json:"fileName"`
+	FileTypes          []string                   `json:"fileTypes,omitempty"`
+	Checksums          []Checksum                 `json:"checksums"`
+	LicenseConcluded   string                     `json:"licenseConcluded,omitempty"`
+	LicenseInfoInFiles []string                   `json:"licenseInfoInFiles,omitempty"`
+	LicenseComments    string                     `json:"licenseComments,omitempty"`
+	CopyrightText      string                     `json:"copyrightText,omitempty"`
+	Comment            string                     `json:"comment,omitempty"`
+	NoticeText         string                     `json:"noticeText,omitempty"`
+	FileContributors   []string                   `json:"fileContributors,omitempty"`
+	AttributionTexts   []string                   `json:"attributionTexts,omitempty"`
+	FileDependencies   []string                   `json:"fileDependencies,omitempty"` // deprecated
+	ArtifactOfs        []*ArtifactOf              `json:"artifactOfs,omitempty"`      // deprecated
+	Annotations        []*Annotation              `json:"annotations,omitempty"`
+	Extra              map[string]json.RawMessage `json:"-"`
+}
+
+// Deprecated (since SPDX 2.0); kept so that older documents parse
+type ArtifactOf struct {
+	Name     string                     `json:"name,omitempty"`
+	HomePage string                     `json:"homePage,omitempty"`
+	URI      string                     `json:"uri,omitempty"`
+	Extra    map[string]json.RawMessage `json:"-"`
+}
+
+type Snippet struct {
+	SPDXID                string                     `json:"SPDXID"`
+	Name                  string                     `json:"name,omitempty"`
+	SnippetFromFile       string                     `json:"snippetFromFile"`
+	Ranges                []*SnippetRange            `json:"ranges"`
+	LicenseConcluded      string                     `json:"licenseConcluded,omitempty"`
+	LicenseInfoInSnippets []string                   `json:"licenseInfoInSnippets,omitempty"`
+	LicenseComments       string                     `json:"licenseComments,omitempty"`
+	CopyrightText         string                     `json:"copyrightText,omitempty"`
+	Comment               string                     `json:"comment,omitempty"`
+	AttributionTexts      []string                   `json:"attributionTexts,omitempty"`
+	Annotations           []*Annotation              `json:"annotations,omitempty"`
+	Extra                 map[string]json.RawMessage `json:"-"`
+}
+
+type SnippetRange struct {
+	StartPointer SnippetPointer             `json:"startPointer"`
+	EndPointer   SnippetPointer             `json:"endPointer"`
+	Extra        map[string]json.RawMessage `json:"-"`
+}
+
+// Either Offset (bytes) or LineNumber is set
+type SnippetPointer struct {
+	Reference  string                     `json:"reference"`
+	Offset     *int                       `json:"offset,omitempty"`
+	LineNumber *int                       `json:"lineNumber,omitempty"`
+	Extra      map[string]json.RawMessage `json:"-"`
+}
+
+type Relationship struct {
+	SPDXElementID      string                     `json:"spdxElementId"`
+	RelationshipType   string                     `json:"relationshipType"`
+	RelatedSPDXElement string                     `json:"relatedSpdxElement"`
+	Comment            string                     `json:"comment,omitempty"`
+	Extra              map[string]json.RawMessage `json:"-"`
+}
+
+type ExtractedLicensingInfo struct {
+	LicenseId     string                     `json:"licenseId"`
+	ExtractedText string                     `json:"extractedText"`
+	Name          string                     `json:"name,omitempty"`
+	SeeAlsos      []string                   `json:"seeAlsos,omitempty"`
+	Comment       string                     `json:"comment,omitempty"`
+	CrossRefs     []CrossRef                 `json:"crossRefs,omitempty"` // JSON only
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type CrossRef struct {
+	URL           string                     `json:"url"`
+	IsLive        *bool                      `json:"isLive,omitempty"`
+	IsValid       *bool                      `json:"isValid,omitempty"`
+	IsWayBackLink *bool                      `json:"isWayBackLink,omitempty"`
+	Match         string                     `json:"match,omitempty"`
+	Order         *int                       `json:"order,omitempty"`
+	Timestamp     string                     `json:"timestamp,omitempty"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+type Annotation struct {
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Comment        string `json:"comment"`
+	// The annotated element; only serialized in tag-value ("SPDXREF"),
+	// JSON nests annotations within the element they annotate
+	SPDXElementID string                     `json:"-"`
+	Extra         map[string]json.RawMessage `json:"-"`
+}
+
+// Deprecated (since SPDX 2.0) in favor of annotations of type REVIEW
+type Review struct {
+	Reviewer   string                     `json:"reviewer,omitempty"`
+	ReviewDate string                     `json:"reviewDate"`
+	Comment    string                     `json:"comment,omitempty"`
+	Extra      map[string]json.RawMessage `json:"-"`
+}
+
+// Lookup helpers
+
+func (document *Document) Package(spdxId string) *Package {
+	for _, pkg := range document.Packages {
+		if pkg.SPDXID == spdxId {
+			return pkg
+		}
+	}
+	return nil
+}
+
+func (document *Document) File(spdxId string) *File {
+	for _, file := range document.Files {
+		if file.SPDXID == spdxId {
+			return file
+		}
+	}
+	return nil
+}
+
+func (document *Document) Snippet(spdxId string) *Snippet {
+	for _, snippet := range document.Snippets {
+		if snippet.SPDXID == spdxId {
+			return snippet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,883 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	TEXT_START = "<text>"
+	TEXT_END   = "</text>"
+)
+
+// Errors found while lexing or parsing are reported with the (first)
+// line number of the offending tag-value pair
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", err.Line, err.Message)
+}
+
+func newParseError(line int, format string, a ...interface{}) *ParseError {
+	return &ParseError{Line: line, Message: fmt.Sprintf(format, a...)}
+}
+
+// ------------------------------------------------------------------------
+// Lexer
+// ------------------------------------------------------------------------
+
+// A single "Tag: Value" pair; multi-line values are enclosed
+// in "<text>...</text>" which is removed from the Value
+type Token struct {
+	Tag   string
+	Value string
+	Line  int
+}
+
+// Lexer streams tag-value pairs from a reader, skipping blank lines and
+// comments (i.e., lines starting with "#") outside of <text> blocks
+type Lexer struct {
+	reader *bufio.Reader
+	line   int
+	eof    bool
+}
+
+func NewLexer(reader io.Reader) *Lexer {
+	return &Lexer{reader: bufio.NewReader(reader)}
+}
+
+// Read the next physical line (without line terminators)
+func (lexer *Lexer) readLine() (string, bool) {
+	if lexer.eof {
+		return "", false
+	}
+	line, err := lexer.reader.ReadString('\n')
+	if err != nil {
+		lexer.eof = true
+		if line == "" {
+			return "", false
+		}
+	}
+	lexer.line++
+	return strings.TrimRight(line, "\r\n"), true
+}
+
+// Next returns the next token or io.EOF once the input is exhausted
+func (lexer *Lexer) Next() (Token, error) {
+	for {
+		line, ok := lexer.readLine()
+		if !ok {
+			return Token{}, io.EOF
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		separator := strings.Index(line, ":")
+		if separator < 1 {
+			return Token{}, newParseError(lexer.line, "expected `Tag: Value`; found: `%s`", trimmed)
+		}
+
+		token := Token{
+			Tag:   strings.TrimSpace(line[:separator]),
+			Value: strings.TrimSpace(line[separator+1:]),
+			Line:  lexer.line,
+		}
+
+		if strings.HasPrefix(token.Value, TEXT_START) {
+			value, err := lexer.readText(token.Value[len(TEXT_START):], token.Line)
+			if err != nil {
+				return Token{}, err
+			}
+			token.Value = value
+		}
+		return token, nil
+	}
+}
+
+// Read (possibly) multiple lines until the closing </text>
+func (lexer *Lexer) readText(value string, startLine int) (string, error) {
+	var sb strings.Builder
+	for {
+		if end := strings.Index(value, TEXT_END); end >= 0 {
+			sb.WriteString(value[:end])
+			if trailing := strings.TrimSpace(value[end+len(TEXT_END):]); trailing != "" {
+				return "", newParseError(lexer.line, "unexpected content after %s: `%s`", TEXT_END, trailing)
+			}
+			return sb.String(), nil
+		}
+		sb.WriteString(value)
+
+		line, ok := lexer.readLine()
+		if !ok {
+			return "", newParseError(startLine, "unterminated %s block", TEXT_START)
+		}
+		sb.WriteByte('\n')
+		value = line
+	}
+}
+
+// ------------------------------------------------------------------------
+// Parser
+// ------------------------------------------------------------------------
+
+// Elements (sections) of a tag-value document; each starts with a specific tag
+const (
+	sectionDocument = iota
+	sectionPackage
+	sectionFile
+	sectionSnippet
+	sectionLicense
+	sectionRelationship
+	sectionAnnotation
+	sectionReview
+)
+
+type tagValueParser struct {
+	document     *Document
+	section      int
+	pkg          *Package
+	file         *File
+	snippet      *Snippet
+	license      *ExtractedLicensingInfo
+	relationship *Relationship
+	annotation   *Annotation
+	review       *Review
+	externalRef  *ExternalRef
+	artifactOf   *ArtifactOf
+	// files listed after a package belong to that package
+	fileOwners map[*File]*Package
+}
+
+// ParseTagValue reads an SPDX 2.x tag-value document into the typed model
+func ParseTagValue(reader io.Reader) (*Document, error) {
+	parser := &tagValueParser{
+		document:   &Document{},
+		fileOwners: make(map[*File]*Package),
+	}
+
+	lexer := NewLexer(reader)
+	for {
+		token, err := lexer.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = parser.parse(token); err != nil {
+			return nil, err
+		}
+	}
+
+	parser.finish()
+	return parser.document, nil
+}
+
+func (parser *tagValueParser) parse(token Token) error {
+	// Tags that start a new element (section)
+	switch token.Tag {
+	case "PackageName":
+		parser.pkg = &Package{Name: token.Value}
+		parser.document.Packages = append(parser.document.Packages, parser.pkg)
+		parser.file, parser.externalRef = nil, nil
+		parser.section = sectionPackage
+		return nil
+	case "FileName":
+		parser.file = &File{FileName: token.Value}
+		parser.document.Files = append(parser.document.Files, parser.file)
+		if parser.pkg != nil {
+			parser.fileOwners[parser.file] = parser.pkg
+		}
+		parser.artifactOf = nil
+		parser.section = sectionFile
+		return nil
+	case "SnippetSPDXID":
+		parser.snippet = &Snippet{SPDXID: token.Value}
+		parser.document.Snippets = append(parser.document.Snippets, parser.snippet)
+		parser.section = sectionSnippet
+		return nil
+	case "LicenseID":
+		parser.license = &ExtractedLicensingInfo{LicenseId: token.Value}
+		parser.document.HasExtractedLicensingInfos = append(parser.document.HasExtractedLicensingInfos, parser.license)
+		parser.section = sectionLicense
+		return nil
+	case "Relationship":
+		relationship, err := parseRelationship(token)
+		if err != nil {
+			return err
+		}
+		parser.relationship = relationship
+		parser.document.Relationships = append(parser.document.Relationships, relationship)
+		parser.section = sectionRelationship
+		return nil
+	case "Annotator":
+		parser.annotation = &Annotation{Annotator: token.Value}
+		parser.document.Annotations = append(parser.document.Annotations, parser.annotation)
+		parser.section = sectionAnnotation
+		return nil
+	case "Reviewer":
+		parser.review = &Review{Reviewer: token.Value}
+		parser.document.Reviews = append(parser.document.Reviews, parser.review)
+		parser.section = sectionReview
+		return nil
+	}
+
+	// "SPDXID" identifies the most recently started document, package or file
+	if token.Tag == "SPDXID" {
+		switch parser.section {
+		case sectionPackage:
+			parser.pkg.SPDXID = token.Value
+		case sectionFile:
+			parser.file.SPDXID = token.Value
+		default:
+			if parser.document.SPDXID != "" {
+				return newParseError(token.Line, "unexpected `SPDXID` (document identifier already set)")
+			}
+			parser.document.SPDXID = token.Value
+		}
+		return nil
+	}
+
+	if handler, ok := documentTags[token.Tag]; ok {
+		return handler(parser.document, token)
+	}
+	if handler, ok := packageTags[token.Tag]; ok {
+		if parser.pkg == nil || parser.section != sectionPackage {
+			return newParseError(token.Line, "tag `%s` found outside of a package (`PackageName`) section", token.Tag)
+		}
+		return handler(parser, token)
+	}
+	if handler, ok := fileTags[token.Tag]; ok {
+		if parser.file == nil || parser.section != sectionFile {
+			return newParseError(token.Line, "tag `%s` found outside of a file (`FileName`) section", token.Tag)
+		}
+		return handler(parser, token)
+	}
+	if handler, ok := snippetTags[token.Tag]; ok {
+		if parser.snippet == nil || parser.section != sectionSnippet {
+			return newParseError(token.Line, "tag `%s` found outside of a snippet (`SnippetSPDXID`) section", token.Tag)
+		}
+		return handler(parser.snippet, token)
+	}
+	if handler, ok := licenseTags[token.Tag]; ok {
+		if parser.license == nil || parser.section != sectionLicense {
+			return newParseError(token.Line, "tag `%s` found outside of a license (`LicenseID`) section", token.Tag)
+		}
+		handler(parser.license, token)
+		return nil
+	}
+
+	switch token.Tag {
+	case "RelationshipComment":
+		if parser.relationship == nil || parser.section != sectionRelationship {
+			return newParseError(token.Line, "tag `%s` must follow a `Relationship`", token.Tag)
+		}
+		parser.relationship.Comment = token.Value
+	case "AnnotationDate", "AnnotationType", "AnnotationComment", "SPDXREF":
+		if parser.annotation == nil || parser.section != sectionAnnotation {
+			return newParseError(token.Line, "tag `%s` found outside of an annotation (`Annotator`) section", token.Tag)
+		}
+		switch token.Tag {
+		case "AnnotationDate":
+			parser.annotation.AnnotationDate = token.Value
+		case "AnnotationType":
+			parser.annotation.AnnotationType = token.Value
+		case "AnnotationComment":
+			parser.annotation.Comment = token.Value
+		case "SPDXREF":
+			parser.annotation.SPDXElementID = token.Value
+		}
+	case "ReviewDate", "ReviewComment":
+		if parser.review == nil || parser.section != sectionReview {
+			return newParseError(token.Line, "tag `%s` found outside of a review (`Reviewer`) section", token.Tag)
+		}
+		if token.Tag == "ReviewDate" {
+			parser.review.ReviewDate = token.Value
+		} else {
+			parser.review.Comment = token.Value
+		}
+	default:
+		return newParseError(token.Line, "unknown tag: `%s`", token.Tag)
+	}
+	return nil
+}
+
+// Resolve references that can only be known once the whole document is read
+func (parser *tagValueParser) finish() {
+	// Files that followed a package are contained in that package
+	for _, file := range parser.document.Files {
+		if pkg, ok := parser.fileOwners[file]; ok && file.SPDXID != "" {
+			pkg.HasFiles = append(pkg.HasFiles, file.SPDXID)
+		}
+	}
+
+	// Snippet ranges may precede the "SnippetFromFileSPDXID" they refer to
+	for _, snippet := range parser.document.Snippets {
+		for _, snippetRange := range snippet.Ranges {
+			if snippetRange.StartPointer.Reference == "" {
+				snippetRange.StartPointer.Reference = snippet.SnippetFromFile
+				snippetRange.EndPointer.Reference = snippet.SnippetFromFile
+			}
+		}
+	}
+
+	// Move annotations to the (package, file or snippet) element they annotate
+	var documentAnnotations []*Annotation
+	for _, annotation := range parser.document.Annotations {
+		id := annotation.SPDXElementID
+		if pkg := parser.document.Package(id); pkg != nil {
+			pkg.Annotations = append(pkg.Annotations, annotation)
+		} else if file := parser.document.File(id); file != nil {
+			file.Annotations = append(file.Annotations, annotation)
+		} else if snippet := parser.document.Snippet(id); snippet != nil {
+			snippet.Annotations = append(snippet.Annotations, annotation)
+		} else {
+			documentAnnotations = append(documentAnnotations, annotation)
+		}
+	}
+	parser.document.Annotations = documentAnnotations
+}
+
+// ------------------------------------------------------------------------
+// Tag handlers (by section)
+// ------------------------------------------------------------------------
+
+var documentTags = map[string]func(*Document, Token) error{
+	"SPDXVersion":        func(d *Document, t Token) error { d.SPDXVersion = t.Value; return nil },
+	"DataLicense":        func(d *Document, t Token) error { d.DataLicense = t.Value; return nil },
+	"DocumentName":       func(d *Document, t Token) error { d.Name = t.Value; return nil },
+	"DocumentNamespace":  func(d *Document, t Token) error { d.DocumentNamespace = t.Value; return nil },
+	"DocumentComment":    func(d *Document, t Token) error { d.Comment = t.Value; return nil },
+	"LicenseListVersion": func(d *Document, t Token) error { d.CreationInfo.LicenseListVersion = t.Value; return nil },
+	"Creator": func(d *Document, t Token) error {
+		d.CreationInfo.Creators = append(d.CreationInfo.Creators, t.Value)
+		return nil
+	},
+	"Created":        func(d *Document, t Token) error { d.CreationInfo.Created = t.Value; return nil },
+	"CreatorComment": func(d *Document, t Token) error { d.CreationInfo.Comment = t.Value; return nil },
+	"ExternalDocumentRef": func(d *Document, t Token) error {
+		ref, err := parseExternalDocumentRef(t)
+		if err == nil {
+			d.ExternalDocumentRefs = append(d.ExternalDocumentRefs, ref)
+		}
+		return err
+	},
+}
+
+var packageTags = map[string]func(*tagValueParser, Token) error{
+	"PackageVersion":          func(p *tagValueParser, t Token) error { p.pkg.VersionInfo = t.Value; return nil },
+	"PackageFileName":         func(p *tagValueParser, t Token) error { p.pkg.PackageFileName = t.Value; return nil },
+	"PackageSupplier":         func(p *tagValueParser, t Token) error { p.pkg.Supplier = t.Value; return nil },
+	"PackageOriginator":       func(p *tagValueParser, t Token) error { p.pkg.Originator = t.Value; return nil },
+	"PackageDownloadLocation": func(p *tagValueParser, t Token) error { p.pkg.DownloadLocation = t.Value; return nil },
+	"PackageHomePage":         func(p *tagValueParser, t Token) error { p.pkg.Homepage = t.Value; return nil },
+	"PackageSourceInfo":       func(p *tagValueParser, t Token) error { p.pkg.SourceInfo = t.Value; return nil },
+	"PackageLicenseConcluded": func(p *tagValueParser, t Token) error { p.pkg.LicenseConcluded = t.Value; return nil },
+	"PackageLicenseDeclared":  func(p *tagValueParser, t Token) error { p.pkg.LicenseDeclared = t.Value; return nil },
+	"PackageLicenseComments":  func(p *tagValueParser, t Token) error { p.pkg.LicenseComments = t.Value; return nil },
+	"PackageCopyrightText":    func(p *tagValueParser, t Token) error { p.pkg.CopyrightText = t.Value; return nil },
+	"PackageSummary":          func(p *tagValueParser, t Token) error { p.pkg.Summary = t.Value; return nil },
+	"PackageDescription":      func(p *tagValueParser, t Token) error { p.pkg.Description = t.Value; return nil },
+	"PackageComment":          func(p *tagValueParser, t Token) error { p.pkg.Comment = t.Value; return nil },
+	"PrimaryPackagePurpose":   func(p *tagValueParser, t Token) error { p.pkg.PrimaryPackagePurpose = t.Value; return nil },
+	"ReleaseDate":             func(p *tagValueParser, t Token) error { p.pkg.ReleaseDate = t.Value; return nil },
+	"BuiltDate":               func(p *tagValueParser, t Token) error { p.pkg.BuiltDate = t.Value; return nil },
+	"ValidUntilDate":          func(p *tagValueParser, t Token) error { p.pkg.ValidUntilDate = t.Value; return nil },
+	"PackageLicenseInfoFromFiles": func(p *tagValueParser, t Token) error {
+		p.pkg.LicenseInfoFromFiles = append(p.pkg.LicenseInfoFromFiles, t.Value)
+		return nil
+	},
+	"PackageAttributionText": func(p *tagValueParser, t Token) error {
+		p.pkg.AttributionTexts = append(p.pkg.AttributionTexts, t.Value)
+		return nil
+	},
+	"FilesAnalyzed": func(p *tagValueParser, t Token) error {
+		analyzed, err := strconv.ParseBool(t.Value)
+		if err != nil {
+			return newParseError(t.Line, "invalid `FilesAnalyzed` value: `%s` (expected true or false)", t.Value)
+		}
+		p.pkg.FilesAnalyzed = &analyzed
+		return nil
+	},
+	"PackageVerificationCode": func(p *tagValueParser, t Token) error {
+		p.pkg.PackageVerificationCode = parseVerificationCode(t.Value)
+		return nil
+	},
+	"PackageChecksum": func(p *tagValueParser, t Token) error {
+		checksum, err := parseChecksum(t)
+		if err == nil {
+			p.pkg.Checksums = append(p.pkg.Checksums, checksum)
+		}
+		return err
+	},
+	"ExternalRef": func(p *tagValueParser, t Token) error {
+		fields := strings.Fields(t.Value)
+		if len(fields) != 3 {
+			return newParseError(t.Line, "invalid `ExternalRef`: `%s` (expected: <category> <type> <locator>)", t.Value)
+		}
+		p.externalRef = &ExternalRef{ReferenceCategory: fields[0], ReferenceType: fields[1], ReferenceLocator: fields[2]}
+		p.pkg.ExternalRefs = append(p.pkg.ExternalRefs, p.externalRef)
+		return nil
+	},
+	"ExternalRefComment": func(p *tagValueParser, t Token) error {
+		if p.externalRef == nil {
+			return newParseError(t.Line, "tag `ExternalRefComment` must follow an `ExternalRef`")
+		}
+		p.externalRef.Comment = t.Value
+		return nil
+	},
+}
+
+var fileTags = map[string]func(*tagValueParser, Token) error{
+	"FileType": func(p *tagValueParser, t Token) error {
+		p.file.FileTypes = append(p.file.FileTypes, t.Value)
+		return nil
+	},
+	"LicenseConcluded": func(p *tagValueParser, t Token) error { p.file.LicenseConcluded = t.Value; return nil },
+	"LicenseInfoInFile": func(p *tagValueParser, t Token) error {
+		p.file.LicenseInfoInFiles = append(p.file.LicenseInfoInFiles, t.Value)
+		return nil
+	},
+	"LicenseComments":   func(p *tagValueParser, t Token) error { p.file.LicenseComments = t.Value; return nil },
+	"FileCopyrightText": func(p *tagValueParser, t Token) error { p.file.CopyrightText = t.Value; return nil },
+	"FileComment":       func(p *tagValueParser, t Token) error { p.file.Comment = t.Value; return nil },
+	"FileNotice":        func(p *tagValueParser, t Token) error { p.file.NoticeText = t.Value; return nil },
+	"FileContributor": func(p *tagValueParser, t Token) error {
+		p.file.FileContributors = append(p.file.FileContributors, t.Value)
+		return nil
+	},
+	"FileAttributionText": func(p *tagValueParser, t Token) error {
+		p.file.AttributionTexts = append(p.file.AttributionTexts, t.Value)
+		return nil
+	},
+	"FileDependency": func(p *tagValueParser, t Token) error {
+		p.file.FileDependencies = append(p.file.FileDependencies, t.Value)
+		return nil
+	},
+	"FileChecksum": func(p *tagValueParser, t Token) error {
+		checksum, err := parseChecksum(t)
+		if err == nil {
+			p.file.Checksums = append(p.file.Checksums, checksum)
+		}
+		return err
+	},
+	"ArtifactOfProjectName": func(p *tagValueParser, t Token) error {
+		p.artifactOf = &ArtifactOf{Name: t.Value}
+		p.file.ArtifactOfs = append(p.file.ArtifactOfs, p.artifactOf)
+		return nil
+	},
+	"ArtifactOfProjectHomePage": func(p *tagValueParser, t Token) error {
+		if p.artifactOf == nil {
+			return newParseError(t.Line, "tag `%s` must follow an `ArtifactOfProjectName`", t.Tag)
+		}
+		p.artifactOf.HomePage = t.Value
+		return nil
+	},
+	"ArtifactOfProjectURI": func(p *tagValueParser, t Token) error {
+		if p.artifactOf == nil {
+			return newParseError(t.Line, "tag `%s` must follow an `ArtifactOfProjectName`", t.Tag)
+		}
+		p.artifactOf.URI = t.Value
+		return nil
+	},
+}
+
+var snippetTags = map[string]func(*Snippet, Token) error{
+	"SnippetName":             func(s *Snippet, t Token) error { s.Name = t.Value; return nil },
+	"SnippetFromFileSPDXID":   func(s *Snippet, t Token) error { s.SnippetFromFile = t.Value; return nil },
+	"SnippetLicenseConcluded": func(s *Snippet, t Token) error { s.LicenseConcluded = t.Value; return nil },
+	"LicenseInfoInSnippet": func(s *Snippet, t Token) error {
+		s.LicenseInfoInSnippets = append(s.LicenseInfoInSnippets, t.Value)
+		return nil
+	},
+	"SnippetLicenseComments": func(s *Snippet, t Token) error { s.LicenseComments = t.Value; return nil },
+	"SnippetCopyrightText":   func(s *Snippet, t Token) error { s.CopyrightText = t.Value; return nil },
+	"SnippetComment":         func(s *Snippet, t Token) error { s.Comment = t.Value; return nil },
+	"SnippetAttributionText": func(s *Snippet, t Token) error { s.AttributionTexts = append(s.AttributionTexts, t.Value); return nil },
+	"SnippetByteRange":       func(s *Snippet, t Token) error { return parseSnippetRange(s, t, false) },
+	"SnippetLineRange":       func(s *Snippet, t Token) error { return parseSnippetRange(s, t, true) },
+}
+
+var licenseTags = map[string]func(*ExtractedLicensingInfo, Token){
+	"ExtractedText":         func(l *ExtractedLicensingInfo, t Token) { l.ExtractedText = t.Value },
+	"LicenseName":           func(l *ExtractedLicensingInfo, t Token) { l.Name = t.Value },
+	"LicenseCrossReference": func(l *ExtractedLicensingInfo, t Token) { l.SeeAlsos = append(l.SeeAlsos, t.Value) },
+	"LicenseComment":        func(l *ExtractedLicensingInfo, t Token) { l.Comment = t.Value },
+}
+
+// ------------------------------------------------------------------------
+// Value parsers
+// ------------------------------------------------------------------------
+
+// e.g., "SHA1: d6a770ba38583ed4bb4525bd96e50461655d2758"
+func parseChecksum(token Token) (Checksum, error) {
+	algorithm, value, found := cut(token.Value, ":")
+	if !found || algorithm == "" || value == "" {
+		return Checksum{}, newParseError(token.Line, "invalid checksum: `%s` (expected: <algorithm>: <value>)", token.Value)
+	}
+	return Checksum{Algorithm: algorithm, ChecksumValue: value}, nil
+}
+
+// e.g., "DocumentRef-spdx-tool-1.2 http://spdx.org/spdxdocs/spdx-tools-v1.2-3F25... SHA1: d6a770ba..."
+func parseExternalDocumentRef(token Token) (ExternalDocumentRef, error) {
+	fields := strings.Fields(token.Value)
+	if len(fields) < 3 || !strings.HasPrefix(fields[0], DOCUMENT_REF_PREFIX) {
+		return ExternalDocumentRef{}, newParseError(token.Line,
+			"invalid `ExternalDocumentRef`: `%s` (expected: DocumentRef-<id> <namespace> <algorithm>: <checksum>)", token.Value)
+	}
+	checksum, err := parseChecksum(Token{Value: strings.Join(fields[2:], " "), Line: token.Line})
+	if err != nil {
+		return ExternalDocumentRef{}, err
+	}
+	return ExternalDocumentRef{ExternalDocumentId: fields[0], SpdxDocument: fields[1], Checksum: checksum}, nil
+}
+
+// e.g., "d6a770ba38583ed4bb4525bd96e50461655d2758 (excludes: ./package.spdx)"
+func parseVerificationCode(value string) *PackageVerificationCode {
+	code := &PackageVerificationCode{PackageVerificationCodeValue: value}
+	if open := strings.Index(value, "("); open >= 0 {
+		code.PackageVerificationCodeValue = strings.TrimSpace(value[:open])
+		excludes := strings.TrimSuffix(strings.TrimSpace(value[open+1:]), ")")
+		excludes = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(excludes), "excludes:"))
+		if excludes != "" {
+			code.PackageVerificationCodeExcludedFiles = []string{excludes}
+		}
+	}
+	return code
+}
+
+// e.g., "SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package"; the related element
+// may also be an external element ("DocumentRef-x:SPDXRef-y"), NONE or NOASSERTION
+func parseRelationship(token Token) (*Relationship, error) {
+	fields := strings.Fields(token.Value)
+	if len(fields) != 3 {
+		return nil, newParseError(token.Line, "invalid `Relationship`: `%s` (expected: <element> <type> <related element>)", token.Value)
+	}
+	return &Relationship{SPDXElementID: fields[0], RelationshipType: fields[1], RelatedSPDXElement: fields[2]}, nil
+}
+
+// e.g., "310:420" (byte offsets) or "5:23" (line numbers)
+func parseSnippetRange(snippet *Snippet, token Token, lines bool) error {
+	startValue, endValue, _ := cut(token.Value, ":")
+	start, err1 := strconv.Atoi(startValue)
+	end, err2 := strconv.Atoi(endValue)
+	if err1 != nil || err2 != nil {
+		return newParseError(token.Line, "invalid `%s`: `%s` (expected: <start>:<end>)", token.Tag, token.Value)
+	}
+
+	snippetRange := &SnippetRange{
+		StartPointer: SnippetPointer{Reference: snippet.SnippetFromFile},
+		EndPointer:   SnippetPointer{Reference: snippet.SnippetFromFile},
+	}
+	if lines {
+		snippetRange.StartPointer.LineNumber, snippetRange.EndPointer.LineNumber = &start, &end
+	} else {
+		snippetRange.StartPointer.Offset, snippetRange.EndPointer.Offset = &start, &end
+	}
+	snippet.Ranges = append(snippet.Ranges, snippetRange)
+	return nil
+}
+
+// Split around the first separator (trimming whitespace from both parts)
+func cut(value string, separator string) (before string, after string, found bool) {
+	if index := strings.Index(value, separator); index >= 0 {
+		return strings.TrimSpace(value[:index]), strings.TrimSpace(value[index+len(separator):]), true
+	}
+	return strings.TrimSpace(value), "", false
+}
+
+// ------------------------------------------------------------------------
+// Writer
+// ------------------------------------------------------------------------
+
+// WriteTagValue writes the document as SPDX tag-value. Files are written
+// after the (first) package that has them, which is how the parser assigns
+// them to packages; "documentDescribes" is written as DESCRIBES (and any
+// further "hasFiles", as CONTAINS) relationships. Properties without a
+// tag-value equivalent (e.g., "Extra" or license "crossRefs") are not written.
+func (document *Document) WriteTagValue(writer io.Writer) error {
+	w := &tagValueWriter{writer: bufio.NewWriter(writer)}
+	w.writeDocument(document)
+	if w.err != nil {
+		return w.err
+	}
+	return w.writer.Flush()
+}
+
+type tagValueWriter struct {
+	writer *bufio.Writer
+	err    error
+}
+
+// Write a (single-line) tag-value pair; empty values are omitted
+func (w *tagValueWriter) tag(tag string, value string) {
+	if value == "" || w.err != nil {
+		return
+	}
+	if strings.Contains(value, "\n") || value != strings.TrimSpace(value) {
+		value = TEXT_START + value + TEXT_END
+	}
+	_, w.err = fmt.Fprintf(w.writer, "%s: %s\n", tag, value)
+}
+
+// Write a free-form text value (always enclosed in <text>...</text>, unless NONE or NOASSERTION)
+func (w *tagValueWriter) text(tag string, value string) {
+	if value == "" || w.err != nil {
+		return
+	}
+	if value != NONE && value != NOASSERTION {
+		value = TEXT_START + value + TEXT_END
+	}
+	_, w.err = fmt.Fprintf(w.writer, "%s: %s\n", tag, value)
+}
+
+func (w *tagValueWriter) tags(tag string, values []string) {
+	for _, value := range values {
+		w.tag(tag, value)
+	}
+}
+
+func (w *tagValueWriter) comment(comment string) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.writer, "\n## %s\n\n", comment)
+	}
+}
+
+func (w *tagValueWriter) writeDocument(document *Document) {
+	w.comment("Document Information")
+	w.tag("SPDXVersion", document.SPDXVersion)
+	w.tag("DataLicense", document.DataLicense)
+	w.tag("SPDXID", document.SPDXID)
+	w.tag("DocumentName", document.Name)
+	w.tag("DocumentNamespace", document.DocumentNamespace)
+	for _, ref := range document.ExternalDocumentRefs {
+		w.tag("ExternalDocumentRef", fmt.Sprintf("%s %s %s: %s",
+			ref.ExternalDocumentId, ref.SpdxDocument, ref.Checksum.Algorithm, ref.Checksum.ChecksumValue))
+	}
+	w.text("DocumentComment", document.Comment)
+
+	w.comment("Creation Information")
+	w.tag("LicenseListVersion", document.CreationInfo.LicenseListVersion)
+	w.tags("Creator", document.CreationInfo.Creators)
+	w.tag("Created", document.CreationInfo.Created)
+	w.text("CreatorComment", document.CreationInfo.Comment)
+
+	// Files followed by a package would be assigned to it; write those
+	// without a package first and the others with their (first) package
+	owners := map[string]*Package{}
+	var contains []*Relationship
+	for _, pkg := range document.Packages {
+		for _, spdxId := range pkg.HasFiles {
+			if _, owned := owners[spdxId]; owned {
+				contains = append(contains, &Relationship{SPDXElementID: pkg.SPDXID, RelationshipType: RELATIONSHIP_CONTAINS, RelatedSPDXElement: spdxId})
+			} else {
+				owners[spdxId] = pkg
+			}
+		}
+	}
+	for _, file := range document.Files {
+		if owners[file.SPDXID] == nil {
+			w.writeFile(file)
+		}
+	}
+	for _, pkg := range document.Packages {
+		w.writePackage(pkg)
+		for _, file := range document.Files {
+			if owners[file.SPDXID] == pkg {
+				w.writeFile(file)
+			}
+		}
+	}
+
+	for _, snippet := range document.Snippets {
+		w.writeSnippet(snippet)
+	}
+	for _, license := range document.HasExtractedLicensingInfos {
+		w.comment("License Information")
+		w.tag("LicenseID", license.LicenseId)
+		w.text("ExtractedText", license.ExtractedText)
+		w.tag("LicenseName", license.Name)
+		w.tags("LicenseCrossReference", license.SeeAlsos)
+		w.text("LicenseComment", license.Comment)
+	}
+
+	// Relationships (including those implied by JSON-only properties)
+	var relationships []*Relationship
+	for _, spdxId := range document.DocumentDescribes {
+		if !hasRelationship(document.Relationships, document.SPDXID, RELATIONSHIP_DESCRIBES, spdxId) {
+			relationships = append(relationships, &Relationship{SPDXElementID: document.SPDXID, RelationshipType: RELATIONSHIP_DESCRIBES, RelatedSPDXElement: spdxId})
+		}
+	}
+	relationships = append(relationships, document.Relationships...)
+	for _, relationship := range contains {
+		if !hasRelationship(document.Relationships, relationship.SPDXElementID, relationship.RelationshipType, relationship.RelatedSPDXElement) {
+			relationships = append(relationships, relationship)
+		}
+	}
+	if len(relationships) > 0 {
+		w.comment("Relationships")
+	}
+	for _, relationship := range relationships {
+		w.tag("Relationship", fmt.Sprintf("%s %s %s", relationship.SPDXElementID, relationship.RelationshipType, relationship.RelatedSPDXElement))
+		w.text("RelationshipComment", relationship.Comment)
+	}
+
+	w.writeAnnotations(document.SPDXID, document.Annotations)
+	for _, pkg := range document.Packages {
+		w.writeAnnotations(pkg.SPDXID, pkg.Annotations)
+	}
+	for _, file := range document.Files {
+		w.writeAnnotations(file.SPDXID, file.Annotations)
+	}
+	for _, snippet := range document.Snippets {
+		w.writeAnnotations(snippet.SPDXID, snippet.Annotations)
+	}
+
+	for _, review := range document.Reviews {
+		w.comment("Review Information")
+		w.tag("Reviewer", review.Reviewer)
+		w.tag("ReviewDate", review.ReviewDate)
+		w.text("ReviewComment", review.Comment)
+	}
+}
+
+func hasRelationship(relationships []*Relationship, element string, relationshipType string, related string) bool {
+	for _, relationship := range relationships {
+		if relationship.SPDXElementID == element && relationship.RelationshipType == relationshipType &&
+			relationship.RelatedSPDXElement == related {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *tagValueWriter) writePackage(pkg *Package) {
+	w.comment("Package Information")
+	w.tag("PackageName", pkg.Name)
+	w.tag("SPDXID", pkg.SPDXID)
+	w.tag("PackageVersion", pkg.VersionInfo)
+	w.tag("PackageFileName", pkg.PackageFileName)
+	w.tag("PackageSupplier", pkg.Supplier)
+	w.tag("PackageOriginator", pkg.Originator)
+	w.tag("PackageDownloadLocation", pkg.DownloadLocation)
+	w.tag("PrimaryPackagePurpose", pkg.PrimaryPackagePurpose)
+	if pkg.FilesAnalyzed != nil {
+		w.tag("FilesAnalyzed", strconv.FormatBool(*pkg.FilesAnalyzed))
+	}
+	if code := pkg.PackageVerificationCode; code != nil {
+		value := code.PackageVerificationCodeValue
+		if len(code.PackageVerificationCodeExcludedFiles) > 0 {
+			value += fmt.Sprintf(" (excludes: %s)", strings.Join(code.PackageVerificationCodeExcludedFiles, ", "))
+		}
+		w.tag("PackageVerificationCode", value)
+	}
+	for _, checksum := range pkg.Checksums {
+		w.tag("PackageChecksum", checksum.Algorithm+": "+checksum.ChecksumValue)
+	}
+	w.tag("PackageHomePage", pkg.Homepage)
+	w.text("PackageSourceInfo", pkg.SourceInfo)
+	w.tag("PackageLicenseConcluded", pkg.LicenseConcluded)
+	w.tags("PackageLicenseInfoFromFiles", pkg.LicenseInfoFromFiles)
+	w.tag("PackageLicenseDeclared", pkg.LicenseDeclared)
+	w.text("PackageLicenseComments", pkg.LicenseComments)
+	w.text("PackageCopyrightText", pkg.CopyrightText)
+	w.text("PackageSummary", pkg.Summary)
+	w.text("PackageDescription", pkg.Description)
+	w.text("PackageComment", pkg.Comment)
+	for _, ref := range pkg.ExternalRefs {
+		w.tag("ExternalRef", fmt.Sprintf("%s %s %s", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator))
+		w.text("ExternalRefComment", ref.Comment)
+	}
+	for _, text := range pkg.AttributionTexts {
+		w.text("PackageAttributionText", text)
+	}
+	w.tag("ReleaseDate", pkg.ReleaseDate)
+	w.tag("BuiltDate", pkg.BuiltDate)
+	w.tag("ValidUntilDate", pkg.ValidUntilDate)
+}
+
+func (w *tagValueWriter) writeFile(file *File) {
+	w.comment("File Information")
+	w.tag("FileName", file.FileName)
+	w.tag("SPDXID", file.SPDXID)
+	w.tags("FileType", file.FileTypes)
+	for _, checksum := range file.Checksums {
+		w.tag("FileChecksum", checksum.Algorithm+": "+checksum.ChecksumValue)
+	}
+	w.tag("LicenseConcluded", file.LicenseConcluded)
+	w.tags("LicenseInfoInFile", file.LicenseInfoInFiles)
+	w.text("LicenseComments", file.LicenseComments)
+	w.text("FileCopyrightText", file.CopyrightText)
+	w.text("FileComment", file.Comment)
+	w.text("FileNotice", file.NoticeText)
+	w.tags("FileContributor", file.FileContributors)
+	for _, text := range file.AttributionTexts {
+		w.text("FileAttributionText", text)
+	}
+	w.tags("FileDependency", file.FileDependencies)
+	for _, artifactOf := range file.ArtifactOfs {
+		w.tag("ArtifactOfProjectName", artifactOf.Name)
+		w.tag("ArtifactOfProjectHomePage", artifactOf.HomePage)
+		w.tag("ArtifactOfProjectURI", artifactOf.URI)
+	}
+}
+
+func (w *tagValueWriter) writeSnippet(snippet *Snippet) {
+	w.comment("Snippet Information")
+	w.tag("SnippetSPDXID", snippet.SPDXID)
+	w.tag("SnippetFromFileSPDXID", snippet.SnippetFromFile)
+	for _, snippetRange := range snippet.Ranges {
+		start, end := snippetRange.StartPointer, snippetRange.EndPointer
+		if start.Offset != nil && end.Offset != nil {
+			w.tag("SnippetByteRange", fmt.Sprintf("%d:%d", *start.Offset, *end.Offset))
+		} else if start.LineNumber != nil && end.LineNumber != nil {
+			w.tag("SnippetLineRange", fmt.Sprintf("%d:%d", *start.LineNumber, *end.LineNumber))
+		}
+	}
+	w.tag("SnippetLicenseConcluded", snippet.LicenseConcluded)
+	w.tags("LicenseInfoInSnippet", snippet.LicenseInfoInSnippets)
+	w.text("SnippetLicenseComments", snippet.LicenseComments)
+	w.text("SnippetCopyrightText", snippet.CopyrightText)
+	w.text("SnippetComment", snippet.Comment)
+	w.tag("SnippetName", snippet.Name)
+	for _, text := range snippet.AttributionTexts {
+		w.text("SnippetAttributionText", text)
+	}
+}
+
+func (w *tagValueWriter) writeAnnotations(spdxId string, annotations []*Annotation) {
+	for _, annotation := range annotations {
+		w.comment("Annotations")
+		w.tag("Annotator", annotation.Annotator)
+		w.tag("AnnotationDate", annotation.AnnotationDate)
+		w.tag("AnnotationType", annotation.AnnotationType)
+		w.tag("SPDXREF", spdxId)
+		w.text("AnnotationComment", annotation.Comment)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseTagValueFile(t *testing.T, filename string) *Document {
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	document, err := ParseTagValue(file)
+	if err != nil {
+		t.Fatalf("%s: %v", filename, err)
+	}
+	return document
+}
+
+func TestParseTagValueExample23(t *testing.T) {
+	document := parseTagValueFile(t, "testdata/SPDXTagExample-v2.3.spdx")
+
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Equal(t, SPDXID_DOCUMENT, document.SPDXID)
+	assert.Equal(t, "SPDX-Tools-v2.0", document.Name)
+	assert.Equal(t, 3, len(document.CreationInfo.Creators))
+	assert.Equal(t, "This package has been shipped in source and binary form.\n"+
+		"The binaries were created with gcc 4.5.1 and expect to link to\n"+
+		"compatible system run time libraries.", document.CreationInfo.Comment)
+	assert.Equal(t, "SHA1", document.ExternalDocumentRefs[0].Checksum.Algorithm)
+	assert.Equal(t, "d6a770ba38583ed4bb4525bd96e50461655d2759", document.ExternalDocumentRefs[0].Checksum.ChecksumValue)
+
+	assert.Equal(t, 4, len(document.Files))
+	assert.Equal(t, 5, len(document.Packages))
+	assert.Equal(t, 5, len(document.HasExtractedLicensingInfos))
+	assert.Equal(t, 9, len(document.Relationships))
+	assert.Equal(t, 3, len(document.Annotations))
+
+	glibc := document.Package("SPDXRef-Package")
+	assert.NotNil(t, glibc)
+	assert.Equal(t, 3, len(glibc.Checksums))
+	assert.Equal(t, "d6a770ba38583ed4bb4525bd96e50461655d2758", glibc.PackageVerificationCode.PackageVerificationCodeValue)
+	assert.Equal(t, []string{"./package.spdx"}, glibc.PackageVerificationCode.PackageVerificationCodeExcludedFiles)
+	assert.Equal(t, 2, len(glibc.ExternalRefs))
+	assert.Equal(t, "This is the external ref for Acme", glibc.ExternalRefs[1].Comment)
+
+	centos := document.Package("SPDXRef-CentOS-7")
+	assert.Equal(t, "CONTAINER", centos.PrimaryPackagePurpose)
+	assert.True(t, *centos.FilesAnalyzed)
+
+	relationship := document.Relationships[1]
+	assert.Equal(t, "COPY_OF", relationship.RelationshipType)
+	assert.Equal(t, "DocumentRef-spdx-tool-1.2:SPDXRef-ToolsElement", relationship.RelatedSPDXElement)
+	assert.Equal(t, "A relationship comment", document.Relationships[0].Comment)
+}
+
+func TestParseTagValueExample22(t *testing.T) {
+	document := parseTagValueFile(t, "testdata/SPDXTagExample-v2.2.spdx")
+
+	assert.Equal(t, "SPDX-2.2", document.SPDXVersion)
+	assert.Equal(t, 1, len(document.Snippets))
+	snippet := document.Snippets[0]
+	assert.Equal(t, 2, len(snippet.Ranges))
+	for _, snippetRange := range snippet.Ranges {
+		assert.Equal(t, snippet.SnippetFromFile, snippetRange.StartPointer.Reference)
+	}
+}
+
+func TestParseTagValueHello(t *testing.T) {
+	document := parseTagValueFile(t, "testdata/hello.spdx")
+
+	pkg := document.Package("SPDXRef-Package-hello")
+	assert.NotNil(t, pkg)
+	// files that follow a package belong to that package
+	assert.Equal(t, []string{"SPDXRef-hello-binary", "SPDXRef-Makefile", "SPDXRef-hello-src"}, pkg.HasFiles)
+	assert.Equal(t, 3, len(document.File("SPDXRef-hello-binary").Checksums))
+}
+
+// The binary document of the spdx-examples "example6" (a Go binary built
+// from sources and libraries described in other, external, documents)
+func TestParseTagValueExample6Bin(t *testing.T) {
+	document := parseTagValueFile(t, "testdata/example6-bin.spdx")
+
+	assert.Equal(t, "hello-go-bin", document.Name)
+	assert.Equal(t, 3, len(document.CreationInfo.Creators))
+	assert.Equal(t, 2, len(document.ExternalDocumentRefs))
+	ref := document.ExternalDocumentRefs[0]
+	assert.Equal(t, "DocumentRef-hello-go-src", ref.ExternalDocumentId)
+	assert.Equal(t, "https://swinslow.net/spdx-examples/example6/hello-go-src-v2", ref.SpdxDocument)
+	assert.Equal(t, "SHA1", ref.Checksum.Algorithm)
+
+	pkg := document.Package("SPDXRef-Package-hello-go-bin")
+	assert.NotNil(t, pkg)
+	assert.Equal(t, []string{"SPDXRef-hello-go-binary"}, pkg.HasFiles)
+	binary := document.File("SPDXRef-hello-go-binary")
+	assert.Equal(t, 3, len(binary.Checksums))
+	assert.Equal(t, "GPL-3.0-or-later AND LicenseRef-Golang-BSD-plus-Patents", binary.LicenseConcluded)
+
+	// i.e., elements of either side of a relationship may be external
+	assert.Equal(t, 8, len(document.Relationships))
+	generated := document.Relationships[1]
+	assert.Equal(t, "GENERATED_FROM", generated.RelationshipType)
+	assert.Equal(t, "DocumentRef-hello-go-src:SPDXRef-hello-go-src", generated.RelatedSPDXElement)
+	tool := document.Relationships[3]
+	assert.Equal(t, "DocumentRef-go-lib:SPDXRef-Package-go-compiler", tool.SPDXElementID)
+	assert.Equal(t, "BUILD_TOOL_OF", tool.RelationshipType)
+
+	assert.Equal(t, 1, len(document.HasExtractedLicensingInfos))
+	license := document.HasExtractedLicensingInfos[0]
+	assert.Equal(t, 2, len(license.SeeAlsos))
+	assert.True(t, strings.HasSuffix(license.ExtractedText, "shall terminate as of the date such litigation is filed."))
+}
+
+// A (synthetic) document for the features example6-bin does not use, i.e.,
+// relationship comments, NONE and annotations of elements
+func TestParseTagValueExternalDocumentRefs(t *testing.T) {
+	document := parseTagValueFile(t, "testdata/external-document-refs.spdx")
+
+	assert.Equal(t, 2, len(document.ExternalDocumentRefs))
+	assert.Equal(t, "DocumentRef-go-lib", document.ExternalDocumentRefs[1].ExternalDocumentId)
+	assert.Equal(t, "DocumentRef-go-lib:SPDXRef-Package-go-runtime", document.Relationships[2].RelatedSPDXElement)
+	assert.Equal(t, NONE, document.Relationships[3].RelatedSPDXElement)
+	assert.True(t, strings.HasPrefix(document.Relationships[1].Comment, "The binary was generated"))
+
+	// annotations with an "SPDXREF" are moved to the element they annotate
+	assert.Equal(t, 0, len(document.Annotations))
+	binary := document.File("SPDXRef-hello-go-binary")
+	assert.Equal(t, 1, len(binary.Annotations))
+	assert.Equal(t, "REVIEW", binary.Annotations[0].AnnotationType)
+}
+
+func TestParseTagValueErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"SPDXVersion: SPDX-2.2\nnot a tag value pair\n", 2},
+		{"SPDXVersion: SPDX-2.2\n\nUnknownTag: value\n", 3},
+		{"SPDXVersion: SPDX-2.2\nPackageVersion: 1.0\n", 2},
+		{"PackageName: x\nFilesAnalyzed: maybe\n", 2},
+		{"PackageName: x\nPackageChecksum: SHA1\n", 2},
+		{"Relationship: SPDXRef-A DESCRIBES\n", 1},
+		{"DocumentComment: <text>never\nterminated\n", 1},
+		{"DocumentComment: <text>text</text> trailing\n", 1},
+		{"SnippetSPDXID: SPDXRef-Snippet\nSnippetByteRange: 1-2\n", 2},
+		{"PackageName: x\nFileName: ./y\nPackageVersion: 1.0\n", 3},
+		{"PackageName: x\nSnippetSPDXID: SPDXRef-Snippet\nPackageVersion: 1.0\n", 3},
+		{"PackageName: x\nRelationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-x\nPackageVersion: 1.0\n", 3},
+	}
+
+	for _, test := range tests {
+		_, err := ParseTagValue(strings.NewReader(test.input))
+		var parseError *ParseError
+		if assert.True(t, errors.As(err, &parseError), test.input) {
+			assert.Equal(t, test.line, parseError.Line, test.input)
+		}
+	}
+}
+
+// Writing, then re-parsing, a tag-value document must result in the same document
+func TestWriteTagValueRoundTrip(t *testing.T) {
+	for _, filename := range []string{
+		"testdata/SPDXTagExample-v2.2.spdx",
+		"testdata/SPDXTagExample-v2.3.spdx",
+		"testdata/hello.spdx",
+		"testdata/example6-bin.spdx",
+		"testdata/external-document-refs.spdx",
+	} {
+		document := parseTagValueFile(t, filename)
+		// SPDXREF is required (i.e., always written), but not by the parser
+		for _, annotation := range document.Annotations {
+			if annotation.SPDXElementID == "" {
+				annotation.SPDXElementID = SPDXID_DOCUMENT
+			}
+		}
+
+		var output bytes.Buffer
+		assert.NoError(t, document.WriteTagValue(&output))
+		actual, err := ParseTagValue(&output)
+		if assert.NoError(t, err, filename) {
+			assert.Equal(t, document, actual, filename)
+		}
+	}
+}
+
+// JSON-only properties are written as their tag-value equivalents
+func TestWriteTagValueFromJSON(t *testing.T) {
+	file, err := os.Open("testdata/SPDXJSONExample-v2.3.spdx.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	document, err := ParseJSON(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	assert.NoError(t, document.WriteTagValue(&output))
+	actual, err := ParseTagValue(&output)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, actual.Check())
+	assert.Equal(t, len(document.Packages), len(actual.Packages))
+	assert.Equal(t, len(document.Files), len(actual.Files))
+	for _, spdxId := range document.DocumentDescribes {
+		assert.True(t, hasRelationship(actual.Relationships, SPDXID_DOCUMENT, RELATIONSHIP_DESCRIBES, spdxId), spdxId)
+	}
+	assert.Equal(t, document.Package("SPDXRef-Package").HasFiles, actual.Package("SPDXRef-Package").HasFiles)
+	assert.Equal(t, document.Package("SPDXRef-Package").Annotations[0].Comment, actual.Package("SPDXRef-Package").Annotations[0].Comment)
+}
@@ -0,0 +1,176 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spdx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SPDX YAML is a direct encoding of the SPDX JSON data model; documents are
+// converted to (or from) JSON so that the same model (and schema) applies.
+
+// ParseYAML reads an SPDX 2.2 or 2.3 YAML document
+func ParseYAML(reader io.Reader) (*Document, error) {
+	buffer, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	data, err := YAMLToJSON(buffer)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSON(bytes.NewReader(data))
+}
+
+// WriteYAML writes the document as SPDX YAML (properties in JSON order)
+func (document *Document) WriteYAML(writer io.Writer) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML; decoding it as a node tree preserves property order
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	setBlockStyle(&node)
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err = encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// YAMLToJSON converts a YAML document to its equivalent JSON
+func YAMLToJSON(buffer []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(buffer, &node); err != nil {
+		return nil, err
+	}
+	value, err := nodeToJSON(&node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Convert a YAML node to a value that encodes to the equivalent JSON.
+// Note: scalars are converted by their (resolved) tag and not decoded into
+// Go types so that, for example, timestamps remain (unchanged) strings.
+func nodeToJSON(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return nodeToJSON(node.Content[0])
+	case yaml.AliasNode:
+		return nodeToJSON(node.Alias)
+	case yaml.SequenceNode:
+		values := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := nodeToJSON(child)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case yaml.MappingNode:
+		object := orderedObject{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: unsupported (non-scalar) mapping key", key.Line)
+			}
+			value, err := nodeToJSON(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, property{key.Value, value})
+		}
+		return object, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool":
+			var value bool
+			err := node.Decode(&value)
+			return value, err
+		case "!!int", "!!float":
+			// keep the literal (e.g., large integers) unless not valid JSON
+			if json.Valid([]byte(node.Value)) {
+				return json.Number(node.Value), nil
+			}
+			var value float64
+			err := node.Decode(&value)
+			return value, err
+		default:
+			return node.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// A JSON object that retains the order of its properties
+type property struct {
+	name  string
+	value interface{}
+}
+
+type orderedObject []property
+
+func (object orderedObject) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, property := range object {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		name, _ := json.Marshal(property.name)
+		value, err := json.Marshal(property.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// Clear the (JSON) flow styles so that the encoder emits block YAML;
+// quoting is still applied wherever a string would otherwise resolve
+// to another type (e.g., "1.0" or "true")
+func setBlockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		setBlockStyle(child)
+	}
+}
@@ -19,7 +19,13 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // TODO: What is the value of this wrapper method over basic encoder?
@@ -28,29 +34,277 @@ func ConvertMapToJson(name string, mapIn interface{}) ([]byte, error) {
 	return strMapOut, err
 }
 
-// TODO: function NOT complete, only placeholder type switch
-func ConvertAnyToAny(values ...interface{}) {
-	//fmt.Printf("values=%v\n", values)
-	for index, value := range values {
-		fmt.Printf("value[%d] (%T): %+v\n", index, value, value)
-		switch t := value.(type) {
-		case int:
-		case uint:
-		case int32:
-		case int64:
-		case uint64:
-			fmt.Println("Type is an integer:", t)
-		case float32:
-		case float64:
-			fmt.Println("Type is a float:", t)
-		case string:
-			fmt.Println("Type is a string:", t)
-		case nil:
-			fmt.Println("Type is nil.")
-		case bool:
-			fmt.Println("Type is a bool:", t)
-		default:
-			fmt.Printf("Type is unknown!: %v\n", t)
+// ------------------------------------------------------------------------
+// Conversion of (decoded JSON or YAML) values, e.g., to compare SBOM
+// property values regardless of how they were encoded ("1.5" vs. 1.5)
+// ------------------------------------------------------------------------
+
+// Conversion errors (wrapped by ConversionError); e.g., errors.Is(err, ErrOverflow)
+var (
+	ErrNil         = errors.New("nil value")
+	ErrUnsupported = errors.New("unsupported type")
+	ErrFormat      = errors.New("invalid format")
+	ErrOverflow    = errors.New("value out of range")
+	ErrTruncated   = errors.New("value would be truncated")
+)
+
+// Time (string) layouts accepted by ToTime(); those without a zone are UTC
+var TimeLayouts = []string{
+	time.RFC3339Nano, // also matches time.RFC3339
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Values that cannot be converted are reported with the target type
+type ConversionError struct {
+	Value  interface{}
+	Target string // e.g., "int64"
+	Err    error  // one of the ErrXxx conversion errors
+}
+
+func (err *ConversionError) Error() string {
+	return fmt.Sprintf("unable to convert `%v` (%T) to %s: %s", err.Value, err.Value, err.Target, err.Err)
+}
+
+func (err *ConversionError) Unwrap() error { return err.Err }
+
+func newConversionError(value interface{}, target string, err error) *ConversionError {
+	return &ConversionError{Value: value, Target: target, Err: err}
+}
+
+// ConvertAnyToAny converts the value to the type the target points to
+// (i.e., *string, *int64, *float64, *bool or *time.Time); the target is
+// left unchanged if the value cannot be converted. e.g.,
+//
+//	var count int64
+//	err := ConvertAnyToAny(properties["count"], &count)
+func ConvertAnyToAny(value interface{}, target interface{}) error {
+	switch typed := target.(type) {
+	case *string:
+		return assign(typed, ToString, value)
+	case *int64:
+		return assign(typed, ToInt64, value)
+	case *float64:
+		return assign(typed, ToFloat64, value)
+	case *bool:
+		return assign(typed, ToBool, value)
+	case *time.Time:
+		return assign(typed, ToTime, value)
+	}
+	return newConversionError(value, fmt.Sprintf("%T", target), ErrUnsupported)
+}
+
+func assign[T any](target *T, convert func(interface{}) (T, error), value interface{}) error {
+	converted, err := convert(value)
+	if err == nil {
+		*target = converted
+	}
+	return err
+}
+
+// ToString converts (the scalar) value to a string; numbers are formatted
+// without exponents (e.g., 1e6 as "1000000") and times as RFC 3339
+func ToString(value interface{}) (string, error) {
+	indirect, ok := indirect(value)
+	if !ok {
+		return "", newConversionError(value, "string", ErrNil)
+	}
+	switch typed := indirect.Interface().(type) {
+	case time.Time:
+		return typed.Format(time.RFC3339Nano), nil
+	case []byte:
+		return string(typed), nil
+	case fmt.Stringer:
+		return typed.String(), nil
+	case error:
+		return typed.Error(), nil
+	}
+
+	switch indirect.Kind() {
+	case reflect.String:
+		return indirect.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(indirect.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(indirect.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(indirect.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(indirect.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(indirect.Float(), 'f', -1, 64), nil
+	}
+	return "", newConversionError(value, "string", ErrUnsupported)
+}
+
+// ToInt64 converts the value to an int64; floats (and strings) must be
+// whole numbers (e.g., 2.0 or "1e3") within range
+func ToInt64(value interface{}) (int64, error) {
+	indirect, ok := indirect(value)
+	if !ok {
+		return 0, newConversionError(value, "int64", ErrNil)
+	}
+
+	switch indirect.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return indirect.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if indirect.Uint() > math.MaxInt64 {
+			return 0, newConversionError(value, "int64", ErrOverflow)
+		}
+		return int64(indirect.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		integer, err := floatToInt64(indirect.Float())
+		if err != nil {
+			return 0, newConversionError(value, "int64", err)
+		}
+		return integer, nil
+	case reflect.String:
+		// e.g., json.Number
+		text := strings.TrimSpace(indirect.String())
+		integer, err := strconv.ParseInt(text, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, newConversionError(value, "int64", ErrOverflow)
+		} else if err != nil {
+			var float float64
+			if float, err = parseFloat(text); err == nil {
+				integer, err = floatToInt64(float)
+			}
 		}
+		if err != nil {
+			return 0, newConversionError(value, "int64", err)
+		}
+		return integer, nil
+	}
+	return 0, newConversionError(value, "int64", ErrUnsupported)
+}
+
+// ToFloat64 converts the value to a float64 (integers beyond 2^53 lose precision)
+func ToFloat64(value interface{}) (float64, error) {
+	indirect, ok := indirect(value)
+	if !ok {
+		return 0, newConversionError(value, "float64", ErrNil)
+	}
+
+	switch indirect.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(indirect.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(indirect.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return indirect.Float(), nil
+	case reflect.String:
+		float, err := parseFloat(strings.TrimSpace(indirect.String()))
+		if err != nil {
+			return 0, newConversionError(value, "float64", err)
+		}
+		return float, nil
+	}
+	return 0, newConversionError(value, "float64", ErrUnsupported)
+}
+
+// ToBool converts the value to a bool; strings may be any of "true",
+// "yes", "on", "1" (or "t", "y"), their opposites, regardless of case;
+// numbers must be 0 or 1
+func ToBool(value interface{}) (bool, error) {
+	indirect, ok := indirect(value)
+	if !ok {
+		return false, newConversionError(value, "bool", ErrNil)
+	}
+
+	switch indirect.Kind() {
+	case reflect.Bool:
+		return indirect.Bool(), nil
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(indirect.String())) {
+		case "true", "t", "yes", "y", "on", "1":
+			return true, nil
+		case "false", "f", "no", "n", "off", "0":
+			return false, nil
+		}
+		return false, newConversionError(value, "bool", ErrFormat)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		number, _ := ToFloat64(indirect.Interface())
+		if number != 0 && number != 1 {
+			return false, newConversionError(value, "bool", ErrOverflow)
+		}
+		return number == 1, nil
+	}
+	return false, newConversionError(value, "bool", ErrUnsupported)
+}
+
+// ToTime converts the value to a time.Time; strings must match one of the
+// TimeLayouts and numbers are (Unix) seconds since the epoch, e.g., 1.5e9
+func ToTime(value interface{}) (time.Time, error) {
+	indirect, ok := indirect(value)
+	if !ok {
+		return time.Time{}, newConversionError(value, "time.Time", ErrNil)
+	}
+	if typed, ok := indirect.Interface().(time.Time); ok {
+		return typed, nil
+	}
+
+	switch indirect.Kind() {
+	case reflect.String:
+		text := strings.TrimSpace(indirect.String())
+		for _, layout := range TimeLayouts {
+			if parsed, err := time.ParseInLocation(layout, text, time.UTC); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, newConversionError(value, "time.Time", ErrFormat)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		seconds, err := ToInt64(indirect.Interface())
+		if err != nil {
+			return time.Time{}, newConversionError(value, "time.Time", ErrOverflow)
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	case reflect.Float32, reflect.Float64:
+		float := indirect.Float()
+		seconds, err := floatToInt64(math.Floor(float))
+		if err != nil {
+			return time.Time{}, newConversionError(value, "time.Time", err)
+		}
+		nanoseconds := int64(math.Round((float - math.Floor(float)) * float64(time.Second)))
+		return time.Unix(seconds, nanoseconds).UTC(), nil
+	}
+	return time.Time{}, newConversionError(value, "time.Time", ErrUnsupported)
+}
+
+// Follow pointers (and interfaces); false if (any is) nil
+func indirect(value interface{}) (reflect.Value, bool) {
+	indirect := reflect.ValueOf(value)
+	for indirect.Kind() == reflect.Ptr || indirect.Kind() == reflect.Interface {
+		if indirect.IsNil() {
+			return indirect, false
+		}
+		indirect = indirect.Elem()
+	}
+	return indirect, indirect.IsValid()
+}
+
+func parseFloat(text string) (float64, error) {
+	float, err := strconv.ParseFloat(text, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, ErrOverflow
+	} else if err != nil {
+		return 0, ErrFormat
+	}
+	return float, nil
+}
+
+func floatToInt64(float float64) (int64, error) {
+	if math.IsNaN(float) {
+		return 0, ErrFormat
+	} else if float < math.MinInt64 || float >= math.MaxInt64 {
+		// Note: float64(math.MaxInt64) is 2^63 (i.e., out of range)
+		return 0, ErrOverflow
+	} else if float != math.Trunc(float) {
+		return 0, ErrTruncated
 	}
+	return int64(float), nil
 }
@@ -0,0 +1,129 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestConvert(t *testing.T) {
+	text := "42"
+	epoch := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	tests := []struct {
+		value    interface{}
+		convert  func(interface{}) (interface{}, error)
+		expected interface{}
+		err      error
+	}{
+		// ToString
+		{"abc", toString, "abc", nil},
+		{1.5e6, toString, "1500000", nil},
+		{float32(0.1), toString, "0.1", nil},
+		{uint8(7), toString, "7", nil},
+		{true, toString, "true", nil},
+		{&text, toString, "42", nil},
+		{json.Number("1e3"), toString, "1e3", nil},
+		{epoch, toString, "2023-11-14T22:13:20Z", nil},
+		{nil, toString, "", ErrNil},
+		{[]int{1}, toString, "", ErrUnsupported},
+		// ToInt64
+		{42, toInt64, int64(42), nil},
+		{2.0, toInt64, int64(2), nil},
+		{" 42 ", toInt64, int64(42), nil},
+		{"1e3", toInt64, int64(1000), nil},
+		{json.Number("-7"), toInt64, int64(-7), nil},
+		{uint64(math.MaxUint64), toInt64, int64(0), ErrOverflow},
+		{"9223372036854775808", toInt64, int64(0), ErrOverflow},
+		{1e19, toInt64, int64(0), ErrOverflow},
+		{1.5, toInt64, int64(0), ErrTruncated},
+		{"1.5", toInt64, int64(0), ErrTruncated},
+		{"forty-two", toInt64, int64(0), ErrFormat},
+		{true, toInt64, int64(0), ErrUnsupported},
+		// ToFloat64
+		{"1.5", toFloat64, 1.5, nil},
+		{int64(-3), toFloat64, -3.0, nil},
+		{"1e400", toFloat64, 0.0, ErrOverflow},
+		{"1.5.0", toFloat64, 0.0, ErrFormat},
+		// ToBool
+		{"Yes", toBool, true, nil},
+		{"off", toBool, false, nil},
+		{1, toBool, true, nil},
+		{0.0, toBool, false, nil},
+		{2, toBool, false, ErrOverflow},
+		{"maybe", toBool, false, ErrFormat},
+		// ToTime
+		{"2023-11-14T22:13:20Z", toTime, epoch, nil},
+		{"2023-11-15T00:13:20+02:00", toTime, epoch, nil},
+		{"2023-11-14 22:13:20", toTime, epoch, nil},
+		{"2023-11-14", toTime, time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC), nil},
+		{1700000000, toTime, epoch, nil},
+		{1700000000.5, toTime, epoch.Add(500 * time.Millisecond), nil},
+		{"14/11/2023", toTime, time.Time{}, ErrFormat},
+		{1e300, toTime, time.Time{}, ErrOverflow},
+	}
+	for _, test := range tests {
+		actual, err := test.convert(test.value)
+		if test.err != nil {
+			assert.True(t, errors.Is(err, test.err), "%v: %v", test.value, err)
+			var conversionError *ConversionError
+			assert.True(t, errors.As(err, &conversionError), "%v", test.value)
+		} else {
+			assert.NoError(t, err, "%v", test.value)
+		}
+		if expected, ok := test.expected.(time.Time); ok {
+			assert.True(t, expected.Equal(actual.(time.Time)), "%v: %v", test.value, actual)
+		} else {
+			assert.Equal(t, test.expected, actual, "%v", test.value)
+		}
+	}
+}
+
+func TestConvertAnyToAny(t *testing.T) {
+	// e.g., an SBOM property, decoded as JSON and as YAML
+	var decoded map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"count": 3, "enabled": "true"}`), &decoded))
+	var count int64
+	assert.NoError(t, ConvertAnyToAny(decoded["count"], &count))
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, yaml.Unmarshal([]byte("count: \"4\"\nenabled: true\n"), &decoded))
+	assert.NoError(t, ConvertAnyToAny(decoded["count"], &count))
+	assert.Equal(t, int64(4), count)
+	var enabled bool
+	assert.NoError(t, ConvertAnyToAny(decoded["enabled"], &enabled))
+	assert.True(t, enabled)
+
+	// i.e., unchanged on error
+	err := ConvertAnyToAny("many", &count)
+	assert.EqualError(t, err, "unable to convert `many` (string) to int64: invalid format")
+	assert.Equal(t, int64(4), count)
+	var unsupported int
+	assert.True(t, errors.Is(ConvertAnyToAny(1, &unsupported), ErrUnsupported))
+}
+
+func toString(value interface{}) (interface{}, error)  { return ToString(value) }
+func toInt64(value interface{}) (interface{}, error)   { return ToInt64(value) }
+func toFloat64(value interface{}) (interface{}, error) { return ToFloat64(value) }
+func toBool(value interface{}) (interface{}, error)    { return ToBool(value) }
+func toTime(value interface{}) (interface{}, error)    { return ToTime(value) }
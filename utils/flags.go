@@ -32,12 +32,30 @@ type MyFlags struct {
 	ExecDir    string
 
 	// persistent flags (common to all commands)
-	Trace        bool // trace logging
-	Debug        bool // debug logging
-	InputFile    string
-	InputFormat  string
-	OutputFile   string
-	OutputFormat string
+	Trace         bool // trace logging
+	Debug         bool // debug logging
+	InputFile     string
+	InputFormat   string
+	OutputFile    string
+	OutputFormat  string
+	LogFormat     string
+	Color         string
+	LogFile       string // rotated (see LogMaxSize and LogMaxBackups) log file
+	LogMaxSize    int    // in megabytes
+	LogMaxBackups int
+	LogCompress   bool // gzip rotated log files
+
+	// validate flags
+	SchemaFile   string
+	ReportFormat string
+
+	// convert flags
+	SpecVersion string
+
+	// list flags (shared by all list commands)
+	ListFormat  string
+	ListColumns []string
+	ListSort    []string
 }
 
 var Flags MyFlags